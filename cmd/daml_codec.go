@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/smartcontractkit/go-daml/codegen/damlparser"
+	"github.com/smartcontractkit/go-daml/codegen/damltemplate"
+)
+
+func newDamlCodecCommand() *cobra.Command {
+	var configFile, output string
+	cmd := &cobra.Command{
+		Use:   "daml-codec --output <dir> [--config <file>] <Types.daml>...",
+		Short: "Generate Daml encode/decode modules from Daml type definitions",
+		Long: `Generates Daml codec modules encoding and decoding the records and variants of *Types.daml sources.
+
+The optional YAML or JSON config sets the custom codecs of types, the tag bytes of variant constructors
+and the types to generate codecs for. The codec module of MyModule.Types is MyModule.Codec, written to
+<output>/MyModule/Codec.daml, unless moduleName is set in the config.`,
+		Example: `  godaml daml-codec --output ./daml ./daml/MyModule/Types.daml
+  godaml daml-codec --config codec.yaml --output ./daml ./daml/A/Types.daml ./daml/B/Types.daml`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDamlCodec(args, configFile, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&configFile, "config", "", "YAML or JSON codec config")
+	cmd.Flags().StringVar(&output, "output", "", "output directory where generated Daml modules will be saved (required)")
+	cmd.MarkFlagRequired("output")
+
+	return cmd
+}
+
+func runDamlCodec(sources []string, configFile, outputDir string) error {
+	config, err := loadDamlCodecConfig(configFile)
+	if err != nil {
+		return err
+	}
+	if len(sources) > 1 && (config.ModuleName != "" || config.TypesModule != "") {
+		return fmt.Errorf("moduleName and typesModule can only be configured when generating a single module")
+	}
+
+	for _, source := range sources {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return fmt.Errorf("failed to read Daml source '%s': %w", source, err)
+		}
+
+		module, err := damlparser.Parse(bytes.NewReader(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse Daml source '%s': %w", source, err)
+		}
+
+		moduleConfig := config
+		if moduleConfig.TypesModule == "" {
+			moduleConfig.TypesModule = module.ModuleName
+		}
+		if moduleConfig.TypesModule == "" {
+			return fmt.Errorf("no module declaration in Daml source '%s'", source)
+		}
+		if moduleConfig.ModuleName == "" {
+			moduleConfig.ModuleName = codecModuleName(moduleConfig.TypesModule)
+		}
+
+		code, err := damltemplate.Generate(module, moduleConfig)
+		if err != nil {
+			return fmt.Errorf("failed to generate codec for '%s': %w", source, err)
+		}
+
+		outputFile := filepath.Join(outputDir, filepath.FromSlash(strings.ReplaceAll(moduleConfig.ModuleName, ".", "/"))+".daml")
+		if err := os.MkdirAll(filepath.Dir(outputFile), 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory '%s': %w", filepath.Dir(outputFile), err)
+		}
+		if err := os.WriteFile(outputFile, []byte(code), 0o644); err != nil {
+			return fmt.Errorf("failed to write file '%s': %w", outputFile, err)
+		}
+
+		log.Info().Msgf("successfully generated: %s", outputFile)
+	}
+
+	return nil
+}
+
+func loadDamlCodecConfig(configFile string) (damltemplate.DamlCodecConfig, error) {
+	var config damltemplate.DamlCodecConfig
+	if configFile == "" {
+		return config, nil
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return config, fmt.Errorf("failed to read config '%s': %w", configFile, err)
+	}
+
+	if strings.EqualFold(filepath.Ext(configFile), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&config)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&config)
+	}
+	if err != nil && err != io.EOF {
+		return config, fmt.Errorf("failed to parse config '%s': %w", configFile, err)
+	}
+
+	return config, nil
+}
+
+// codecModuleName derives the name of the codec module from the name of the types module,
+// e.g. MyModule.Types becomes MyModule.Codec and Test.TestTypes becomes Test.TestCodec.
+func codecModuleName(typesModule string) string {
+	return strings.TrimSuffix(typesModule, "Types") + "Codec"
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testTypesSource = `module Test.PoolTypes where
+
+import DA.Crypto.Text (BytesHex)
+
+data ChainUpdate = ChainUpdate
+    with
+        remoteChainSelector : Numeric 0
+        remotePools : [BytesHex]
+    deriving (Eq, Show)
+
+data TransferTimeout
+    = Indefinite
+    | RelativeHours Int
+    deriving (Eq, Show)
+`
+
+func TestRunDamlCodec(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "Types.daml")
+	require.NoError(t, os.WriteFile(source, []byte(testTypesSource), 0o644))
+
+	yamlConfig := filepath.Join(dir, "codec.yaml")
+	require.NoError(t, os.WriteFile(yamlConfig, []byte(`
+variantTagBytes:
+  TransferTimeout:
+    Indefinite: 0
+    RelativeHours: 7
+targetTypes: [TransferTimeout]
+`), 0o644))
+
+	output := filepath.Join(dir, "out")
+	require.NoError(t, runDamlCodec([]string{source}, yamlConfig, output))
+
+	code, err := os.ReadFile(filepath.Join(output, "Test", "PoolCodec.daml"))
+	require.NoError(t, err)
+	require.Contains(t, string(code), "module Test.PoolCodec where")
+	require.Contains(t, string(code), "encodeUint8 7")
+	require.NotContains(t, string(code), "encodeChainUpdate")
+
+	jsonConfig := filepath.Join(dir, "codec.json")
+	require.NoError(t, os.WriteFile(jsonConfig, []byte(`{"moduleName": "Custom.Codec"}`), 0o644))
+	require.NoError(t, runDamlCodec([]string{source}, jsonConfig, output))
+	code, err = os.ReadFile(filepath.Join(output, "Custom", "Codec.daml"))
+	require.NoError(t, err)
+	require.Contains(t, string(code), "encodeChainUpdate")
+
+	require.ErrorContains(t, runDamlCodec([]string{source, source}, jsonConfig, output), "single module")
+
+	require.NoError(t, os.WriteFile(yamlConfig, []byte("variantTags: {}\n"), 0o644))
+	require.ErrorContains(t, runDamlCodec([]string{source}, yamlConfig, output), "failed to parse config")
+}
+
+func TestCodecModuleName(t *testing.T) {
+	require.Equal(t, "MyModule.Codec", codecModuleName("MyModule.Types"))
+	require.Equal(t, "Test.TestCodec", codecModuleName("Test.TestTypes"))
+	require.Equal(t, "Test.PoolCodec", codecModuleName("Test.Pool"))
+}
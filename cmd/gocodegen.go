@@ -0,0 +1,268 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/smartcontractkit/go-daml/codegen"
+	model2 "github.com/smartcontractkit/go-daml/codegen/model"
+)
+
+type goOptions struct {
+	configFile   string
+	darFiles     []string
+	output       string
+	debug        bool
+	pkg          string
+	goImportPath string
+	hexEncoder   bool
+}
+
+func (o *goOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.configFile, "config", "", "path to a godaml.yaml codegen config, overridden by the other flags")
+	cmd.Flags().StringArrayVar(&o.darFiles, "dar", nil, "path to the DAR file (required), repeat to generate a Go package per Daml package of several DARs")
+	cmd.Flags().StringVar(&o.output, "output", "", "output directory where generated Go files will be saved (required)")
+	cmd.Flags().StringVar(&o.pkg, "go_package", "", "Go package name for generated code (required for a single DAR)")
+	cmd.Flags().StringVar(&o.goImportPath, "go_import_path", "", "Go import path of the output directory (required for several DARs)")
+	cmd.Flags().BoolVar(&o.debug, "debug", false, "enable debug logging")
+	cmd.Flags().BoolVar(&o.hexEncoder, "hex-encoder", false, "generate MarshalHex/UnmarshalHex methods for Canton MCMS codec")
+}
+
+func (o *goOptions) run() error {
+	config := &codegen.Config{}
+	if o.configFile != "" {
+		var err error
+		if config, err = codegen.LoadConfig(o.configFile); err != nil {
+			return err
+		}
+	}
+
+	darFiles := o.darFiles
+	if len(darFiles) == 0 {
+		darFiles = config.DarFiles()
+	}
+	output := cmp.Or(o.output, config.Output)
+	pkg := cmp.Or(o.pkg, config.GoPackage)
+	goImportPath := cmp.Or(o.goImportPath, config.GoImportPath)
+	if len(darFiles) == 0 {
+		return fmt.Errorf("--dar parameter is required")
+	}
+	if output == "" {
+		return fmt.Errorf("--output parameter is required")
+	}
+	multiPackage := len(darFiles) > 1 || goImportPath != ""
+	if multiPackage {
+		if goImportPath == "" {
+			return fmt.Errorf("--go_import_path parameter is required when generating several DARs")
+		}
+		if pkg != "" {
+			return fmt.Errorf("--go_package cannot be used with --go_import_path, Go packages are named after the Daml packages")
+		}
+	} else if pkg == "" {
+		return fmt.Errorf("--go_package parameter is required")
+	}
+
+	fieldHints, err := config.GetFieldHints()
+	if err != nil {
+		return err
+	}
+	externalPackages, err := config.GetExternalPackages()
+	if err != nil {
+		return err
+	}
+
+	generateHexCodec := o.hexEncoder || config.HexEncoder
+	if multiPackage {
+		return runMultiCodeGen(darFiles, output, goImportPath, o.debug, generateHexCodec, externalPackages, fieldHints)
+	}
+	return runCodeGen(darFiles[0], output, pkg, o.debug, generateHexCodec, externalPackages, fieldHints)
+}
+
+func newGoCommand() *cobra.Command {
+	opts := &goOptions{}
+	cmd := &cobra.Command{
+		Use:   "go [--config <godaml.yaml>] --dar <path> --output <dir> --go_package <name> [--debug]",
+		Short: "Generate Go code from a DAR file",
+		Long: `Generates Go code from DAML (.dar) files.
+
+Extracts DAML definitions from .dar archives and generates corresponding Go structs and types.
+
+Given several DARs, or a Go import path, generates a Go package for each Daml package of the DARs
+in <output>/<package name>, so that the types of libraries shared by the DARs are generated once.
+References to the types of another Daml package import its Go package from <go_import_path>/<package name>.
+
+The optional YAML config sets the DARs, output and Go package, the field hints of the hex codec
+and the Go packages that external Daml packages are imported from. Flags take precedence over
+the config; --dar, --output and --go_package are required unless set in the config.`,
+		Example: `  godaml go --dar ./test.dar --output ./generated --go_package main
+  godaml go --dar /path/to/contracts.dar --output ./src/daml --go_package contracts --debug
+  godaml go --dar ./a.dar --dar ./b.dar --output ./generated --go_import_path github.com/org/repo/generated
+  godaml go --config godaml.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run()
+		},
+	}
+
+	opts.addFlags(cmd)
+
+	return cmd
+}
+
+func removePackageID(filename string) string {
+	lastHyphen := strings.LastIndex(filename, "-")
+	if lastHyphen == -1 {
+		return filename
+	}
+
+	potentialHash := filename[lastHyphen+1:]
+	if len(potentialHash) == 64 {
+		allHex := true
+		for _, ch := range potentialHash {
+			if !((ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')) {
+				allHex = false
+				break
+			}
+		}
+		if allHex {
+			return filename[:lastHyphen]
+		}
+	}
+
+	return filename
+}
+
+func getFilenameFromDalf(dalfRelPath string) string {
+	parts := strings.Split(dalfRelPath, "/")
+	var baseFileName string
+	if len(parts) > 1 {
+		dalfFileName := parts[len(parts)-1]
+		baseFileName = strings.TrimSuffix(dalfFileName, ".dalf")
+	} else {
+		baseFileName = strings.TrimSuffix(dalfRelPath, ".dalf")
+	}
+
+	baseFileName = removePackageID(baseFileName)
+	sanitizedFileName := strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(baseFileName), ".", "_"), "-", "_")
+	return sanitizedFileName
+}
+
+func openDar(darFile string) (*zip.Reader, error) {
+	darContent, err := os.ReadFile(darFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dar file '%s': %w", darFile, err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader([]byte(darContent)), int64(len(darContent)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to created zip reader: %w", err)
+	}
+
+	return reader, nil
+}
+
+func runCodeGen(darFile, outputDir, pkgFile string, debugMode bool, generateHexCodec bool, externalPackages model2.ExternalPackages, fieldHints model2.FieldHints) error {
+	if debugMode {
+		log.Info().Msg("debug mode enabled")
+	}
+
+	reader, err := openDar(darFile)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := codegen.GetManifest(reader)
+	if err != nil {
+		return fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	err = os.MkdirAll(outputDir, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create output directory '%s': %w", outputDir, err)
+	}
+
+	dalfs := make([]string, 0)
+	for _, dalf := range manifest.Dalfs {
+		if dalf == manifest.MainDalf {
+			continue
+		}
+
+		if codegen.IsStdlibPackage(dalf) {
+			continue
+		}
+
+		dalfs = append(dalfs, dalf)
+	}
+
+	dalfManifest := &model2.Manifest{
+		SdkVersion: manifest.SdkVersion,
+		MainDalf:   manifest.MainDalf,
+		Dalfs:      dalfs,
+	}
+
+	dalfToProcess := make([]string, 0)
+	dalfToProcess = append(dalfToProcess, manifest.MainDalf)
+	dalfToProcess = append(dalfToProcess, dalfs...)
+
+	result, err := codegen.CodegenDalfs(dalfToProcess, reader, pkgFile, dalfManifest, generateHexCodec, externalPackages, fieldHints)
+	if err != nil {
+		return err
+	}
+
+	for dalf, code := range result {
+		baseFileName := getFilenameFromDalf(dalf)
+		outputFile := filepath.Join(outputDir, baseFileName+".go")
+
+		if err := os.WriteFile(outputFile, []byte(code), 0o644); err != nil {
+			return fmt.Errorf("failed to write file '%s': %w", outputFile, err)
+		}
+
+		log.Info().Msgf("successfully generated: %s", outputFile)
+	}
+
+	return nil
+}
+
+func runMultiCodeGen(darFiles []string, outputDir, goImportPath string, debugMode bool, generateHexCodec bool, externalPackages model2.ExternalPackages, fieldHints model2.FieldHints) error {
+	if debugMode {
+		log.Info().Msg("debug mode enabled")
+	}
+
+	dars := make([]fs.FS, 0, len(darFiles))
+	for _, darFile := range darFiles {
+		reader, err := openDar(darFile)
+		if err != nil {
+			return err
+		}
+		dars = append(dars, reader)
+	}
+
+	packages, err := codegen.CodegenDars(dars, goImportPath, generateHexCodec, externalPackages, fieldHints)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range packages {
+		packageDir := filepath.Join(outputDir, pkg.GoPackage)
+		if err := os.MkdirAll(packageDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory '%s': %w", packageDir, err)
+		}
+
+		outputFile := filepath.Join(packageDir, getFilenameFromDalf(pkg.Dalf)+".go")
+		if err := os.WriteFile(outputFile, []byte(pkg.Code), 0o644); err != nil {
+			return fmt.Errorf("failed to write file '%s': %w", outputFile, err)
+		}
+
+		log.Info().Msgf("successfully generated: %s", outputFile)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoOptionsConfig(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "godaml.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(`
+dar: ../test-data/all-kinds-of-1.0.0_lf.dar
+output: `+filepath.Join(dir, "config")+`
+goPackage: fromconfig
+hexEncoder: true
+fieldHints:
+  typeFields:
+    AllKindsOf.OneOfEverything: {someDecimal: decimal}
+`), 0o644))
+
+	require.NoError(t, (&goOptions{configFile: config}).run())
+	code, err := os.ReadFile(filepath.Join(dir, "config", "all_kinds_of_1_0_0.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(code), "package fromconfig")
+	require.Contains(t, string(code), `json:"someDecimal" hex:"decimal"`)
+
+	// Flags take precedence over the config
+	output := filepath.Join(dir, "flags")
+	require.NoError(t, (&goOptions{configFile: config, output: output, pkg: "fromflags"}).run())
+	code, err = os.ReadFile(filepath.Join(output, "all_kinds_of_1_0_0.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(code), "package fromflags")
+
+	require.ErrorContains(t, (&goOptions{}).run(), "--dar parameter is required")
+
+	require.NoError(t, os.WriteFile(config, []byte("fieldHints: {typeFields: {AllKindsOf.Missing: {someText: bytes}}}\n"), 0o644))
+	require.ErrorContains(t, (&goOptions{configFile: config, darFiles: []string{"../test-data/all-kinds-of-1.0.0_lf.dar"}, output: output, pkg: "fromflags"}).run(), "unknown type AllKindsOf.Missing")
+}
+
+func TestGoOptionsMultipleDars(t *testing.T) {
+	output := t.TempDir()
+	opts := &goOptions{
+		darFiles:     []string{"../test-data/all-kinds-of-1.0.0_lf.dar", "../test-data/amulets-interface-test-1.0.0_lf.dar"},
+		output:       output,
+		goImportPath: "github.com/org/repo/generated",
+	}
+	require.NoError(t, opts.run())
+
+	code, err := os.ReadFile(filepath.Join(output, "all_kinds_of", "all_kinds_of_1_0_0.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(code), "package all_kinds_of")
+	code, err = os.ReadFile(filepath.Join(output, "amulets_interface_test", "amulets_interface_test_1_0_0.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(code), "package amulets_interface_test")
+
+	opts.goImportPath = ""
+	require.ErrorContains(t, opts.run(), "--go_import_path parameter is required")
+	opts.goImportPath, opts.pkg = "github.com/org/repo/generated", "contracts"
+	require.ErrorContains(t, opts.run(), "--go_package cannot be used")
+}
@@ -0,0 +1,254 @@
+// Package common holds the parts of the AST generators that do not depend on the Daml-LF major
+// version, so that the LF 1.x and LF 2.x generators produce the same codegen model.
+package common
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/smartcontractkit/go-daml/codegen/model"
+)
+
+const (
+	RawTypeTemplate   = "Template"
+	RawTypeOptional   = "OPTIONAL"
+	RawTypeInterface  = "Interface"
+	RawTypeRecord     = "Record"
+	RawTypeVariant    = "Variant"
+	RawTypeEnum       = "Enum"
+	RawTypeContractID = "CONTRACT_ID"
+	RawTypeList       = "LIST"
+)
+
+// Generator is the LF version independent state of an AST generator
+type Generator struct {
+	// Optional external packages to allow referencing external types
+	ExternalPackages model.ExternalPackages
+	// For keeping track of which packages have been imported, will start off empty and be populated as we process the
+	// DAML LF and encounter references to external packages. This allows us to include only the necessary imports in the generated code
+	ImportedPackages map[string]model.ExternalPackage
+	// Caller-supplied hints for fields that need non-default hex encoding tags
+	FieldHints model.FieldHints
+}
+
+// importPackage returns the external package with the given ID and records it as imported
+func (g *Generator) importPackage(packageID string) (model.ExternalPackage, bool) {
+	extPkg, exists := g.ExternalPackages.Packages[packageID]
+	if exists {
+		g.ImportedPackages[packageID] = extPkg
+	}
+	return extPkg, exists
+}
+
+// ConType returns the type of the type constructor name declared in module moduleName of the package
+// with the given ID, which is empty for the package itself
+func (g *Generator) ConType(packageID string, moduleName string, name string) model.DamlType {
+	if packageID == "" {
+		// Type constructor from the same package, will be generated as part of the output
+		return model.Unknown{String: name}
+	}
+
+	// Check if this is an external package that we have access to via externalPackages
+	if extPkg, exists := g.importPackage(packageID); exists {
+		return model.Imported{
+			Underlying:      model.Unknown{String: name},
+			ExternalPackage: extPkg,
+		}
+	}
+
+	// Special handling for certain stdlib/DA types that have generated types
+	if moduleName == "DA.Date.Types" {
+		switch name {
+		case "DayOfWeek":
+			return model.DayOfWeek{}
+		case "Month":
+			return model.Month{}
+		}
+	}
+	switch name {
+	case "RelTime":
+		return model.RelTime{}
+	case "Set":
+		return model.Set{}
+	case "Tuple2":
+		return model.Tuple2{}
+	case "Tuple3":
+		return model.Tuple3{}
+	default:
+		return model.Unknown{String: name}
+	}
+}
+
+// Field returns the record field name of type typ, tagged according to the field hints
+func (g *Generator) Field(name string, typ model.DamlType, rawType string) *model.TmplField {
+	_, isOptional := typ.(model.Optional)
+	return &model.TmplField{
+		Name:         name,
+		Type:         typ,
+		RawType:      rawType,
+		IsOptional:   isOptional,
+		IsBytes:      g.FieldHints.BytesFields[name],
+		IsBytesHex:   g.FieldHints.BytesHexFields[name],
+		IsUint32:     g.FieldHints.Uint32Fields[name],
+		IsUint32List: g.FieldHints.Uint32ListFields[name],
+		// Only scalar NUMERIC fields get hex:"decimal"; a same-named map/list (e.g. FeeQuoter's
+		// usdPerToken GENMAP) must not, matching the runtime types.NUMERIC check in the codec.
+		IsDecimal: g.FieldHints.DecimalFields[name] && typ.GoType() == "types.NUMERIC",
+	}
+}
+
+// SetKey sets the key of tmplStruct to the first of the key fields, if it is a field of the template
+func SetKey(tmplStruct *model.TmplStruct, keyFieldNames []string, keyType string) {
+	if len(keyFieldNames) == 0 {
+		return
+	}
+
+	// For now, we support single-field keys
+	// TODO: Support composite keys with multiple fields
+	keyFieldName := keyFieldNames[0]
+	for _, field := range tmplStruct.Fields {
+		if field.Name == keyFieldName {
+			tmplStruct.Key = &model.TmplField{
+				Name:    field.Name,
+				Type:    field.Type,
+				RawType: keyType,
+			}
+			log.Debug().Msgf("template %s key field: %s", tmplStruct.Name, keyFieldName)
+			return
+		}
+	}
+}
+
+// AddInterface records that tmplStruct implements the interface interfaceName of module ifcModuleName,
+// declared in the package with the given ID, and adds the interface choices that the template does
+// not already define. The template does not implement the Go interface if one of its choices has
+// the name of a choice of another interface of the template, as a method can only implement one.
+func (g *Generator) AddInterface(
+	tmplStruct *model.TmplStruct, interfaceName string, ifcModuleName string, packageID string,
+	interfaces map[string]model.InterfaceMap,
+) {
+	// Extract the DAML interface name (remove "I" prefix that was added)
+	damlIfcName := strings.TrimPrefix(interfaceName, "I")
+	// For interfaces like IIExecutor (from IExecutor), remove both I's
+	if strings.HasPrefix(damlIfcName, "I") {
+		damlIfcName = strings.TrimPrefix(damlIfcName, "I")
+	}
+
+	interfaceStruct, exists := findInterface(interfaces[ifcModuleName], interfaceName)
+	if exists {
+		// The interface is generated under another name if other modules declare one of the same name
+		interfaceName = interfaceStruct.Name
+	}
+
+	var extPkg model.ExternalPackage
+	implements := model.DamlType(model.Unknown{String: interfaceName})
+	if packageID != "" {
+		// Check if this is an external package that we have access to via externalPackages
+		var exists bool
+		extPkg, exists = g.importPackage(packageID)
+		if exists {
+			implements = model.Imported{
+				Underlying:      model.Unknown{String: interfaceName},
+				ExternalPackage: extPkg,
+			}
+		}
+	}
+
+	log.Debug().Msgf("template %s -implements interface: %s location %s", tmplStruct.Name, interfaceName, ifcModuleName)
+
+	if !exists {
+		tmplStruct.Implements = append(tmplStruct.Implements, implements)
+		return
+	}
+	log.Debug().Msgf("found interface %s in map with %d choices", interfaceName, len(interfaceStruct.Choices))
+
+	implemented := true
+	for _, ifaceChoice := range interfaceStruct.Choices {
+		// Check if template already has a choice implementing this interface method
+		// Template choices follow naming convention: {InterfaceName}_{MethodName}
+		// e.g., interface method "CalculateFee" -> template choice "Executor_CalculateFee"
+		expectedTmplChoiceName := damlIfcName + "_" + ifaceChoice.Name
+		found := false
+		for _, tmplChoice := range tmplStruct.Choices {
+			if tmplChoice.Name == ifaceChoice.Name || tmplChoice.Name == expectedTmplChoiceName {
+				found = true
+				if tmplChoice.Interface != nil && tmplChoice.Interface.GoType() != implements.GoType() {
+					// The method exercises the choice of another interface
+					log.Debug().Msgf("template %s choice %s is already defined by interface %s", tmplStruct.Name, tmplChoice.Name, tmplChoice.Interface.GoType())
+					implemented = false
+					break
+				}
+				tmplChoice.Interface = implements
+				// If this is an external interface, wrap the ArgType immediately
+				// Skip wrapping built-in types (Unit, etc.) and already-imported types
+				if extPkg != (model.ExternalPackage{}) {
+					if _, isImported := tmplChoice.ArgType.(model.Imported); !isImported {
+						if _, isUnit := tmplChoice.ArgType.(model.Unit); !isUnit {
+							tmplChoice.ArgType = model.Imported{
+								Underlying:      tmplChoice.ArgType,
+								ExternalPackage: extPkg,
+							}
+						}
+						tmplChoice.InterfaceName = interfaceName
+						tmplChoice.InterfaceDAMLName = damlIfcName
+					}
+				}
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		log.Debug().Msgf("adding interface choice %s to template %s", ifaceChoice.Name, tmplStruct.Name)
+		choice := &model.TmplChoice{
+			Name:              ifaceChoice.Name,
+			ArgType:           ifaceChoice.ArgType,
+			ReturnType:        ifaceChoice.ReturnType,
+			Interface:         implements,
+			InterfaceName:     interfaceName,
+			InterfaceDAMLName: interfaceStruct.DAMLName,
+		}
+		if extPkg != (model.ExternalPackage{}) {
+			log.Debug().Msg("Interface choice is from an external package, adding using imports")
+			// Only wrap non-Unit types
+			if _, isUnit := choice.ArgType.(model.Unit); !isUnit {
+				choice.ArgType = model.Imported{
+					Underlying:      ifaceChoice.ArgType,
+					ExternalPackage: extPkg,
+				}
+			}
+			choice.ReturnType = importReturnType(ifaceChoice.ReturnType, extPkg)
+		}
+		tmplStruct.Choices = append(tmplStruct.Choices, choice)
+	}
+
+	if implemented {
+		tmplStruct.Implements = append(tmplStruct.Implements, implements)
+	}
+}
+
+// findInterface returns the interface of the module that is named interfaceName before renaming
+func findInterface(moduleInterfaces model.InterfaceMap, interfaceName string) (*model.TmplStruct, bool) {
+	if ifc, exists := moduleInterfaces[interfaceName]; exists && ifc.Name == interfaceName {
+		return ifc, true
+	}
+	for _, ifc := range moduleInterfaces {
+		if "I"+ifc.DAMLName == interfaceName {
+			return ifc, true
+		}
+	}
+	return nil, false
+}
+
+// importReturnType qualifies a choice return type declared in an external interface package,
+// so that it refers to the type generated for that package. Builtin types are left as they are.
+func importReturnType(retType model.DamlType, extPkg model.ExternalPackage) model.DamlType {
+	if _, isUnknown := retType.(model.Unknown); isUnknown {
+		return model.Imported{
+			Underlying:      retType,
+			ExternalPackage: extPkg,
+		}
+	}
+	return retType
+}
@@ -3,6 +3,7 @@ package astgen
 import (
 	"fmt"
 
+	v2 "github.com/smartcontractkit/go-daml/codegen/astgen/v2"
 	v3 "github.com/smartcontractkit/go-daml/codegen/astgen/v3"
 	model2 "github.com/smartcontractkit/go-daml/codegen/model"
 )
@@ -14,6 +15,7 @@ const (
 )
 
 type AstGen interface {
+	// GetInterfaces returns the interfaces of the package by module and Go name, module:name
 	GetInterfaces() (map[string]*model2.TmplStruct, error)
 	GetTemplateStructs(ifcByModule map[string]model2.InterfaceMap) (map[string]*model2.TmplStruct, model2.ExternalPackages, error)
 	GetConsts() ([]*model2.TmplConst, error)
@@ -21,6 +23,9 @@ type AstGen interface {
 
 func GetAstGenFromVersion(payload []byte, ext model2.ExternalPackages, hints model2.FieldHints, ver string) (AstGen, error) {
 	switch ver {
+	case V1, V2:
+		// SDK 1.x and 2.x DARs contain Daml-LF 1.x archives
+		return v2.NewCodegenAst(payload, ext, hints), nil
 	case V3:
 		return v3.NewCodegenAst(payload, ext, hints), nil
 	default:
@@ -0,0 +1,666 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+
+	damlcommon "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive"
+	daml "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive/daml_lf_1"
+	"github.com/rs/zerolog/log"
+	"github.com/smartcontractkit/go-daml/codegen/astgen/common"
+	"github.com/smartcontractkit/go-daml/codegen/model"
+	"google.golang.org/protobuf/proto"
+)
+
+// codeGenAst builds the codegen model from Daml-LF 1.x archives, as produced by SDK 1.x and 2.x.
+// The LF version independent parts are shared with the LF 2.x generator in astgen/v3 through
+// astgen/common, so that the generated Go code has the same shape regardless of the LF major version of a DAR.
+type codeGenAst struct {
+	payload []byte
+	common.Generator
+}
+
+func NewCodegenAst(payload []byte, externalPackages model.ExternalPackages, fieldHints model.FieldHints) *codeGenAst {
+	return &codeGenAst{
+		payload: payload,
+		Generator: common.Generator{
+			ExternalPackages: externalPackages,
+			FieldHints:       fieldHints,
+		},
+	}
+}
+
+// getPackage decodes the LF 1.x package of the archive. Unlike LF 2.x, names may be inlined
+// instead of interned (LF < 1.7), so modules without interned strings are still processed.
+func (c *codeGenAst) getPackage() (*daml.Package, error) {
+	var archive damlcommon.Archive
+	err := proto.Unmarshal(c.payload, &archive)
+	if err != nil {
+		return nil, err
+	}
+
+	var payloadMapped damlcommon.ArchivePayload
+	err = proto.Unmarshal(archive.Payload, &payloadMapped)
+	if err != nil {
+		return nil, err
+	}
+
+	damlLfBytes := payloadMapped.GetDamlLf_1()
+	if damlLfBytes == nil {
+		return nil, errors.New("unsupported daml version")
+	}
+
+	var damlLf daml.Package
+	err = proto.Unmarshal(damlLfBytes, &damlLf)
+	if err != nil {
+		return nil, err
+	}
+
+	damlLf.InternedStrings = common.UnmangleIdentifiers(damlLf.InternedStrings)
+
+	return &damlLf, nil
+}
+
+func (c *codeGenAst) GetInterfaces() (map[string]*model.TmplStruct, error) {
+	interfaceMap := make(map[string]*model.TmplStruct)
+
+	pkg, err := c.getPackage()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, module := range pkg.Modules {
+		moduleName := c.getDottedName(pkg, module.GetNameDname(), module.GetNameInternedDname())
+
+		interfaces, err := c.getInterfaces(pkg, module, moduleName)
+		if err != nil {
+			return nil, err
+		}
+		// Interfaces of different modules may have the same name
+		for key, val := range interfaces {
+			interfaceMap[moduleName+":"+key] = val
+		}
+	}
+
+	return interfaceMap, nil
+}
+
+func (c *codeGenAst) GetTemplateStructs(ifcByModule map[string]model.InterfaceMap) (map[string]*model.TmplStruct, model.ExternalPackages, error) {
+	structs := make(map[string]*model.TmplStruct)
+	// Reset imported packages map before processing, will be populated with any external packages that are actually referenced by this package.
+	c.ImportedPackages = make(map[string]model.ExternalPackage)
+
+	pkg, err := c.getPackage()
+	if err != nil {
+		return nil, model.ExternalPackages{}, err
+	}
+
+	for _, module := range pkg.Modules {
+		moduleName := c.getDottedName(pkg, module.GetNameDname(), module.GetNameInternedDname())
+		log.Info().Msgf("processing module %s", moduleName)
+
+		dataTypes, err := c.getDataTypes(pkg, module, moduleName)
+		if err != nil {
+			return nil, model.ExternalPackages{}, err
+		}
+		for key, val := range dataTypes {
+			structs[key] = val
+		}
+
+		templates, err := c.getTemplates(pkg, module, moduleName, ifcByModule)
+		if err != nil {
+			return nil, model.ExternalPackages{}, err
+		}
+		for key, val := range templates {
+			structs[key] = val
+		}
+	}
+
+	// Return all packages that have actually been imported
+	importedPackages := model.ExternalPackages{
+		Packages: c.ImportedPackages,
+	}
+	c.ImportedPackages = nil
+
+	return structs, importedPackages, nil
+}
+
+func (c *codeGenAst) GetConsts() ([]*model.TmplConst, error) {
+	pkg, err := c.getPackage()
+	if err != nil {
+		return nil, err
+	}
+
+	var exprs []*model.TmplConst
+	for _, module := range pkg.Modules {
+		moduleName := c.getDottedName(pkg, module.GetNameDname(), module.GetNameInternedDname())
+		log.Info().Msgf("processing module %s for consts", moduleName)
+
+		for _, value := range module.GetValues() {
+			nameWithType := value.GetNameWithType()
+			var name string
+			if segments := nameWithType.GetNameDname(); len(segments) > 0 {
+				name = segments[len(segments)-1]
+			} else {
+				name = c.getName(pkg, nil, nameWithType.GetNameInternedDname())
+			}
+			// Skip all names that contain invalid characters
+			// expressions can contain more than just literal values, e.g. selectors
+			if !token.IsIdentifier(name) {
+				continue
+			}
+			exp, err := c.extractExpression(pkg, value.GetExpr())
+			if err != nil {
+				// Ignore all errors for now, not all possible types are handled yet
+				continue
+			}
+			exprs = append(exprs, &model.TmplConst{
+				Name:       name,
+				Expression: exp,
+			})
+		}
+	}
+
+	return exprs, nil
+}
+
+// getString resolves a string that is either inlined or interned.
+func (c *codeGenAst) getString(pkg *daml.Package, str string, internedStr int32) string {
+	if str != "" {
+		return str
+	}
+	if int(internedStr) >= len(pkg.InternedStrings) {
+		return ""
+	}
+	return pkg.InternedStrings[internedStr]
+}
+
+// getSegments resolves a dotted name that is either inlined or interned.
+func (c *codeGenAst) getSegments(pkg *daml.Package, dname *daml.DottedName, internedDname int32) []string {
+	if dname != nil {
+		return dname.Segments
+	}
+	if int(internedDname) >= len(pkg.InternedDottedNames) {
+		return nil
+	}
+
+	idx := pkg.InternedDottedNames[internedDname].SegmentsInternedStr
+	segments := make([]string, 0, len(idx))
+	for _, segIdx := range idx {
+		if int(segIdx) < len(pkg.InternedStrings) {
+			segments = append(segments, pkg.InternedStrings[segIdx])
+		}
+	}
+	return segments
+}
+
+func (c *codeGenAst) getName(pkg *daml.Package, dname *daml.DottedName, internedDname int32) string {
+	segments := c.getSegments(pkg, dname, internedDname)
+	if len(segments) == 0 {
+		return ""
+	}
+	return segments[len(segments)-1]
+}
+
+func (c *codeGenAst) getDottedName(pkg *daml.Package, dname *daml.DottedName, internedDname int32) string {
+	return strings.Join(c.getSegments(pkg, dname, internedDname), ".")
+}
+
+func (c *codeGenAst) getDataTypeName(pkg *daml.Package, dataType *daml.DefDataType) string {
+	return c.getName(pkg, dataType.GetNameDname(), dataType.GetNameInternedDname())
+}
+
+// getPackageID returns the ID of the package referenced by ref, or an empty string for the package itself.
+func (c *codeGenAst) getPackageID(pkg *daml.Package, ref *daml.PackageRef) (string, error) {
+	switch pkgId := ref.GetSum().(type) {
+	case *daml.PackageRef_Self:
+		return "", nil
+	case *daml.PackageRef_PackageIdStr:
+		return pkgId.PackageIdStr, nil
+	case *daml.PackageRef_PackageIdInternedStr:
+		return c.getString(pkg, "", pkgId.PackageIdInternedStr), nil
+	default:
+		return "", fmt.Errorf("unknown package reference type: %T", pkgId)
+	}
+}
+
+func (c *codeGenAst) isEnumType(typeName model.DamlType, pkg *daml.Package) bool {
+	for _, module := range pkg.Modules {
+		for _, dataType := range module.GetDataTypes() {
+			if !dataType.Serializable {
+				continue
+			}
+
+			if c.getDataTypeName(pkg, dataType) == typeName.GoType() {
+				if _, isEnum := dataType.DataCons.(*daml.DefDataType_Enum); isEnum {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (c *codeGenAst) getTemplates(
+	pkg *daml.Package, module *daml.Module, moduleName string,
+	interfaces map[string]model.InterfaceMap,
+) (map[string]*model.TmplStruct, error) {
+	structs := make(map[string]*model.TmplStruct, 0)
+
+	for _, template := range module.Templates {
+		templateName := c.getName(pkg, template.GetTyconDname(), template.GetTyconInternedDname())
+		log.Debug().Msgf("processing template: %s", templateName)
+
+		var templateDataType *daml.DefDataType
+		for _, dataType := range module.DataTypes {
+			if c.getDataTypeName(pkg, dataType) == templateName {
+				templateDataType = dataType
+				break
+			}
+		}
+
+		if templateDataType == nil {
+			log.Debug().Msgf("could not find data type for template: %s", templateName)
+			continue
+		}
+
+		tmplStruct := model.TmplStruct{
+			Name:       templateName,
+			DAMLName:   templateName,
+			ModuleName: moduleName,
+			RawType:    common.RawTypeTemplate,
+			IsTemplate: true,
+			Choices:    make([]*model.TmplChoice, 0),
+		}
+
+		switch v := templateDataType.DataCons.(type) {
+		case *daml.DefDataType_Record:
+			for _, field := range v.Record.Fields {
+				fieldExtracted, typeExtracted, err := c.extractField(pkg, field)
+				if err != nil {
+					return nil, err
+				}
+				tmplField := c.Field(fieldExtracted, typeExtracted, field.String())
+				tmplField.IsEnum = c.isEnumType(typeExtracted, pkg)
+				tmplStruct.Fields = append(tmplStruct.Fields, tmplField)
+			}
+		default:
+			log.Debug().Msgf("template %s has non-record data type: %T", templateName, v)
+		}
+
+		choices := c.getChoices(pkg, template.Choices)
+		tmplStruct.Choices = append(tmplStruct.Choices, choices...)
+
+		if template.Key != nil {
+			keyType := template.Key.GetType().String()
+			normalizedKeyType := model.NormalizeDAMLType(keyType)
+			log.Debug().Msgf("template %s has key of type: %s (normalized: %s)", templateName, keyType, normalizedKeyType)
+			common.SetKey(&tmplStruct, c.parseKeyExpression(pkg, template.Key), keyType)
+		}
+
+		for _, impl := range template.Implements {
+			if impl.Interface == nil {
+				continue
+			}
+			c.addInterface(pkg, &tmplStruct, impl.Interface, interfaces)
+		}
+
+		structs[templateName] = &tmplStruct
+	}
+
+	return structs, nil
+}
+
+// addInterface records that tmplStruct implements ifc and adds the interface choices that
+// the template does not already define.
+func (c *codeGenAst) addInterface(pkg *daml.Package, tmplStruct *model.TmplStruct, ifc *daml.TypeConName, interfaces map[string]model.InterfaceMap) {
+	interfaceName := "I" + c.getName(pkg, ifc.GetNameDname(), ifc.GetNameInternedDname())
+
+	importedPackageId, err := c.getPackageID(pkg, ifc.GetModule().GetPackageRef())
+	if err != nil {
+		log.Warn().Err(err).Msgf("unknown package ID type for interface implementation of %s", interfaceName)
+		return
+	}
+
+	ifcModuleName := c.getDottedName(pkg, ifc.GetModule().GetModuleNameDname(), ifc.GetModule().GetModuleNameInternedDname())
+	c.AddInterface(tmplStruct, interfaceName, ifcModuleName, importedPackageId, interfaces)
+}
+
+func (c *codeGenAst) getChoices(pkg *daml.Package, choices []*daml.TemplateChoice) []*model.TmplChoice {
+	res := make([]*model.TmplChoice, 0)
+	for _, choice := range choices {
+		choiceStruct := &model.TmplChoice{
+			Name: c.getString(pkg, choice.GetNameStr(), choice.GetNameInternedStr()),
+		}
+
+		if argBinder := choice.GetArgBinder(); argBinder != nil && argBinder.Type != nil {
+			argType := c.extractType(pkg, argBinder.Type)
+			// If this is an Archive choice, set ArgType to Unit in order to ignore it in the template
+			if argType.GoType() == "Archive" {
+				argType = model.Unit{}
+			}
+			choiceStruct.ArgType = argType
+		}
+
+		if retType := choice.GetRetType(); retType != nil {
+			choiceStruct.ReturnType = c.extractType(pkg, retType)
+		}
+
+		res = append(res, choiceStruct)
+	}
+
+	return res
+}
+
+func (c *codeGenAst) getInterfaces(pkg *daml.Package, module *daml.Module, moduleName string) (map[string]*model.TmplStruct, error) {
+	structs := make(map[string]*model.TmplStruct, 0)
+
+	for _, iface := range module.Interfaces {
+		originalName := c.getName(pkg, nil, iface.TyconInternedDname)
+		interfaceName := "I" + originalName
+		location := moduleName
+		if locModule := iface.GetLocation().GetModule(); locModule != nil {
+			location = c.getName(pkg, locModule.GetModuleNameDname(), locModule.GetModuleNameInternedDname())
+		}
+		log.Debug().Msgf("processing interface: %s, original name %s location %s", interfaceName, originalName, location)
+
+		tmplStruct := model.TmplStruct{
+			Name:        interfaceName,
+			DAMLName:    originalName,
+			ModuleName:  moduleName,
+			RawType:     common.RawTypeInterface,
+			IsInterface: true,
+			Choices:     make([]*model.TmplChoice, 0),
+			Location:    location,
+		}
+		choices := c.getChoices(pkg, iface.Choices)
+		tmplStruct.Choices = append(tmplStruct.Choices, choices...)
+
+		structs[interfaceName] = &tmplStruct
+	}
+
+	return structs, nil
+}
+
+func (c *codeGenAst) getDataTypes(pkg *daml.Package, module *daml.Module, moduleName string) (map[string]*model.TmplStruct, error) {
+	structs := make(map[string]*model.TmplStruct, 0)
+	for _, dataType := range module.GetDataTypes() {
+		if !dataType.Serializable {
+			continue
+		}
+
+		name := c.getDataTypeName(pkg, dataType)
+		tmplStruct := model.TmplStruct{
+			Name:       name,
+			DAMLName:   name,
+			ModuleName: moduleName,
+		}
+
+		switch v := dataType.DataCons.(type) {
+		case *daml.DefDataType_Record:
+			tmplStruct.RawType = common.RawTypeRecord
+			for _, field := range v.Record.Fields {
+				fieldExtracted, typeExtracted, err := c.extractField(pkg, field)
+				if err != nil {
+					return nil, err
+				}
+				tmplStruct.Fields = append(tmplStruct.Fields, c.Field(fieldExtracted, typeExtracted, field.String()))
+			}
+		case *daml.DefDataType_Variant:
+			tmplStruct.RawType = common.RawTypeVariant
+			// Check if this variant type has numeric tag byte mapping configured
+			variantKey := moduleName + "." + name
+			if tagMap, exists := c.FieldHints.VariantTagByteMap[variantKey]; exists {
+				tmplStruct.VariantTagMapping = tagMap
+			}
+			for _, field := range v.Variant.Fields {
+				fieldExtracted, typeExtracted, err := c.extractField(pkg, field)
+				if err != nil {
+					return nil, err
+				}
+				tmplField := c.Field(fieldExtracted, typeExtracted, field.String())
+				tmplField.IsOptional = true
+				tmplStruct.Fields = append(tmplStruct.Fields, tmplField)
+			}
+		case *daml.DefDataType_Enum:
+			tmplStruct.RawType = common.RawTypeEnum
+			enumKey := moduleName + "." + name
+			if tagMap, exists := c.FieldHints.EnumTagByteMap[enumKey]; exists {
+				tmplStruct.EnumTagMapping = tagMap
+			}
+			constructors := v.Enum.ConstructorsStr
+			for _, constructorIdx := range v.Enum.ConstructorsInternedStr {
+				if int(constructorIdx) < len(pkg.InternedStrings) {
+					constructors = append(constructors, pkg.InternedStrings[constructorIdx])
+				}
+			}
+			for _, constructorName := range constructors {
+				tmplStruct.Fields = append(tmplStruct.Fields, &model.TmplField{
+					Name: constructorName,
+					Type: model.Enum{},
+				})
+			}
+		case *daml.DefDataType_Interface:
+			tmplStruct.RawType = common.RawTypeInterface
+			log.Warn().Msgf("interface not supported %s", name)
+		default:
+			log.Warn().Msgf("unknown data cons type: %T", v)
+		}
+		structs[name] = &tmplStruct
+	}
+
+	return structs, nil
+}
+
+// parseKeyExpression returns the fields referenced by a template key. LF 1.x keys are either
+// a restricted key expression of projections and records, or, since LF 1.4, an arbitrary expression.
+func (c *codeGenAst) parseKeyExpression(pkg *daml.Package, key *daml.DefTemplate_DefKey) []string {
+	var fieldNames []string
+	if key == nil {
+		return fieldNames
+	}
+
+	switch k := key.KeyExpr.(type) {
+	case *daml.DefTemplate_DefKey_Key:
+		fieldNames = c.parseKeyExprForFields(pkg, k.Key)
+	case *daml.DefTemplate_DefKey_ComplexKey:
+		fieldNames = c.parseExpressionForFields(pkg, k.ComplexKey)
+	default:
+		return fieldNames
+	}
+
+	if len(fieldNames) == 0 {
+		log.Warn().Msg("could not extract fields from key expression")
+	}
+
+	return fieldNames
+}
+
+func (c *codeGenAst) parseKeyExprForFields(pkg *daml.Package, keyExpr *daml.KeyExpr) []string {
+	var fieldNames []string
+
+	switch e := keyExpr.GetSum().(type) {
+	case *daml.KeyExpr_Projections_:
+		for _, projection := range e.Projections.GetProjections() {
+			fieldNames = append(fieldNames, c.getString(pkg, projection.GetFieldStr(), projection.GetFieldInternedStr()))
+		}
+	case *daml.KeyExpr_Record_:
+		for _, field := range e.Record.GetFields() {
+			fieldNames = append(fieldNames, c.getString(pkg, field.GetFieldStr(), field.GetFieldInternedStr()))
+		}
+	default:
+		log.Debug().Msgf("unhandled key expression type in key parsing: %T", e)
+	}
+
+	return fieldNames
+}
+
+func (c *codeGenAst) parseExpressionForFields(pkg *daml.Package, expr *daml.Expr) []string {
+	var fieldNames []string
+
+	if expr == nil {
+		return fieldNames
+	}
+
+	switch e := expr.Sum.(type) {
+	case *daml.Expr_RecProj_:
+		if e.RecProj != nil {
+			fieldNames = append(fieldNames, c.getString(pkg, e.RecProj.GetFieldStr(), e.RecProj.GetFieldInternedStr()))
+			// Also check if the record being projected has more fields
+			if e.RecProj.Record != nil {
+				subFields := c.parseExpressionForFields(pkg, e.RecProj.Record)
+				fieldNames = append(fieldNames, subFields...)
+			}
+		}
+	case *daml.Expr_RecCon_:
+		if e.RecCon != nil {
+			for _, field := range e.RecCon.Fields {
+				fieldNames = append(fieldNames, c.getString(pkg, field.GetFieldStr(), field.GetFieldInternedStr()))
+			}
+		}
+	case *daml.Expr_VarStr:
+		fieldNames = append(fieldNames, e.VarStr)
+	case *daml.Expr_VarInternedStr:
+		// In template keys, the template parameter is often referenced
+		// We'll include variable names as they might represent fields
+		fieldNames = append(fieldNames, c.getString(pkg, "", e.VarInternedStr))
+	default:
+		log.Debug().Msgf("unhandled expression type in key parsing: %T", e)
+	}
+
+	return fieldNames
+}
+
+func (c *codeGenAst) extractType(pkg *daml.Package, typ *daml.Type) model.DamlType {
+	if typ == nil {
+		return model.Unknown{}
+	}
+
+	switch v := typ.Sum.(type) {
+	case *daml.Type_Interned:
+		if int(v.Interned) >= len(pkg.InternedTypes) {
+			return model.Unknown{String: "unknown_interned_type"}
+		}
+		// recurse into the interned definition
+		return c.extractType(pkg, pkg.InternedTypes[v.Interned])
+	case *daml.Type_Prim_:
+		return c.handlePrimType(pkg, v.Prim)
+	case *daml.Type_Con_:
+		// Type constructor, arguments of type constructors are not reflected in the generated types
+		return c.handleConType(pkg, v.Con)
+	case *daml.Type_Var_:
+		// Can't handle these properly yet...
+		return model.Any{}
+	case *daml.Type_Syn_:
+		// Synonym - type synonyms are usually expanded by the Daml compiler,
+		// but we still handle them here for completeness
+		if v.Syn.Tysyn != nil {
+			name := c.getName(pkg, v.Syn.Tysyn.GetNameDname(), v.Syn.Tysyn.GetNameInternedDname())
+			// Detect BytesHex type synonym from DA.Crypto.Text
+			if name == "BytesHex" {
+				return model.BytesHex{}
+			}
+			return model.Unknown{String: name}
+		}
+		return model.Unknown{String: "syn_without_name"}
+	default:
+		return model.Unknown{String: fmt.Sprintf("unknown_type_%T", typ.Sum)}
+	}
+}
+
+func (c *codeGenAst) handlePrimType(pkg *daml.Package, p *daml.Type_Prim) model.DamlType {
+	switch p.Prim {
+	case daml.PrimType_UNIT:
+		return model.Unit{}
+	case daml.PrimType_BOOL:
+		return model.Bool{}
+	case daml.PrimType_INT64:
+		return model.Int64{}
+	case daml.PrimType_DATE:
+		return model.Date{}
+	case daml.PrimType_TIMESTAMP:
+		return model.Timestamp{}
+	case daml.PrimType_DECIMAL, daml.PrimType_NUMERIC:
+		// DECIMAL is the fixed scale Numeric 10 of LF < 1.7
+		return model.Numeric{}
+	case daml.PrimType_PARTY:
+		return model.Party{}
+	case daml.PrimType_TEXT:
+		return model.Text{}
+	case daml.PrimType_CONTRACT_ID:
+		// Don't extract the template argument, to prevent the referred package from being imported
+		return model.ContractId{}
+	case daml.PrimType_OPTIONAL:
+		if len(p.Args) == 0 {
+			return model.Optional{Inner: model.Unknown{String: "optional_without_arg"}}
+		}
+		return model.Optional{
+			Inner: c.extractType(pkg, p.Args[0]),
+		}
+	case daml.PrimType_LIST:
+		if len(p.Args) == 0 {
+			return model.List{Inner: model.Unknown{String: "list_without_arg"}}
+		}
+		return model.List{
+			Inner: c.extractType(pkg, p.Args[0]),
+		}
+	case daml.PrimType_GENMAP:
+		if len(p.Args) < 2 {
+			return model.GenMap{}
+		}
+		return model.GenMap{
+			Key:   c.extractType(pkg, p.Args[0]),
+			Value: c.extractType(pkg, p.Args[1]),
+		}
+	case daml.PrimType_TEXTMAP:
+		if len(p.Args) == 0 {
+			return model.TextMap{}
+		}
+		return model.TextMap{
+			Value: c.extractType(pkg, p.Args[0]),
+		}
+	case daml.PrimType_ANY:
+		return model.Any{}
+	case daml.PrimType_BIGNUMERIC:
+		return model.BigNumeric{}
+	case daml.PrimType_ROUNDING_MODE:
+		return model.RoundingMode{}
+	default:
+		// UPDATE, SCENARIO, ARROW, TYPE_REP and ANY_EXCEPTION are not serializable
+		return model.Unknown{}
+	}
+}
+
+func (c *codeGenAst) handleConType(pkg *daml.Package, conType *daml.Type_Con) model.DamlType {
+	if conType == nil || conType.Tycon == nil {
+		return model.Unknown{String: "con_without_tycon"}
+	}
+
+	name := c.getName(pkg, conType.Tycon.GetNameDname(), conType.Tycon.GetNameInternedDname())
+	importedPackageId, err := c.getPackageID(pkg, conType.Tycon.GetModule().GetPackageRef())
+	if err != nil {
+		log.Warn().Err(err).Msgf("failed to resolve package of type %s", name)
+		return model.Unknown{String: name}
+	}
+	moduleName := c.getDottedName(pkg, conType.Tycon.GetModule().GetModuleNameDname(), conType.Tycon.GetModule().GetModuleNameInternedDname())
+	return c.ConType(importedPackageId, moduleName, name)
+}
+
+func (c *codeGenAst) extractField(pkg *daml.Package, field *daml.FieldWithType) (string, model.DamlType, error) {
+	if field == nil {
+		return "", nil, fmt.Errorf("field is nil")
+	}
+
+	fieldName := c.getString(pkg, field.GetFieldStr(), field.GetFieldInternedStr())
+	if fieldName == "" {
+		return "", nil, fmt.Errorf("invalid field name: %s", field.String())
+	}
+
+	if field.Type == nil {
+		return fieldName, nil, fmt.Errorf("field type is nil")
+	}
+
+	return fieldName, c.extractType(pkg, field.Type), nil
+}
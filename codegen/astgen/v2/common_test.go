@@ -0,0 +1,184 @@
+package v2
+
+import (
+	"testing"
+
+	daml "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive/daml_lf_1"
+	"github.com/smartcontractkit/go-daml/codegen/astgen/common"
+	"github.com/smartcontractkit/go-daml/codegen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleConType_InlineAndInternedNames(t *testing.T) {
+	extPkg := model.ExternalPackage{Import: "github.com/example/ext", Alias: "ext"}
+	codeGen := &codeGenAst{Generator: common.Generator{
+		ExternalPackages: model.ExternalPackages{
+			Packages: map[string]model.ExternalPackage{"ext-pkg-id": extPkg},
+		},
+		ImportedPackages: map[string]model.ExternalPackage{},
+	}}
+
+	pkg := &daml.Package{
+		InternedStrings: []string{"DA", "Set", "Types", "ext-pkg-id", "Route"},
+		InternedDottedNames: []*daml.InternedDottedName{
+			{SegmentsInternedStr: []int32{0, 1, 2}},
+			{SegmentsInternedStr: []int32{4}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		conType      *daml.Type_Con
+		expectedType model.DamlType
+	}{
+		{
+			name: "self package with inline name",
+			conType: &daml.Type_Con{Tycon: &daml.TypeConName{
+				Module: &daml.ModuleRef{PackageRef: &daml.PackageRef{Sum: &daml.PackageRef_Self{Self: &daml.Unit{}}}},
+				Name:   &daml.TypeConName_NameDname{NameDname: &daml.DottedName{Segments: []string{"Route"}}},
+			}},
+			expectedType: model.Unknown{String: "Route"},
+		},
+		{
+			name: "stdlib type with inline package ID",
+			conType: &daml.Type_Con{Tycon: &daml.TypeConName{
+				Module: &daml.ModuleRef{PackageRef: &daml.PackageRef{Sum: &daml.PackageRef_PackageIdStr{PackageIdStr: "da-set-pkg-id"}}},
+				Name:   &daml.TypeConName_NameDname{NameDname: &daml.DottedName{Segments: []string{"Set"}}},
+			}},
+			expectedType: model.Set{},
+		},
+		{
+			name: "external type with interned package ID",
+			conType: &daml.Type_Con{Tycon: &daml.TypeConName{
+				Module: &daml.ModuleRef{PackageRef: &daml.PackageRef{Sum: &daml.PackageRef_PackageIdInternedStr{PackageIdInternedStr: 3}}},
+				Name:   &daml.TypeConName_NameInternedDname{NameInternedDname: 1},
+			}},
+			expectedType: model.Imported{Underlying: model.Unknown{String: "Route"}, ExternalPackage: extPkg},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expectedType, codeGen.handleConType(pkg, tt.conType))
+		})
+	}
+	require.Equal(t, map[string]model.ExternalPackage{"ext-pkg-id": extPkg}, codeGen.ImportedPackages)
+}
+
+func TestExtractType_Prim(t *testing.T) {
+	codeGen := &codeGenAst{}
+	prim := func(p daml.PrimType, args ...*daml.Type) *daml.Type {
+		return &daml.Type{Sum: &daml.Type_Prim_{Prim: &daml.Type_Prim{Prim: p, Args: args}}}
+	}
+	pkg := &daml.Package{
+		InternedTypes: []*daml.Type{prim(daml.PrimType_INT64)},
+	}
+	interned := &daml.Type{Sum: &daml.Type_Interned{Interned: 0}}
+
+	tests := []struct {
+		name         string
+		typ          *daml.Type
+		expectedType model.DamlType
+	}{
+		{"legacy decimal", prim(daml.PrimType_DECIMAL), model.Numeric{}},
+		{"numeric with scale", prim(daml.PrimType_NUMERIC, &daml.Type{Sum: &daml.Type_Nat{Nat: 10}}), model.Numeric{}},
+		{"interned type", interned, model.Int64{}},
+		{"optional list", prim(daml.PrimType_OPTIONAL, prim(daml.PrimType_LIST, interned)), model.Optional{Inner: model.List{Inner: model.Int64{}}}},
+		{"genmap", prim(daml.PrimType_GENMAP, prim(daml.PrimType_PARTY), interned), model.GenMap{Key: model.Party{}, Value: model.Int64{}}},
+		{"contract id drops template", prim(daml.PrimType_CONTRACT_ID, interned), model.ContractId{}},
+		{"out of range interned type", &daml.Type{Sum: &daml.Type_Interned{Interned: 5}}, model.Unknown{String: "unknown_interned_type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expectedType, codeGen.extractType(pkg, tt.typ))
+		})
+	}
+}
+
+func TestParseKeyExpressionV2(t *testing.T) {
+	codeGen := &codeGenAst{}
+	pkg := &daml.Package{
+		InternedStrings: []string{"owner", "orderId", "this"},
+	}
+
+	t.Run("Projection key", func(t *testing.T) {
+		key := &daml.DefTemplate_DefKey{
+			KeyExpr: &daml.DefTemplate_DefKey_Key{Key: &daml.KeyExpr{
+				Sum: &daml.KeyExpr_Projections_{Projections: &daml.KeyExpr_Projections{
+					Projections: []*daml.KeyExpr_Projection{
+						{Field: &daml.KeyExpr_Projection_FieldInternedStr{FieldInternedStr: 0}},
+					},
+				}},
+			}},
+		}
+
+		require.Equal(t, []string{"owner"}, codeGen.parseKeyExpression(pkg, key))
+	})
+
+	t.Run("Record key", func(t *testing.T) {
+		key := &daml.DefTemplate_DefKey{
+			KeyExpr: &daml.DefTemplate_DefKey_Key{Key: &daml.KeyExpr{
+				Sum: &daml.KeyExpr_Record_{Record: &daml.KeyExpr_Record{
+					Fields: []*daml.KeyExpr_RecordField{
+						{Field: &daml.KeyExpr_RecordField_FieldInternedStr{FieldInternedStr: 0}},
+						{Field: &daml.KeyExpr_RecordField_FieldStr{FieldStr: "orderId"}},
+					},
+				}},
+			}},
+		}
+
+		require.Equal(t, []string{"owner", "orderId"}, codeGen.parseKeyExpression(pkg, key))
+	})
+
+	t.Run("Complex key projection", func(t *testing.T) {
+		// key this.owner : Party
+		key := &daml.DefTemplate_DefKey{
+			KeyExpr: &daml.DefTemplate_DefKey_ComplexKey{ComplexKey: &daml.Expr{
+				Sum: &daml.Expr_RecProj_{RecProj: &daml.Expr_RecProj{
+					Field:  &daml.Expr_RecProj_FieldInternedStr{FieldInternedStr: 0},
+					Record: &daml.Expr{Sum: &daml.Expr_VarInternedStr{VarInternedStr: 2}},
+				}},
+			}},
+		}
+
+		require.Equal(t, []string{"owner", "this"}, codeGen.parseKeyExpression(pkg, key))
+	})
+
+	t.Run("Empty key expression", func(t *testing.T) {
+		require.Empty(t, codeGen.parseKeyExpression(pkg, &daml.DefTemplate_DefKey{}))
+	})
+}
+
+func TestExtractExpression(t *testing.T) {
+	codeGen := &codeGenAst{}
+	pkg := &daml.Package{
+		InternedStrings: []string{"1.5000000000", "hello"},
+	}
+	lit := func(lit *daml.PrimLit) *daml.Expr {
+		return &daml.Expr{Sum: &daml.Expr_PrimLit{PrimLit: lit}}
+	}
+
+	tests := []struct {
+		name     string
+		expr     *daml.Expr
+		expected model.DamlExpression
+	}{
+		{"int64", lit(&daml.PrimLit{Sum: &daml.PrimLit_Int64{Int64: 42}}), model.Int64Literal{Value: 42}},
+		{"legacy decimal", lit(&daml.PrimLit{Sum: &daml.PrimLit_DecimalStr{DecimalStr: "0.25"}}), model.NumericLiteral{Value: "0.25"}},
+		{"interned numeric", lit(&daml.PrimLit{Sum: &daml.PrimLit_NumericInternedStr{NumericInternedStr: 0}}), model.NumericLiteral{Value: "1.5000000000"}},
+		{"inline text", lit(&daml.PrimLit{Sum: &daml.PrimLit_TextStr{TextStr: "hi"}}), model.TextLiteral{Value: "hi"}},
+		{"interned text", lit(&daml.PrimLit{Sum: &daml.PrimLit_TextInternedStr{TextInternedStr: 1}}), model.TextLiteral{Value: "hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := codeGen.extractExpression(pkg, tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+
+	_, err := codeGen.extractExpression(pkg, lit(&daml.PrimLit{Sum: &daml.PrimLit_Timestamp{Timestamp: 1}}))
+	require.Error(t, err)
+}
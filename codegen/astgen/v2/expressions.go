@@ -0,0 +1,45 @@
+package v2
+
+import (
+	"fmt"
+
+	daml "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive/daml_lf_1"
+	"github.com/smartcontractkit/go-daml/codegen/model"
+)
+
+func (c *codeGenAst) extractExpression(pkg *daml.Package, expr *daml.Expr) (model.DamlExpression, error) {
+	switch v := expr.GetSum().(type) {
+	case *daml.Expr_PrimLit:
+		return c.extractExpPrimLit(pkg, v.PrimLit)
+	default:
+		return nil, fmt.Errorf("unsupported expression type: %T", expr.GetSum())
+	}
+}
+
+func (c *codeGenAst) extractExpPrimLit(pkg *daml.Package, expr *daml.PrimLit) (model.DamlExpression, error) {
+	switch v := expr.GetSum().(type) {
+	case *daml.PrimLit_Int64:
+		return model.Int64Literal{
+			Value: v.Int64,
+		}, nil
+	case *daml.PrimLit_DecimalStr:
+		return model.NumericLiteral{
+			Value: v.DecimalStr,
+		}, nil
+	case *daml.PrimLit_NumericInternedStr:
+		return model.NumericLiteral{
+			Value: c.getString(pkg, "", v.NumericInternedStr),
+		}, nil
+	case *daml.PrimLit_TextStr:
+		return model.TextLiteral{
+			Value: v.TextStr,
+		}, nil
+	case *daml.PrimLit_TextInternedStr:
+		return model.TextLiteral{
+			Value: c.getString(pkg, "", v.TextInternedStr),
+		}, nil
+	default:
+		// Can't handle timestamps and dates, as they aren't Go constants
+		return nil, fmt.Errorf("unsupported PrimLit type: %T", expr.GetSum())
+	}
+}
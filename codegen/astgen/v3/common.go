@@ -9,37 +9,23 @@ import (
 	damlcommon "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive"
 	daml "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive/daml_lf_2"
 	"github.com/rs/zerolog/log"
+	"github.com/smartcontractkit/go-daml/codegen/astgen/common"
 	"github.com/smartcontractkit/go-daml/codegen/model"
 	"google.golang.org/protobuf/proto"
 )
 
-const (
-	RawTypeTemplate   = "Template"
-	RawTypeOptional   = "OPTIONAL"
-	RawTypeInterface  = "Interface"
-	RawTypeRecord     = "Record"
-	RawTypeVariant    = "Variant"
-	RawTypeEnum       = "Enum"
-	RawTypeContractID = "CONTRACT_ID"
-	RawTypeList       = "LIST"
-)
-
 type codeGenAst struct {
 	payload []byte
-	// Optional external packages to allow referencing external types
-	externalPackages model.ExternalPackages
-	// For keeping track of which packages have been imported, will start off empty and be populated as we process the
-	// DAML LF and encounter references to external packages. This allows us to include only the necessary imports in the generated code
-	importedPackages map[string]model.ExternalPackage
-	// Caller-supplied hints for fields that need non-default hex encoding tags
-	fieldHints model.FieldHints
+	common.Generator
 }
 
 func NewCodegenAst(payload []byte, externalPackages model.ExternalPackages, fieldHints model.FieldHints) *codeGenAst {
 	return &codeGenAst{
-		payload:          payload,
-		externalPackages: externalPackages,
-		fieldHints:       fieldHints,
+		payload: payload,
+		Generator: common.Generator{
+			ExternalPackages: externalPackages,
+			FieldHints:       fieldHints,
+		},
 	}
 }
 
@@ -61,96 +47,6 @@ func (c *codeGenAst) isEnumType(typeName model.DamlType, pkg *daml.Package) bool
 	return false
 }
 
-// This unmangles a list of strings, e.g. all interned strings
-// ref: https://docs.digitalasset.com/build/3.4/reference/damllf/daml-lf-translation.html#names-with-special-characters
-func unmangleIdentifiers(ids []string) []string {
-	result := make([]string, len(ids))
-	for i, id := range ids {
-		unmangled, err := unmangleIdentifier(id)
-		if err != nil {
-			// If unmangling fails, keep the original identifier
-			result[i] = id
-		} else {
-			result[i] = unmangled
-		}
-	}
-	return result
-}
-
-// unmangleIdentifier reverses the Daml-LF name mangling scheme:
-//   - $$ becomes $
-//   - $uABCD becomes the Unicode codepoint U+ABCD (4 hex digits, lowercase a-f)
-//   - $UABCD1234 becomes the Unicode codepoint U+ABCD1234 (8 hex digits, lowercase a-f)
-//   - ASCII letters, digits, and _ are passed through unchanged
-func unmangleIdentifier(s string) (string, error) {
-	if len(s) == 0 {
-		return "", fmt.Errorf("empty identifier")
-	}
-
-	var b strings.Builder
-	i := 0
-	for i < len(s) {
-		if s[i] == '$' {
-			i++
-			if i >= len(s) {
-				return "", fmt.Errorf("control character $ at end of identifier %q", s)
-			}
-			switch s[i] {
-			case '$':
-				b.WriteByte('$')
-				i++
-			case 'u':
-				i++
-				if i+4 > len(s) {
-					return "", fmt.Errorf("expected 4 hex digits after $u in %q, but got %d", s, len(s)-i)
-				}
-				hex := s[i : i+4]
-				cp, err := parseHexCodepoint(hex)
-				if err != nil {
-					return "", fmt.Errorf("invalid escape sequence $u%s in %q: %w", hex, s, err)
-				}
-				b.WriteRune(rune(cp))
-				i += 4
-			case 'U':
-				i++
-				if i+8 > len(s) {
-					return "", fmt.Errorf("expected 8 hex digits after $U in %q, but got %d", s, len(s)-i)
-				}
-				hex := s[i : i+8]
-				cp, err := parseHexCodepoint(hex)
-				if err != nil {
-					return "", fmt.Errorf("invalid escape sequence $U%s in %q: %w", hex, s, err)
-				}
-				b.WriteRune(rune(cp))
-				i += 8
-			default:
-				return "", fmt.Errorf("control character $ should be followed by $, u or U in %q", s)
-			}
-		} else {
-			b.WriteByte(s[i])
-			i++
-		}
-	}
-	return b.String(), nil
-}
-
-// parseHexCodepoint parses a string of lowercase hex digits into a codepoint value.
-func parseHexCodepoint(hex string) (int64, error) {
-	var val int64
-	for _, c := range hex {
-		val <<= 4
-		switch {
-		case c >= '0' && c <= '9':
-			val |= int64(c - '0')
-		case c >= 'a' && c <= 'f':
-			val |= int64(c-'a') + 10
-		default:
-			return 0, fmt.Errorf("expected only lowercase hex digits (0-9, a-f), but got %q", string(c))
-		}
-	}
-	return val, nil
-}
-
 func (c *codeGenAst) GetInterfaces() (map[string]*model.TmplStruct, error) {
 	interfaceMap := make(map[string]*model.TmplStruct)
 
@@ -177,7 +73,7 @@ func (c *codeGenAst) GetInterfaces() (map[string]*model.TmplStruct, error) {
 		return nil, err
 	}
 
-	damlLf.InternedStrings = unmangleIdentifiers(damlLf.InternedStrings)
+	damlLf.InternedStrings = common.UnmangleIdentifiers(damlLf.InternedStrings)
 
 	for _, module := range damlLf.Modules {
 		if len(damlLf.InternedStrings) == 0 {
@@ -190,8 +86,9 @@ func (c *codeGenAst) GetInterfaces() (map[string]*model.TmplStruct, error) {
 		if err != nil {
 			return nil, err
 		}
+		// Interfaces of different modules may have the same name
 		for key, val := range interfaces {
-			interfaceMap[key] = val
+			interfaceMap[moduleName+":"+key] = val
 		}
 	}
 
@@ -201,7 +98,7 @@ func (c *codeGenAst) GetInterfaces() (map[string]*model.TmplStruct, error) {
 func (c *codeGenAst) GetTemplateStructs(ifcByModule map[string]model.InterfaceMap) (map[string]*model.TmplStruct, model.ExternalPackages, error) {
 	structs := make(map[string]*model.TmplStruct)
 	// Reset imported packages map before processing, will be populated with any external packages that are actually referenced by this package.
-	c.importedPackages = make(map[string]model.ExternalPackage)
+	c.ImportedPackages = make(map[string]model.ExternalPackage)
 
 	var archive damlcommon.Archive
 	err := proto.Unmarshal(c.payload, &archive)
@@ -226,7 +123,7 @@ func (c *codeGenAst) GetTemplateStructs(ifcByModule map[string]model.InterfaceMa
 		return nil, model.ExternalPackages{}, err
 	}
 
-	damlLf.InternedStrings = unmangleIdentifiers(damlLf.InternedStrings)
+	damlLf.InternedStrings = common.UnmangleIdentifiers(damlLf.InternedStrings)
 
 	for _, module := range damlLf.Modules {
 		if len(damlLf.InternedStrings) == 0 {
@@ -256,9 +153,9 @@ func (c *codeGenAst) GetTemplateStructs(ifcByModule map[string]model.InterfaceMa
 
 	// Return all packages that have actually been imported
 	importedPackages := model.ExternalPackages{
-		Packages: c.importedPackages,
+		Packages: c.ImportedPackages,
 	}
-	c.importedPackages = nil
+	c.ImportedPackages = nil
 
 	return structs, importedPackages, nil
 }
@@ -287,7 +184,7 @@ func (c *codeGenAst) GetConsts() ([]*model.TmplConst, error) {
 		return nil, err
 	}
 
-	pkg.InternedStrings = unmangleIdentifiers(pkg.InternedStrings)
+	pkg.InternedStrings = common.UnmangleIdentifiers(pkg.InternedStrings)
 
 	var exprs []*model.TmplConst
 	for _, module := range pkg.Modules {
@@ -354,7 +251,7 @@ func (c *codeGenAst) getTemplates(
 			Name:       templateName,
 			DAMLName:   templateName,
 			ModuleName: moduleName,
-			RawType:    RawTypeTemplate,
+			RawType:    common.RawTypeTemplate,
 			IsTemplate: true,
 			Choices:    make([]*model.TmplChoice, 0),
 		}
@@ -366,21 +263,9 @@ func (c *codeGenAst) getTemplates(
 				if err != nil {
 					return nil, err
 				}
-				_, isOptional := typeExtracted.(model.Optional)
-				tmplStruct.Fields = append(tmplStruct.Fields, &model.TmplField{
-					Name:         fieldExtracted,
-					Type:         typeExtracted,
-					RawType:      field.String(),
-					IsOptional:   isOptional,
-					IsEnum:       c.isEnumType(typeExtracted, pkg),
-					IsBytes:      c.fieldHints.BytesFields[fieldExtracted],
-					IsBytesHex:   c.fieldHints.BytesHexFields[fieldExtracted],
-					IsUint32:     c.fieldHints.Uint32Fields[fieldExtracted],
-					IsUint32List: c.fieldHints.Uint32ListFields[fieldExtracted],
-					// Only scalar NUMERIC fields get hex:"decimal"; a same-named map/list (e.g. FeeQuoter's
-					// usdPerToken GENMAP) must not, matching the runtime types.NUMERIC check in the codec.
-					IsDecimal: c.fieldHints.DecimalFields[fieldExtracted] && typeExtracted.GoType() == "types.NUMERIC",
-				})
+				tmplField := c.Field(fieldExtracted, typeExtracted, field.String())
+				tmplField.IsEnum = c.isEnumType(typeExtracted, pkg)
+				tmplStruct.Fields = append(tmplStruct.Fields, tmplField)
 			}
 		default:
 			log.Debug().Msgf("template %s has non-record data type: %T", templateName, v)
@@ -393,144 +278,21 @@ func (c *codeGenAst) getTemplates(
 			keyType := template.Key.GetType().String()
 			normalizedKeyType := model.NormalizeDAMLType(keyType)
 			log.Debug().Msgf("template %s has key of type: %s (normalized: %s)", templateName, keyType, normalizedKeyType)
-			keyFieldNames := c.parseKeyExpression(pkg, template.Key)
-
-			if len(keyFieldNames) > 0 {
-				// For now, we support single-field keys
-				// TODO: Support composite keys with multiple fields
-				keyFieldName := keyFieldNames[0]
-				var keyField *model.TmplField
-				for _, field := range tmplStruct.Fields {
-					if field.Name == keyFieldName {
-						keyField = &model.TmplField{
-							Name:    field.Name,
-							Type:    field.Type,
-							RawType: keyType,
-						}
-						break
-					}
-				}
-
-				if keyField != nil {
-					tmplStruct.Key = keyField
-					log.Debug().Msgf("template %s key field: %s", templateName, keyFieldName)
-				}
-			}
+			common.SetKey(&tmplStruct, c.parseKeyExpression(pkg, template.Key), keyType)
 		}
 
-		if len(template.Implements) > 0 {
-			for _, impl := range template.Implements {
-				if impl.Interface != nil {
-
-					interfaceName := "I" + c.getName(pkg, impl.Interface.GetNameInternedDname())
-
-					var extPkg model.ExternalPackage
-					implements := model.DamlType(model.Unknown{String: interfaceName})
-					switch pkgId := impl.Interface.Module.PackageId.Sum.(type) {
-					case *daml.SelfOrImportedPackageId_SelfPackageId:
-						// Local interface - nothing to do
-					case *daml.SelfOrImportedPackageId_ImportedPackageIdInternedStr:
-						// Type constructor from an imported package - referenced by interned string
-						importedPackageId := pkg.InternedStrings[pkgId.ImportedPackageIdInternedStr]
-						// Check if this is an external package that we have access to via externalPackages
-						var exists bool
-						extPkg, exists = c.externalPackages.Packages[importedPackageId]
-						if exists {
-							c.importedPackages[importedPackageId] = extPkg
-							implements = model.Imported{
-								Underlying:      model.Unknown{String: interfaceName},
-								ExternalPackage: extPkg,
-							}
-						}
-					case *daml.SelfOrImportedPackageId_PackageImportId:
-						// Type constructor from an imported package - referenced by package import id
-						importedPackageId := pkg.GetPackageImports().ImportedPackages[pkgId.PackageImportId]
-						// Check if this is an external package that we have access to via externalPackages
-						var exists bool
-						extPkg, exists = c.externalPackages.Packages[importedPackageId]
-						if exists {
-							c.importedPackages[importedPackageId] = extPkg
-							implements = model.Imported{
-								Underlying:      model.Unknown{String: interfaceName},
-								ExternalPackage: extPkg,
-							}
-						}
-					default:
-						log.Warn().Msgf("unknown package ID type for interface implementation: %T", pkgId)
-						continue
-					}
-
-					tmplStruct.Implements = append(tmplStruct.Implements, implements)
-					ifcModuleName := c.getDottedName(pkg, impl.Interface.Module.ModuleNameInternedDname)
-					log.Debug().Msgf("template %s -implements interface: %s location %s", templateName, interfaceName, ifcModuleName)
-
-					if interfaceStruct, exists := interfaces[ifcModuleName][interfaceName]; exists {
-						log.Debug().Msgf("found interface %s in map with %d choices", interfaceName, len(interfaceStruct.Choices))
-
-						// Extract the DAML interface name (remove "I" prefix that was added)
-						damlIfcName := strings.TrimPrefix(interfaceName, "I")
-						// For interfaces like IIExecutor (from IExecutor), remove both I's
-						if strings.HasPrefix(damlIfcName, "I") {
-							damlIfcName = strings.TrimPrefix(damlIfcName, "I")
-						}
-
-						for _, ifaceChoice := range interfaceStruct.Choices {
-							// Check if template already has a choice implementing this interface method
-							// Template choices follow naming convention: {InterfaceName}_{MethodName}
-							// e.g., interface method "CalculateFee" -> template choice "Executor_CalculateFee"
-							expectedTmplChoiceName := damlIfcName + "_" + ifaceChoice.Name
-							found := false
-							for _, tmplChoice := range tmplStruct.Choices {
-								if tmplChoice.Name == ifaceChoice.Name || tmplChoice.Name == expectedTmplChoiceName {
-									found = true
-									// If this is an external interface, wrap the ArgType immediately
-									// Skip wrapping built-in types (Unit, etc.) and already-imported types
-									if extPkg != (model.ExternalPackage{}) {
-										if _, isImported := tmplChoice.ArgType.(model.Imported); !isImported {
-											if _, isUnit := tmplChoice.ArgType.(model.Unit); !isUnit {
-												tmplChoice.ArgType = model.Imported{
-													Underlying:      tmplChoice.ArgType,
-													ExternalPackage: extPkg,
-												}
-											}
-											tmplChoice.InterfaceName = interfaceName
-											tmplChoice.InterfaceDAMLName = damlIfcName
-										}
-									}
-									break
-								}
-							}
-							if !found {
-								log.Debug().Msgf("adding interface choice %s to template %s", ifaceChoice.Name, templateName)
-								if extPkg != (model.ExternalPackage{}) {
-									log.Debug().Msg("Interface choice is from an external package, adding using imports")
-									// Only wrap non-Unit types
-									argType := ifaceChoice.ArgType
-									if _, isUnit := argType.(model.Unit); !isUnit {
-										argType = model.Imported{
-											Underlying:      ifaceChoice.ArgType,
-											ExternalPackage: extPkg,
-										}
-									}
-									tmplStruct.Choices = append(tmplStruct.Choices, &model.TmplChoice{
-										Name:              ifaceChoice.Name,
-										ArgType:           argType,
-										InterfaceName:     interfaceName,
-										InterfaceDAMLName: interfaceStruct.DAMLName,
-									})
-								} else {
-									tmplStruct.Choices = append(tmplStruct.Choices, &model.TmplChoice{
-										Name:              ifaceChoice.Name,
-										ArgType:           ifaceChoice.ArgType,
-										InterfaceName:     interfaceName,
-										InterfaceDAMLName: interfaceStruct.DAMLName,
-									})
-								}
-							}
-						}
-					}
-				}
+		for _, impl := range template.Implements {
+			if impl.Interface == nil {
+				continue
 			}
+			interfaceName := "I" + c.getName(pkg, impl.Interface.GetNameInternedDname())
+			importedPackageId, err := c.getPackageID(pkg, impl.Interface.GetModule().GetPackageId())
+			if err != nil {
+				log.Warn().Err(err).Msgf("unknown package ID type for interface implementation of %s", interfaceName)
+				continue
+			}
+			ifcModuleName := c.getDottedName(pkg, impl.Interface.GetModule().GetModuleNameInternedDname())
+			c.AddInterface(&tmplStruct, interfaceName, ifcModuleName, importedPackageId, interfaces)
 		}
 
 		structs[templateName] = &tmplStruct
@@ -561,9 +323,9 @@ func (c *codeGenAst) getChoices(pkg *daml.Package, choices []*daml.TemplateChoic
 			choiceStruct.ArgType = argType
 		}
 
-		// if retType := choice.GetRetType(); retType != nil {
-		// 	choiceStruct.ReturnType = c.extractType(pkg, retType)
-		// }
+		if retType := choice.GetRetType(); retType != nil {
+			choiceStruct.ReturnType = c.extractType(pkg, retType)
+		}
 
 		res = append(res, choiceStruct)
 	}
@@ -584,8 +346,8 @@ func (c *codeGenAst) getInterfaces(pkg *daml.Package, module *daml.Module, modul
 			Name:        interfaceName,
 			DAMLName:    originalName,
 			ModuleName:  moduleName,
-			RawType:     RawTypeInterface,
-			IsInterface: true, // TODO dont need as we have RawTypeInterface
+			RawType:     common.RawTypeInterface,
+			IsInterface: true, // TODO dont need as we have common.RawTypeInterface
 			Choices:     make([]*model.TmplChoice, 0),
 			Location:    location,
 		}
@@ -617,32 +379,19 @@ func (c *codeGenAst) getDataTypes(pkg *daml.Package, module *daml.Module, module
 
 		switch v := dataType.DataCons.(type) {
 		case *daml.DefDataType_Record:
-			tmplStruct.RawType = RawTypeRecord
+			tmplStruct.RawType = common.RawTypeRecord
 			for _, field := range v.Record.Fields {
 				fieldExtracted, typeExtracted, err := c.extractField(pkg, field)
 				if err != nil {
 					return nil, err
 				}
-				_, isOptional := typeExtracted.(model.Optional)
-				tmplStruct.Fields = append(tmplStruct.Fields, &model.TmplField{
-					Name:         fieldExtracted,
-					Type:         typeExtracted,
-					RawType:      field.String(),
-					IsOptional:   isOptional,
-					IsBytes:      c.fieldHints.BytesFields[fieldExtracted],
-					IsBytesHex:   c.fieldHints.BytesHexFields[fieldExtracted],
-					IsUint32:     c.fieldHints.Uint32Fields[fieldExtracted],
-					IsUint32List: c.fieldHints.Uint32ListFields[fieldExtracted],
-					// Only scalar NUMERIC fields get hex:"decimal"; a same-named map/list (e.g. FeeQuoter's
-					// usdPerToken GENMAP) must not, matching the runtime types.NUMERIC check in the codec.
-					IsDecimal: c.fieldHints.DecimalFields[fieldExtracted] && typeExtracted.GoType() == "types.NUMERIC",
-				})
+				tmplStruct.Fields = append(tmplStruct.Fields, c.Field(fieldExtracted, typeExtracted, field.String()))
 			}
 		case *daml.DefDataType_Variant:
-			tmplStruct.RawType = RawTypeVariant
+			tmplStruct.RawType = common.RawTypeVariant
 			// Check if this variant type has numeric tag byte mapping configured
 			variantKey := moduleName + "." + name
-			if tagMap, exists := c.fieldHints.VariantTagByteMap[variantKey]; exists {
+			if tagMap, exists := c.FieldHints.VariantTagByteMap[variantKey]; exists {
 				tmplStruct.VariantTagMapping = tagMap
 			}
 			for _, field := range v.Variant.Fields {
@@ -650,24 +399,14 @@ func (c *codeGenAst) getDataTypes(pkg *daml.Package, module *daml.Module, module
 				if err != nil {
 					return nil, err
 				}
-				tmplStruct.Fields = append(tmplStruct.Fields, &model.TmplField{
-					Name:         fieldExtracted,
-					Type:         typeExtracted,
-					RawType:      field.String(),
-					IsOptional:   true,
-					IsBytes:      c.fieldHints.BytesFields[fieldExtracted],
-					IsBytesHex:   c.fieldHints.BytesHexFields[fieldExtracted],
-					IsUint32:     c.fieldHints.Uint32Fields[fieldExtracted],
-					IsUint32List: c.fieldHints.Uint32ListFields[fieldExtracted],
-					// Only scalar NUMERIC fields get hex:"decimal"; a same-named map/list (e.g. FeeQuoter's
-					// usdPerToken GENMAP) must not, matching the runtime types.NUMERIC check in the codec.
-					IsDecimal: c.fieldHints.DecimalFields[fieldExtracted] && typeExtracted.GoType() == "types.NUMERIC",
-				})
+				tmplField := c.Field(fieldExtracted, typeExtracted, field.String())
+				tmplField.IsOptional = true
+				tmplStruct.Fields = append(tmplStruct.Fields, tmplField)
 			}
 		case *daml.DefDataType_Enum:
-			tmplStruct.RawType = RawTypeEnum
+			tmplStruct.RawType = common.RawTypeEnum
 			enumKey := moduleName + "." + name
-			if tagMap, exists := c.fieldHints.EnumTagByteMap[enumKey]; exists {
+			if tagMap, exists := c.FieldHints.EnumTagByteMap[enumKey]; exists {
 				tmplStruct.EnumTagMapping = tagMap
 			}
 			for _, constructorIdx := range v.Enum.ConstructorsInternedStr {
@@ -680,7 +419,7 @@ func (c *codeGenAst) getDataTypes(pkg *daml.Package, module *daml.Module, module
 				}
 			}
 		case *daml.DefDataType_Interface:
-			tmplStruct.RawType = RawTypeInterface
+			tmplStruct.RawType = common.RawTypeInterface
 			log.Warn().Msgf("interface not supported %s", v.Interface.String())
 		default:
 			log.Warn().Msgf("unknown data cons type: %T", v)
@@ -911,100 +650,32 @@ func (c *codeGenAst) handleBuiltinType(pkg *daml.Package, b *daml.Type_Builtin)
 }
 
 func (c *codeGenAst) handleConType(pkg *daml.Package, conType *daml.Type_Con) model.DamlType {
-
-	switch pkgId := conType.Tycon.Module.PackageId.Sum.(type) {
-	case *daml.SelfOrImportedPackageId_SelfPackageId:
-		// Type constructor from the same package, will be generated as part of the output
-		name := c.getName(pkg, conType.Tycon.GetNameInternedDname())
-		return model.Unknown{String: name}
-	case *daml.SelfOrImportedPackageId_ImportedPackageIdInternedStr:
-		// Type constructor from an imported package - referenced by interned string
-		importedPackageId := pkg.InternedStrings[pkgId.ImportedPackageIdInternedStr]
-		name := c.getName(pkg, conType.Tycon.GetNameInternedDname())
-
-		// Check if this is an external package that we have access to via externalPackages
-		if extPkg, exists := c.externalPackages.Packages[importedPackageId]; exists {
-			c.importedPackages[importedPackageId] = extPkg
-			return model.Imported{
-				Underlying:      model.Unknown{String: name},
-				ExternalPackage: extPkg,
-			}
-		}
-
-		// Special handling for certain stdlib/DA types that have generated types
-		switch name {
-		case "RelTime":
-			return model.RelTime{}
-		case "Set":
-			return model.Set{}
-		default:
-			return model.Unknown{String: name}
-		}
-	case *daml.SelfOrImportedPackageId_PackageImportId:
-		// Type constructor from an imported package - referenced by package import id
-		importedPackageId := pkg.GetPackageImports().ImportedPackages[pkgId.PackageImportId]
-		name := c.getName(pkg, conType.Tycon.GetNameInternedDname())
-
-		// Check if this is an external package that we have access to via externalPackages
-		if extPkg, exists := c.externalPackages.Packages[importedPackageId]; exists {
-			c.importedPackages[importedPackageId] = extPkg
-			return model.Imported{
-				Underlying:      model.Unknown{String: name},
-				ExternalPackage: extPkg,
-			}
-		}
-
-		// Special handling for certain stdlib/DA types
-		switch name {
-		case "RelTime":
-			return model.RelTime{}
-		case "Set":
-			return model.Set{}
-		default:
-			return model.Unknown{String: name}
-		}
+	if conType == nil || conType.Tycon == nil {
+		return model.Unknown{String: "con_without_tycon"}
 	}
 
-	/*if conType == nil || conType.Tycon == nil {
-		return "con_without_tycon"
+	importedPackageId, err := c.getPackageID(pkg, conType.Tycon.GetModule().GetPackageId())
+	if err != nil {
+		return model.Unknown{String: "con_without_tycon"}
 	}
+	moduleName := c.getDottedName(pkg, conType.Tycon.GetModule().GetModuleNameInternedDname())
+	return c.ConType(importedPackageId, moduleName, c.getName(pkg, conType.Tycon.GetNameInternedDname()))
+}
 
-	tyconName := c.getName(pkg, conType.Tycon.GetNameInternedDname())
-
-	switch tyconName {
-	case "Optional":
-		if len(conType.Args) > 0 {
-			elementType := c.extractType(pkg, conType.Args[0])
-			normalizedElementType := model.NormalizeDAMLType(elementType)
-			return "*" + normalizedElementType
-		}
-		return RawTypeOptional
-	case "List":
-		if len(conType.Args) > 0 {
-			elementType := c.extractType(pkg, conType.Args[0])
-			normalizedElementType := model.NormalizeDAMLType(elementType)
-			return "[]" + normalizedElementType
-		}
-		return RawTypeList
-	case "Tuple2":
-		if len(conType.Args) >= 2 {
-			arg1Type := c.extractType(pkg, conType.Args[0])
-			arg2Type := c.extractType(pkg, conType.Args[1])
-			return "TUPLE2[" + model.NormalizeDAMLType(arg1Type) + "," + model.NormalizeDAMLType(arg2Type) + "]"
-		}
-		return "TUPLE2"
-	case "Tuple3":
-		if len(conType.Args) >= 3 {
-			arg1Type := c.extractType(pkg, conType.Args[0])
-			arg2Type := c.extractType(pkg, conType.Args[1])
-			arg3Type := c.extractType(pkg, conType.Args[2])
-			return "TUPLE3[" + model.NormalizeDAMLType(arg1Type) + "," + model.NormalizeDAMLType(arg2Type) + "," + model.NormalizeDAMLType(arg3Type) + "]"
-		}
-		return "TUPLE3"
+// getPackageID returns the ID of the package referenced by ref, or an empty string for the package itself.
+func (c *codeGenAst) getPackageID(pkg *daml.Package, ref *daml.SelfOrImportedPackageId) (string, error) {
+	switch pkgId := ref.GetSum().(type) {
+	case *daml.SelfOrImportedPackageId_SelfPackageId:
+		return "", nil
+	case *daml.SelfOrImportedPackageId_ImportedPackageIdInternedStr:
+		// Referenced by interned string
+		return pkg.InternedStrings[pkgId.ImportedPackageIdInternedStr], nil
+	case *daml.SelfOrImportedPackageId_PackageImportId:
+		// Referenced by package import id
+		return pkg.GetPackageImports().ImportedPackages[pkgId.PackageImportId], nil
 	default:
-		return tyconName
-	}*/
-	return model.Unknown{String: "con_without_tycon"}
+		return "", fmt.Errorf("unknown package reference type: %T", pkgId)
+	}
 }
 
 func (c *codeGenAst) extractField(pkg *daml.Package, field *daml.FieldWithType) (string, model.DamlType, error) {
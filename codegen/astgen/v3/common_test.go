@@ -1,21 +1,21 @@
 package v3
 
 import (
-	"reflect"
 	"testing"
 
 	daml "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive/daml_lf_2"
+	"github.com/smartcontractkit/go-daml/codegen/astgen/common"
 	"github.com/smartcontractkit/go-daml/codegen/model"
 	"github.com/stretchr/testify/require"
 )
 
 func TestHandleConType_StdlibTypes(t *testing.T) {
-	codeGen := &codeGenAst{
-		externalPackages: model.ExternalPackages{
+	codeGen := &codeGenAst{Generator: common.Generator{
+		ExternalPackages: model.ExternalPackages{
 			Packages: map[string]model.ExternalPackage{},
 		},
-		importedPackages: map[string]model.ExternalPackage{},
-	}
+		ImportedPackages: map[string]model.ExternalPackage{},
+	}}
 
 	tests := []struct {
 		name         string
@@ -24,6 +24,8 @@ func TestHandleConType_StdlibTypes(t *testing.T) {
 	}{
 		{"Set via InternedStr", "Set", model.Set{}},
 		{"RelTime via InternedStr", "RelTime", model.RelTime{}},
+		{"Tuple2 via InternedStr", "Tuple2", model.Tuple2{}},
+		{"Tuple3 via InternedStr", "Tuple3", model.Tuple3{}},
 		{"Unknown via InternedStr", "SomeOtherType", model.Unknown{String: "SomeOtherType"}},
 	}
 
@@ -56,12 +58,12 @@ func TestHandleConType_StdlibTypes(t *testing.T) {
 }
 
 func TestHandleConType_PackageImportId_StdlibTypes(t *testing.T) {
-	codeGen := &codeGenAst{
-		externalPackages: model.ExternalPackages{
+	codeGen := &codeGenAst{Generator: common.Generator{
+		ExternalPackages: model.ExternalPackages{
 			Packages: map[string]model.ExternalPackage{},
 		},
-		importedPackages: map[string]model.ExternalPackage{},
-	}
+		ImportedPackages: map[string]model.ExternalPackage{},
+	}}
 
 	tests := []struct {
 		name         string
@@ -70,6 +72,8 @@ func TestHandleConType_PackageImportId_StdlibTypes(t *testing.T) {
 	}{
 		{"Set via PackageImportId", "Set", model.Set{}},
 		{"RelTime via PackageImportId", "RelTime", model.RelTime{}},
+		{"Tuple2 via PackageImportId", "Tuple2", model.Tuple2{}},
+		{"Tuple3 via PackageImportId", "Tuple3", model.Tuple3{}},
 		{"Unknown via PackageImportId", "SomeOtherType", model.Unknown{String: "SomeOtherType"}},
 	}
 
@@ -312,80 +316,3 @@ func TestExtractTapp_CurriedTextMapPreservesTypeArg(t *testing.T) {
 		Value: model.Bool{},
 	}, got)
 }
-
-func Test_unmangleIdentifiers(t *testing.T) {
-	tests := []struct {
-		name string
-		ids  []string
-		want []string
-	}{
-		{
-			name: "plain identifier unchanged",
-			ids:  []string{"message"},
-			want: []string{"message"},
-		},
-		{
-			name: "underscores unchanged",
-			ids:  []string{"Foo_bar"},
-			want: []string{"Foo_bar"},
-		},
-		{
-			name: "single quote unmangled",
-			ids:  []string{"baz$u0027"},
-			want: []string{"baz'"},
-		},
-		{
-			name: "all special chars",
-			ids:  []string{"$u003a$u002b$u003a"},
-			want: []string{":+:"},
-		},
-		{
-			name: "accented chars",
-			ids:  []string{"na$u00efvet$u00e9"},
-			want: []string{"naïveté"},
-		},
-		{
-			name: "emoji with 8-digit escape",
-			ids:  []string{"$u003a$U0001f642$u003a"},
-			want: []string{":🙂:"},
-		},
-		{
-			name: "escaped dollar sign",
-			ids:  []string{"foo$$bar"},
-			want: []string{"foo$bar"},
-		},
-		{
-			name: "multiple identifiers",
-			ids:  []string{"plain", "baz$u0027", "na$u00efvet$u00e9"},
-			want: []string{"plain", "baz'", "naïveté"},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := unmangleIdentifiers(tt.ids); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("unmangleIdentifiers() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func Test_unmangleIdentifier_errors(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
-	}{
-		{"empty string", ""},
-		{"trailing dollar", "foo$"},
-		{"invalid escape char", "foo$x"},
-		{"short u escape", "foo$u00"},
-		{"short U escape", "foo$U001234"},
-		{"uppercase hex in u escape", "foo$u00AB"},
-		{"uppercase hex in U escape", "foo$U0001F642"},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := unmangleIdentifier(tt.input)
-			require.Error(t, err)
-		})
-	}
-}
@@ -63,14 +63,27 @@ func GetManifest(dar fs.FS) (*model2.Manifest, error) {
 }
 
 func CodegenDalfs(dalfToProcess []string, dar fs.FS, pkgFile string, dalfManifest *model2.Manifest, generateHexCodec bool, externalPackages model2.ExternalPackages, fieldHints model2.FieldHints) (map[string]string, error) {
+	declared := make(declaredTypes)
+	interfaces := dalfInterfaces(dalfToProcess, dar, dalfManifest)
+	result, err := codegenDalfs(dalfToProcess, dar, pkgFile, dalfManifest, generateHexCodec, externalPackages, fieldHints, interfaces, declared)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkFieldHints(declared, fieldHints); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// dalfInterfaces returns the interfaces declared in the dalfs by module name.
+func dalfInterfaces(dalfs []string, dar fs.FS, dalfManifest *model2.Manifest) map[string]model2.InterfaceMap {
 	//  ensure stable processing order across runs
-	sort.Strings(dalfToProcess)
+	dalfs = slices.Sorted(slices.Values(dalfs))
 
 	ifcByModule := make(map[string]model2.InterfaceMap)
-	result := make(map[string]string)
-
-	// -------- 1) INTERFACES: deterministic traversal, no renaming logic change --------
-	for _, dalf := range dalfToProcess {
+	for _, dalf := range dalfs {
 		dalfFile, err := dar.Open(dalf)
 		if err != nil {
 			log.Warn().Err(err).Msgf("failed to open dalf '%s': %s", dalf, err)
@@ -96,11 +109,12 @@ func CodegenDalfs(dalfToProcess []string, dar fs.FS, pkgFile string, dalfManifes
 
 		for _, key := range keys {
 			val := interfaces[key]
+			name := val.Name
 
 			equalNames := 0
 			for _, ifcName := range ifcByModule {
 				for ifcKey := range ifcName {
-					res, found := strings.CutPrefix(ifcKey, key)
+					res, found := strings.CutPrefix(ifcKey, name)
 					_, atoiErr := strconv.Atoi(res)
 					if found && (res == "" || atoiErr == nil) {
 						equalNames++
@@ -109,8 +123,8 @@ func CodegenDalfs(dalfToProcess []string, dar fs.FS, pkgFile string, dalfManifes
 			}
 			if equalNames > 0 {
 				equalNames++
-				val.Name = fmt.Sprintf("%s%d", key, equalNames) // keep your existing suffix scheme
-				// If you also want to avoid "...22" visually, change to: fmt.Sprintf("%s_%d", key, equalNames)
+				val.Name = fmt.Sprintf("%s%d", name, equalNames) // keep your existing suffix scheme
+				// If you also want to avoid "...22" visually, change to: fmt.Sprintf("%s_%d", name, equalNames)
 			}
 
 			m, ok := ifcByModule[val.ModuleName]
@@ -121,8 +135,18 @@ func CodegenDalfs(dalfToProcess []string, dar fs.FS, pkgFile string, dalfManifes
 			m[val.Name] = val
 		}
 	}
+	return ifcByModule
+}
 
-	// -------- 2) STRUCTS: deterministic traversal + do not mutate map while ranging --------
+// codegenDalfs generates the code of the dalfs, recording the types they declare in declared.
+// The templates of the dalfs implement the interfaces of ifcByModule, by module name.
+func codegenDalfs(dalfToProcess []string, dar fs.FS, pkgFile string, dalfManifest *model2.Manifest, generateHexCodec bool, externalPackages model2.ExternalPackages, fieldHints model2.FieldHints, ifcByModule map[string]model2.InterfaceMap, declared declaredTypes) (map[string]string, error) {
+	//  ensure stable processing order across runs
+	sort.Strings(dalfToProcess)
+
+	result := make(map[string]string)
+
+	// -------- STRUCTS: deterministic traversal + do not mutate map while ranging --------
 	allStructNames := make(map[string]int)
 
 	for _, dalf := range dalfToProcess {
@@ -142,12 +166,22 @@ func CodegenDalfs(dalfToProcess []string, dar fs.FS, pkgFile string, dalfManifes
 			return nil, fmt.Errorf("failed to generate AST: %w", err)
 		}
 
+		declared.add(pkg.Structs)
+
 		currentModules := make(map[string]bool)
 		for _, structDef := range pkg.Structs {
 			if structDef.ModuleName != "" {
 				currentModules[structDef.ModuleName] = true
 			}
 		}
+		// Modules may declare interfaces without any data types
+		interfaces, err := GetInterfaces(dalfContent, dalfManifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract interfaces from dalf %s: %w", dalf, err)
+		}
+		for _, ifc := range interfaces {
+			currentModules[ifc.ModuleName] = true
+		}
 
 		log.Info().Msgf("adding interfaces for dalf %s from modules: %v", dalf, currentModules)
 
@@ -232,9 +266,7 @@ func CodegenDalfs(dalfToProcess []string, dar fs.FS, pkgFile string, dalfManifes
 
 			for _, choice := range structDef.Choices {
 				choice.ArgType = renameTypeRefs(choice.ArgType, renamedStructs)
-				// if renamed, exists := renamedStructs[choice.ReturnType.GoType()]; exists {
-				// 	choice.ReturnType = model2.Unknown{String: renamed.Name}
-				// }
+				choice.ReturnType = renameTypeRefs(choice.ReturnType, renamedStructs)
 			}
 		}
 
@@ -290,6 +322,7 @@ func GetAST(payload []byte, manifest *model2.Manifest, ifcByModule map[string]mo
 	if err != nil {
 		return nil, err
 	}
+	applyTypeFieldEncodings(structs, fieldHints.TypeFieldEncodings)
 
 	packageID := GetPackageID(manifest.MainDalf)
 	if packageID == "" {
@@ -320,6 +353,25 @@ func GetAST(payload []byte, manifest *model2.Manifest, ifcByModule map[string]mo
 	}, nil
 }
 
+// stdlibPackages are the names of the packages of the Daml standard library
+var stdlibPackages = []string{"daml-prim", "daml-stdlib", "ghc-stdlib"}
+
+// IsStdlibPackage reports whether dalf is a package of the Daml standard library, which is not
+// generated: daml-prim, daml-stdlib, ghc-stdlib, or one of the packages of their DA and GHC
+// modules, such as daml-stdlib-DA-Date-Types.
+func IsStdlibPackage(dalf string) bool {
+	name := GetPackageName(dalf)
+	for _, stdlib := range stdlibPackages {
+		if name == stdlib {
+			return true
+		}
+		if module, ok := strings.CutPrefix(name, stdlib+"-"); ok && (strings.HasPrefix(module, "da-") || strings.HasPrefix(module, "ghc-")) {
+			return true
+		}
+	}
+	return false
+}
+
 func GetPackageID(dalf string) string {
 	parts := strings.Split(dalf, "/")
 	filename := strings.TrimSuffix(parts[len(parts)-1], ".dalf")
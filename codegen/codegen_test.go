@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/smartcontractkit/go-daml/codegen/model"
@@ -83,6 +84,112 @@ func TestGetMainDalfV3(t *testing.T) {
 	require.Equal(t, string(expectedCode), res, "generated code should match expected output")
 }
 
+func TestGetMainDalfLF1(t *testing.T) {
+	// Test.dar of the dazl-client fixtures, as compiled by SDK 2.9.1 to Daml-LF 1.x
+	reader, err := zip.OpenReader("../test-data/test-1.0.0_lf1.dar")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	manifest, err := GetManifest(reader)
+	require.NoError(t, err)
+	require.Equal(t, "2.9.1", manifest.SdkVersion)
+	require.Equal(t, "Test-1.0.0", manifest.Name)
+
+	dalfFile, err := reader.Open(manifest.MainDalf)
+	require.NoError(t, err)
+	dalfContent, err := io.ReadAll(dalfFile)
+	require.NoError(t, err)
+
+	ast, err := GetAST(dalfContent, manifest, nil, model.ExternalPackages{}, model.FieldHints{})
+	require.NoError(t, err)
+
+	simpleFields, exists := ast.Structs["SimpleFields"]
+	require.True(t, exists)
+	require.Equal(t, "Template", simpleFields.RawType)
+	require.Equal(t, "Primitives", simpleFields.ModuleName)
+	require.Equal(t, []*model.TmplField{
+		{Name: "party", Type: model.Party{}},
+		{Name: "aBool", Type: model.Bool{}},
+		{Name: "aInt", Type: model.Int64{}},
+		{Name: "aDecimal", Type: model.Numeric{}},
+		{Name: "aText", Type: model.Text{}},
+		{Name: "aDate", Type: model.Date{}},
+		{Name: "aDatetime", Type: model.Timestamp{}},
+	}, withoutRawTypes(simpleFields.Fields))
+	require.Len(t, simpleFields.Choices, 2)
+
+	optionalFields, exists := ast.Structs["OptionalFields"]
+	require.True(t, exists)
+	require.Equal(t, model.Optional{Inner: model.Text{}}, optionalFields.Fields[1].Type)
+	require.True(t, optionalFields.Fields[1].IsOptional)
+
+	address, exists := ast.Structs["Address"]
+	require.True(t, exists)
+	require.Equal(t, "Variant", address.RawType)
+	require.Equal(t, []*model.TmplField{
+		{Name: "US", Type: model.Unknown{String: "USAddress"}, IsOptional: true},
+		{Name: "UK", Type: model.Unknown{String: "UKAddress"}, IsOptional: true},
+	}, withoutRawTypes(address.Fields))
+
+	// The manifest as the go command passes it, which names the package after the dalf
+	dalfManifest := &model.Manifest{SdkVersion: manifest.SdkVersion, MainDalf: manifest.MainDalf}
+	res, err := CodegenDalfs([]string{manifest.MainDalf}, reader, "codegen_test", dalfManifest, false, model.ExternalPackages{}, model.FieldHints{})
+	require.NoError(t, err)
+
+	expectedCode, err := os.ReadFile("../test-data/test_1_0_0.go_gen")
+	require.NoError(t, err)
+	require.Equal(t, string(expectedCode), res[manifest.MainDalf], "generated code should match expected output")
+
+	vetGenerated(t, map[string]string{"codegen_test/test_1_0_0.go": res[manifest.MainDalf]})
+}
+
+func TestCodegenDalfsLF1Interfaces(t *testing.T) {
+	// quickstart-finance of SDK 2.5.0, whose templates implement the interfaces of the Daml Finance libraries
+	reader, err := zip.OpenReader("../test-data/quickstart-finance-0.0.1_lf1.dar")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	manifest, err := GetManifest(reader)
+	require.NoError(t, err)
+	require.Equal(t, "2.5.0", manifest.SdkVersion)
+
+	var accountDalf string
+	dalfs := []string{manifest.MainDalf}
+	for _, dalf := range manifest.Dalfs {
+		if GetPackageName(dalf) == "daml-finance-account" {
+			accountDalf = dalf
+		}
+		if dalf != manifest.MainDalf && !strings.Contains(dalf, "prim") && !strings.Contains(dalf, "stdlib") {
+			dalfs = append(dalfs, dalf)
+		}
+	}
+	require.NotEmpty(t, accountDalf)
+
+	res, err := CodegenDalfs(dalfs, reader, "codegen_test", manifest, false, model.ExternalPackages{}, model.FieldHints{})
+	require.NoError(t, err)
+
+	account := res[accountDalf]
+	expected := []string{
+		"// Credit exercises the Credit choice on this Account contract via the IAccount interface",
+		"// SetObservers exercises the SetObservers choice on this Account contract via the IDisclosure interface",
+		"func (c *AccountClient) Credit(ctx context.Context, contractID types.CONTRACT_ID, args Credit) (types.CONTRACT_ID, error) {",
+		// The Create choice of the account factory interface
+		"func (c *FactoryClient) CreateChoice(ctx context.Context, contractID types.CONTRACT_ID, args Create) (types.CONTRACT_ID, error) {",
+	}
+	for _, want := range expected {
+		require.Contains(t, account, want)
+	}
+}
+
+// withoutRawTypes returns the name, type and optionality of fields, leaving out their raw LF types
+func withoutRawTypes(fields []*model.TmplField) []*model.TmplField {
+	result := make([]*model.TmplField, 0, len(fields))
+	for _, field := range fields {
+		result = append(result, &model.TmplField{Name: field.Name, Type: field.Type, IsOptional: field.IsOptional})
+	}
+	return result
+}
+
 func TestGetPackageName(t *testing.T) {
 	require.Equal(t, "all-kinds-of",
 		GetPackageName("all-kinds-of-1.0.0-6d7e83e81a0a7960eec37340f5b11e7a61606bd9161f413684bc345c3f387948/all-kinds-of-1.0.0-6d7e83e81a0a7960eec37340f5b11e7a61606bd9161f413684bc345c3f387948.dalf"))
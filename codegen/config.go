@@ -0,0 +1,207 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	model2 "github.com/smartcontractkit/go-daml/codegen/model"
+)
+
+// Config is the declarative codegen config, usually named godaml.yaml:
+//
+//	dar: ./contracts.dar
+//	output: ./generated
+//	goPackage: contracts
+//	hexEncoder: true
+//	fieldHints:
+//	  bytesHexFields: [sender, receiver]
+//	  variantTagBytes:
+//	    MyModule.Timeout: {Indefinite: 0, RelativeHours: 1}
+//	  typeFields:
+//	    MyModule.Transfer: {sender: default, amount: decimal}
+//	externalPackages:
+//	  <package ID>: {import: github.com/org/module/shared, alias: shared}
+//
+// With several DARs, set in dars, or with goImportPath, a Go package is generated for each Daml
+// package in output/<name> and imported from goImportPath/<name>, see CodegenDars.
+type Config struct {
+	Dar              string                           `yaml:"dar"`
+	Dars             []string                         `yaml:"dars"`
+	Output           string                           `yaml:"output"`
+	GoPackage        string                           `yaml:"goPackage"`
+	GoImportPath     string                           `yaml:"goImportPath"`
+	HexEncoder       bool                             `yaml:"hexEncoder"`
+	FieldHints       FieldHintsConfig                 `yaml:"fieldHints"`
+	ExternalPackages map[string]ExternalPackageConfig `yaml:"externalPackages"`
+}
+
+// FieldHintsConfig configures model.FieldHints, listing the field names of each hint set.
+type FieldHintsConfig struct {
+	BytesFields               []string                  `yaml:"bytesFields"`
+	BytesHexFields            []string                  `yaml:"bytesHexFields"`
+	Uint32Fields              []string                  `yaml:"uint32Fields"`
+	Uint32ListFields          []string                  `yaml:"uint32ListFields"`
+	DecimalFields             []string                  `yaml:"decimalFields"`
+	VariantTagBytes           map[string]map[string]int `yaml:"variantTagBytes"`
+	EnumTagBytes              map[string]map[string]int `yaml:"enumTagBytes"`
+	ChoiceParamEncoders       []string                  `yaml:"choiceParamEncoders"`
+	ChoiceOperationDataParams map[string]string         `yaml:"choiceOperationDataParams"`
+	// TypeFields overrides the encoding of the fields of a type, keyed by fully-qualified type
+	// name and field name. Encodings are default, bytes, bytes16, uint32, []uint32 and decimal.
+	TypeFields map[string]map[string]string `yaml:"typeFields"`
+}
+
+// ExternalPackageConfig is the Go package that the types of a Daml package are imported from.
+type ExternalPackageConfig struct {
+	Import string `yaml:"import"`
+	Alias  string `yaml:"alias"`
+}
+
+// LoadConfig reads and validates the codegen config at path. Unknown keys are rejected.
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config '%s': %w", path, err)
+	}
+
+	config, err := ParseConfig(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config '%s': %w", path, err)
+	}
+
+	return config, nil
+}
+
+// ParseConfig parses and validates a YAML codegen config.
+func ParseConfig(content []byte) (*Config, error) {
+	config := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if _, err := config.GetFieldHints(); err != nil {
+		return nil, err
+	}
+	if _, err := config.GetExternalPackages(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// DarFiles returns the configured DARs, dar followed by dars.
+func (c *Config) DarFiles() []string {
+	var darFiles []string
+	if c.Dar != "" {
+		darFiles = append(darFiles, c.Dar)
+	}
+	return append(darFiles, c.Dars...)
+}
+
+// GetFieldHints converts the configured field hints.
+func (c *Config) GetFieldHints() (model2.FieldHints, error) {
+	hints := c.FieldHints
+	fieldHints := model2.FieldHints{
+		BytesFields:               fieldSet(hints.BytesFields),
+		BytesHexFields:            fieldSet(hints.BytesHexFields),
+		Uint32Fields:              fieldSet(hints.Uint32Fields),
+		Uint32ListFields:          fieldSet(hints.Uint32ListFields),
+		DecimalFields:             fieldSet(hints.DecimalFields),
+		ChoiceParamEncoderNames:   fieldSet(hints.ChoiceParamEncoders),
+		ChoiceOperationDataParams: hints.ChoiceOperationDataParams,
+	}
+
+	var err error
+	if fieldHints.VariantTagByteMap, err = tagByteMap("variantTagBytes", hints.VariantTagBytes); err != nil {
+		return model2.FieldHints{}, err
+	}
+	if fieldHints.EnumTagByteMap, err = tagByteMap("enumTagBytes", hints.EnumTagBytes); err != nil {
+		return model2.FieldHints{}, err
+	}
+
+	if len(hints.TypeFields) > 0 {
+		fieldHints.TypeFieldEncodings = make(map[string]map[string]model2.FieldEncoding, len(hints.TypeFields))
+		for _, typeName := range sortedKeys(hints.TypeFields) {
+			encodings := make(map[string]model2.FieldEncoding, len(hints.TypeFields[typeName]))
+			for _, field := range sortedKeys(hints.TypeFields[typeName]) {
+				encoding, err := model2.ParseFieldEncoding(hints.TypeFields[typeName][field])
+				if err != nil {
+					return model2.FieldHints{}, fmt.Errorf("typeFields of %s, field %s: %w", typeName, field, err)
+				}
+				encodings[field] = encoding
+			}
+			fieldHints.TypeFieldEncodings[typeName] = encodings
+		}
+	}
+
+	return fieldHints, nil
+}
+
+// GetExternalPackages converts the configured external packages.
+func (c *Config) GetExternalPackages() (model2.ExternalPackages, error) {
+	externalPackages := model2.ExternalPackages{Packages: make(map[string]model2.ExternalPackage, len(c.ExternalPackages))}
+	for _, packageID := range sortedKeys(c.ExternalPackages) {
+		pkg := c.ExternalPackages[packageID]
+		if decoded, err := hex.DecodeString(packageID); err != nil || len(decoded) != 32 {
+			return model2.ExternalPackages{}, fmt.Errorf("externalPackages: invalid package ID %q, expected 64 hex characters", packageID)
+		}
+		if pkg.Import == "" {
+			return model2.ExternalPackages{}, fmt.Errorf("externalPackages: missing import of package %s", packageID)
+		}
+		if !token.IsIdentifier(pkg.Alias) {
+			return model2.ExternalPackages{}, fmt.Errorf("externalPackages: alias %q of package %s is not a valid Go identifier", pkg.Alias, packageID)
+		}
+		externalPackages.Packages[packageID] = model2.ExternalPackage{Import: pkg.Import, Alias: pkg.Alias}
+	}
+
+	return externalPackages, nil
+}
+
+func fieldSet(fields []string) map[string]bool {
+	if len(fields) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return set
+}
+
+func tagByteMap(name string, tags map[string]map[string]int) (map[string]map[string]byte, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]map[string]byte, len(tags))
+	for _, typeName := range sortedKeys(tags) {
+		bytesByConstructor := make(map[string]byte, len(tags[typeName]))
+		for _, constructor := range sortedKeys(tags[typeName]) {
+			tag := tags[typeName][constructor]
+			if tag < 0 || tag > 255 {
+				return nil, fmt.Errorf("%s of %s: tag byte %d of %s is out of range 0-255", name, typeName, tag, constructor)
+			}
+			bytesByConstructor[constructor] = byte(tag)
+		}
+		result[typeName] = bytesByConstructor
+	}
+	return result, nil
+}
+
+// sortedKeys returns the keys of m in order, so that validation reports the same error on every run.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
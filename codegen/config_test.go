@@ -0,0 +1,152 @@
+package codegen
+
+import (
+	"archive/zip"
+	"regexp"
+	"testing"
+
+	"github.com/smartcontractkit/go-daml/codegen/model"
+	"github.com/stretchr/testify/require"
+)
+
+const testPackageID = "6d7e83e81a0a7960eec37340f5b11e7a61606bd9161f413684bc345c3f387948"
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`
+dar: ./contracts.dar
+output: ./generated
+goPackage: contracts
+hexEncoder: true
+fieldHints:
+  bytesHexFields: [sender]
+  decimalFields: [amount]
+  variantTagBytes:
+    Main.Timeout: {Indefinite: 0, RelativeHours: 7}
+  choiceOperationDataParams:
+    ProposeAdministrator: ProposeAdminParams
+  typeFields:
+    Main.Transfer: {sender: default, amount: bytes16}
+externalPackages:
+  ` + testPackageID + `: {import: github.com/org/module/shared, alias: shared}
+`))
+	require.NoError(t, err)
+	require.Equal(t, "./contracts.dar", config.Dar)
+	require.Equal(t, "contracts", config.GoPackage)
+	require.True(t, config.HexEncoder)
+
+	fieldHints, err := config.GetFieldHints()
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"sender": true}, fieldHints.BytesHexFields)
+	require.Equal(t, map[string]bool{"amount": true}, fieldHints.DecimalFields)
+	require.Nil(t, fieldHints.BytesFields)
+	require.Equal(t, map[string]map[string]byte{"Main.Timeout": {"Indefinite": 0, "RelativeHours": 7}}, fieldHints.VariantTagByteMap)
+	require.Equal(t, map[string]string{"ProposeAdministrator": "ProposeAdminParams"}, fieldHints.ChoiceOperationDataParams)
+	require.Equal(t, map[string]map[string]model.FieldEncoding{
+		"Main.Transfer": {"sender": model.FieldEncodingDefault, "amount": model.FieldEncodingBytes16},
+	}, fieldHints.TypeFieldEncodings)
+
+	externalPackages, err := config.GetExternalPackages()
+	require.NoError(t, err)
+	require.Equal(t, map[string]model.ExternalPackage{
+		testPackageID: {Import: "github.com/org/module/shared", Alias: "shared"},
+	}, externalPackages.Packages)
+
+	empty, err := ParseConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, &Config{}, empty)
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{"unknown key", "goPkg: contracts", "field goPkg not found"},
+		{"unknown field hint", "fieldHints: {hexFields: [sender]}", "field hexFields not found"},
+		{"unknown encoding", "fieldHints: {typeFields: {Main.Transfer: {sender: hex}}}", `typeFields of Main.Transfer, field sender: unknown field encoding "hex"`},
+		{"tag byte out of range", "fieldHints: {enumTagBytes: {Main.Color: {Red: 256}}}", "enumTagBytes of Main.Color: tag byte 256 of Red is out of range"},
+		{"invalid package ID", "externalPackages: {shared: {import: github.com/org/shared, alias: shared}}", `invalid package ID "shared"`},
+		{"missing import", "externalPackages: {" + testPackageID + ": {alias: shared}}", "missing import"},
+		{"invalid alias", "externalPackages: {" + testPackageID + ": {import: github.com/org/shared, alias: shared-types}}", "not a valid Go identifier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.config))
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestCodegenDalfsTypeFieldEncodings(t *testing.T) {
+	reader, err := zip.OpenReader("../test-data/all-kinds-of-1.0.0_lf.dar")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	manifest, err := GetManifest(reader)
+	require.NoError(t, err)
+
+	generate := func(fieldHints model.FieldHints) (string, error) {
+		res, err := CodegenDalfs([]string{manifest.MainDalf}, reader, "codegen_test", manifest, true, model.ExternalPackages{}, fieldHints)
+		return res[manifest.MainDalf], err
+	}
+
+	// The type field encodings take precedence over the field name hints
+	code, err := generate(model.FieldHints{
+		DecimalFields: map[string]bool{"someDecimal": true, "someMeasurement": true},
+		TypeFieldEncodings: map[string]map[string]model.FieldEncoding{
+			"AllKindsOf.OneOfEverything": {"someMeasurement": model.FieldEncodingDefault, "someText": model.FieldEncodingBytes16},
+		},
+	})
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`SomeDecimal +types.NUMERIC +`+"`"+`json:"someDecimal" hex:"decimal"`), code)
+	require.Regexp(t, regexp.MustCompile(`SomeMeasurement +types.NUMERIC +`+"`"+`json:"someMeasurement"`+"`"), code)
+	require.Regexp(t, regexp.MustCompile(`SomeText +types.TEXT +`+"`"+`json:"someText" hex:"bytes16"`), code)
+
+	_, err = generate(model.FieldHints{
+		TypeFieldEncodings: map[string]map[string]model.FieldEncoding{
+			"AllKindsOf.Missing":         {"someText": model.FieldEncodingBytes},
+			"AllKindsOf.OneOfEverything": {"someTxt": model.FieldEncodingBytes},
+		},
+	})
+	require.EqualError(t, err, "field hints refer to unknown field someTxt of type AllKindsOf.OneOfEverything, type AllKindsOf.Missing")
+}
+
+func TestCodegenDalfsUnknownFieldHints(t *testing.T) {
+	reader, err := zip.OpenReader("../test-data/all-kinds-of-1.0.0_lf.dar")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	manifest, err := GetManifest(reader)
+	require.NoError(t, err)
+
+	generate := func(fieldHints model.FieldHints) error {
+		_, err := CodegenDalfs([]string{manifest.MainDalf}, reader, "codegen_test", manifest, true, model.ExternalPackages{}, fieldHints)
+		return err
+	}
+
+	require.NoError(t, generate(model.FieldHints{
+		BytesHexFields:    map[string]bool{"someText": true},
+		DecimalFields:     map[string]bool{"someDecimal": true},
+		VariantTagByteMap: map[string]map[string]byte{"AllKindsOf.VPair": {"Left": 0, "Right": 1, "Both": 2}},
+		EnumTagByteMap:    map[string]map[string]byte{"AllKindsOf.Color": {"Red": 0}},
+	}))
+
+	// Hints that would change nothing are reported together
+	err = generate(model.FieldHints{
+		BytesFields:               map[string]bool{"someTxt": true},
+		Uint32ListFields:          map[string]bool{"someSimpleList": true, "someList": true},
+		VariantTagByteMap:         map[string]map[string]byte{"AllKindsOf.VPair": {"Middle": 0}},
+		EnumTagByteMap:            map[string]map[string]byte{"AllKindsOf.Colour": {"Red": 0}},
+		ChoiceParamEncoderNames:   map[string]bool{"Transfer": true},
+		ChoiceOperationDataParams: map[string]string{"ProposeAdministrator": "ProposeAdminParams"},
+	})
+	require.EqualError(t, err, "field hints refer to unknown "+
+		"[]uint32 field someList, "+
+		"bytes field someTxt, "+
+		"constructor Middle of variant AllKindsOf.VPair, "+
+		"enum AllKindsOf.Colour, "+
+		"record ProposeAdminParams of operation data params of ProposeAdministrator, "+
+		"record TransferParams of choice param encoder Transfer")
+}
@@ -18,25 +18,25 @@ var tmplSource string
 // DamlCodecConfig configures the Daml codec generator.
 type DamlCodecConfig struct {
 	// ModuleName is the output module name (e.g., "MyModule.Codec")
-	ModuleName string
+	ModuleName string `json:"moduleName,omitempty" yaml:"moduleName,omitempty"`
 	// TypesModule is the module containing the type definitions (e.g., "MyModule.Types")
-	TypesModule string
+	TypesModule string `json:"typesModule,omitempty" yaml:"typesModule,omitempty"`
 	// CustomTypeCodecs maps type names to their custom codec functions
-	CustomTypeCodecs map[string]CustomCodec
+	CustomTypeCodecs map[string]CustomCodec `json:"customTypeCodecs,omitempty" yaml:"customTypeCodecs,omitempty"`
 	// VariantTagByteMap maps variant type names to constructor->tag byte mappings
-	VariantTagByteMap map[string]map[string]int
+	VariantTagByteMap map[string]map[string]int `json:"variantTagBytes,omitempty" yaml:"variantTagBytes,omitempty"`
 	// TargetTypes is the list of types to generate codecs for (empty = all)
-	TargetTypes []string
+	TargetTypes []string `json:"targetTypes,omitempty" yaml:"targetTypes,omitempty"`
 }
 
 // CustomCodec defines encode/decode functions for a custom type.
 type CustomCodec struct {
-	EncodeFunc   string // e.g., "encodeRawInstanceAddress"
-	DecodeFunc   string // e.g., "decodeRawInstanceAddressAt"
-	ImportModule string // e.g., "MyModule.Codec"
+	EncodeFunc   string `json:"encodeFunc" yaml:"encodeFunc"`                         // e.g., "encodeRawInstanceAddress"
+	DecodeFunc   string `json:"decodeFunc" yaml:"decodeFunc"`                         // e.g., "decodeRawInstanceAddressAt"
+	ImportModule string `json:"importModule,omitempty" yaml:"importModule,omitempty"` // e.g., "MyModule.Codec"
 	// For list types
-	EncodeListFunc string // e.g., "encodeRawInstanceAddressList" (optional)
-	DecodeListFunc string // e.g., "decodeRawInstanceAddressList" (optional)
+	EncodeListFunc string `json:"encodeListFunc,omitempty" yaml:"encodeListFunc,omitempty"` // e.g., "encodeRawInstanceAddressList" (optional)
+	DecodeListFunc string `json:"decodeListFunc,omitempty" yaml:"decodeListFunc,omitempty"` // e.g., "decodeRawInstanceAddressList" (optional)
 }
 
 // tmplData is the data passed to the template.
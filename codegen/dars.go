@@ -0,0 +1,163 @@
+package codegen
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	model2 "github.com/smartcontractkit/go-daml/codegen/model"
+)
+
+// GeneratedPackage is the Go package generated for a Daml package by CodegenDars.
+type GeneratedPackage struct {
+	PackageID string
+	// Dalf is the path of the Daml package in its DAR.
+	Dalf string
+	// GoPackage is the name of the Go package, which is also the directory it is imported from.
+	GoPackage string
+	Import    string
+	Code      string
+}
+
+type darPackage struct {
+	dar        fs.FS
+	sdkVersion string
+	generated  *GeneratedPackage
+}
+
+// CodegenDars generates one Go package for each Daml package of the DARs, so that the types of a
+// library that several DARs depend on are generated once and shared by their bindings. The Go
+// package of a Daml package is named after it and imported from goImportPath/<name>; references
+// to the types of other packages import them. Packages in externalPackages are imported instead
+// of generated, and the packages of the standard library are skipped (see IsStdlibPackage).
+func CodegenDars(dars []fs.FS, goImportPath string, generateHexCodec bool, externalPackages model2.ExternalPackages, fieldHints model2.FieldHints) ([]*GeneratedPackage, error) {
+	packages := make(map[string]*darPackage)
+	for i, dar := range dars {
+		manifest, err := GetManifest(dar)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest of DAR %d: %w", i, err)
+		}
+
+		for _, dalf := range append([]string{manifest.MainDalf}, manifest.Dalfs...) {
+			if IsStdlibPackage(dalf) {
+				continue
+			}
+
+			packageID := GetPackageID(dalf)
+			if packageID == "" {
+				return nil, fmt.Errorf("could not extract package ID from dalf: %s", dalf)
+			}
+			if _, exists := packages[packageID]; exists {
+				continue
+			}
+			if _, exists := externalPackages.Packages[packageID]; exists {
+				continue
+			}
+
+			packages[packageID] = &darPackage{
+				dar:        dar,
+				sdkVersion: manifest.SdkVersion,
+				generated:  &GeneratedPackage{PackageID: packageID, Dalf: dalf},
+			}
+		}
+	}
+
+	packageIDs := make([]string, 0, len(packages))
+	for packageID := range packages {
+		packageIDs = append(packageIDs, packageID)
+	}
+	sort.Strings(packageIDs)
+
+	// Name the Go packages after the Daml packages, telling apart versions of the same package by their ID
+	imports := model2.ExternalPackages{Packages: maps.Clone(externalPackages.Packages)}
+	if imports.Packages == nil {
+		imports.Packages = make(map[string]model2.ExternalPackage)
+	}
+	usedNames := make(map[string]bool)
+	for _, packageID := range packageIDs {
+		generated := packages[packageID].generated
+		generated.GoPackage = goPackageName(GetPackageName(generated.Dalf))
+		if usedNames[generated.GoPackage] {
+			generated.GoPackage += "_" + packageID[:8]
+		}
+		usedNames[generated.GoPackage] = true
+		generated.Import = path.Join(goImportPath, generated.GoPackage)
+
+		imports.Packages[packageID] = model2.ExternalPackage{Import: generated.Import, Alias: generated.GoPackage}
+	}
+
+	// Templates implement the interfaces of other packages, whose choices are looked up by module
+	interfaces := make(map[string]map[string]model2.InterfaceMap, len(packageIDs))
+	for _, packageID := range packageIDs {
+		pkg := packages[packageID]
+		manifest := &model2.Manifest{SdkVersion: pkg.sdkVersion, MainDalf: pkg.generated.Dalf}
+		interfaces[packageID] = dalfInterfaces([]string{pkg.generated.Dalf}, pkg.dar, manifest)
+	}
+
+	declared := make(declaredTypes)
+	result := make([]*GeneratedPackage, 0, len(packageIDs))
+	for _, packageID := range packageIDs {
+		pkg := packages[packageID]
+		generated := pkg.generated
+
+		otherPackages := model2.ExternalPackages{Packages: maps.Clone(imports.Packages)}
+		delete(otherPackages.Packages, packageID)
+
+		// The interfaces of the package itself take precedence over same-named modules of other packages
+		ifcByModule := make(map[string]model2.InterfaceMap)
+		for _, otherID := range packageIDs {
+			if otherID != packageID {
+				maps.Copy(ifcByModule, interfaces[otherID])
+			}
+		}
+		maps.Copy(ifcByModule, interfaces[packageID])
+
+		manifest := &model2.Manifest{SdkVersion: pkg.sdkVersion, MainDalf: generated.Dalf}
+		code, err := codegenDalfs([]string{generated.Dalf}, pkg.dar, generated.GoPackage, manifest, generateHexCodec, otherPackages, fieldHints, ifcByModule, declared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate package %s: %w", generated.GoPackage, err)
+		}
+		if _, ok := code[generated.Dalf]; !ok {
+			return nil, fmt.Errorf("failed to generate package %s from dalf: %s", generated.GoPackage, generated.Dalf)
+		}
+
+		generated.Code = code[generated.Dalf]
+		result = append(result, generated)
+	}
+
+	if err := checkFieldHints(declared, fieldHints); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// generatedImports are the names of the packages imported by every generated file
+var generatedImports = []string{"big", "bind", "client", "codec", "context", "errors", "fmt", "model", "strings", "types"}
+
+// goPackageName converts a Daml package name such as my-package to a Go package name, my_package.
+// Names that cannot be imported under their own name, such as Go keywords, predeclared identifiers
+// and the imports of the generated code, are suffixed with _daml.
+func goPackageName(damlName string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, damlName)
+
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "daml_" + name
+	}
+	if token.IsKeyword(name) || types.Universe.Lookup(name) != nil || name == "main" || slices.Contains(generatedImports, name) {
+		name += "_daml"
+	}
+	return name
+}
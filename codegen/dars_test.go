@@ -0,0 +1,232 @@
+package codegen
+
+import (
+	"archive/zip"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/smartcontractkit/go-daml/codegen/model"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	allKindsOfPackageID      = "6d7e83e81a0a7960eec37340f5b11e7a61606bd9161f413684bc345c3f387948"
+	allKindsOfSDK33PackageID = "ddf0d6396a862eaa7f8d647e39d090a6b04c4a3fd6736aa1730ebc9fca6be664"
+	amuletsPackageID         = "ee24e688b09f26fbe93199692613c670d4d50452a753ff62ba95e06c05284be4"
+)
+
+func openTestDar(t *testing.T, darPath string) (*zip.ReadCloser, *model.Manifest) {
+	reader, err := zip.OpenReader(darPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { reader.Close() })
+
+	manifest, err := GetManifest(reader)
+	require.NoError(t, err)
+
+	return reader, manifest
+}
+
+func readDalf(t *testing.T, dar fs.FS, dalf string) []byte {
+	file, err := dar.Open(dalf)
+	require.NoError(t, err)
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return content
+}
+
+// dependentDar returns a DAR of the amulets package that depends on the all-kinds-of package of dependency
+func dependentDar(t *testing.T, dependency fs.FS, dependencyDalf string) fs.FS {
+	amulets, amuletsManifest := openTestDar(t, "../test-data/amulets-interface-test-1.0.0_lf.dar")
+
+	return fstest.MapFS{
+		"META-INF/MANIFEST.MF": {Data: []byte("Manifest-Version: 1.0\n" +
+			"Created-By: damlc\n" +
+			"Name: amulets-interface-test-1.0.0\n" +
+			"Sdk-Version: " + amuletsManifest.SdkVersion + "\n" +
+			"Main-Dalf: " + amuletsManifest.MainDalf + "\n" +
+			"Dalfs: " + amuletsManifest.MainDalf + ", " + dependencyDalf + "\n")},
+		amuletsManifest.MainDalf: {Data: readDalf(t, amulets, amuletsManifest.MainDalf)},
+		dependencyDalf:           {Data: readDalf(t, dependency, dependencyDalf)},
+	}
+}
+
+// renamedDar returns the DAR at darPath with the dalf of the package named oldName renamed to newName
+func renamedDar(t *testing.T, darPath, oldName, newName string) fs.FS {
+	dar, manifest := openTestDar(t, darPath)
+
+	rename := func(dalf string) string {
+		if GetPackageName(dalf) != oldName {
+			return dalf
+		}
+		dir, file := path.Split(dalf)
+		return dir + newName + strings.TrimPrefix(file, oldName)
+	}
+
+	renamed := fstest.MapFS{}
+	dalfs := make([]string, 0, len(manifest.Dalfs))
+	for _, dalf := range manifest.Dalfs {
+		renamed[rename(dalf)] = &fstest.MapFile{Data: readDalf(t, dar, dalf)}
+		dalfs = append(dalfs, rename(dalf))
+	}
+	renamed["META-INF/MANIFEST.MF"] = &fstest.MapFile{Data: []byte("Manifest-Version: 1.0\n" +
+		"Created-By: damlc\n" +
+		"Sdk-Version: " + manifest.SdkVersion + "\n" +
+		"Main-Dalf: " + rename(manifest.MainDalf) + "\n" +
+		"Dalfs: " + strings.Join(dalfs, ", ") + "\n")}
+	return renamed
+}
+
+func TestCodegenDars(t *testing.T) {
+	allKindsOf, allKindsOfManifest := openTestDar(t, "../test-data/all-kinds-of-1.0.0_lf.dar")
+	amulets := dependentDar(t, allKindsOf, allKindsOfManifest.MainDalf)
+
+	// The shared all-kinds-of package is generated once
+	packages, err := CodegenDars([]fs.FS{allKindsOf, amulets}, "github.com/org/repo/generated", false, model.ExternalPackages{}, model.FieldHints{})
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+
+	require.Equal(t, allKindsOfPackageID, packages[0].PackageID)
+	require.Equal(t, allKindsOfManifest.MainDalf, packages[0].Dalf)
+	require.Equal(t, "all_kinds_of", packages[0].GoPackage)
+	require.Equal(t, "github.com/org/repo/generated/all_kinds_of", packages[0].Import)
+	require.Contains(t, packages[0].Code, "package all_kinds_of\n")
+	require.Contains(t, packages[0].Code, `PackageID   = "`+allKindsOfPackageID+`"`)
+
+	require.Equal(t, amuletsPackageID, packages[1].PackageID)
+	require.Equal(t, "amulets_interface_test", packages[1].GoPackage)
+	require.Contains(t, packages[1].Code, "package amulets_interface_test\n")
+	require.Contains(t, packages[1].Code, `PackageID   = "`+amuletsPackageID+`"`)
+
+	// Packages that are already generated elsewhere are not generated again
+	packages, err = CodegenDars([]fs.FS{amulets}, "github.com/org/repo/generated", false, model.ExternalPackages{
+		Packages: map[string]model.ExternalPackage{allKindsOfPackageID: {Import: "github.com/org/shared/all_kinds_of", Alias: "all_kinds_of"}},
+	}, model.FieldHints{})
+	require.NoError(t, err)
+	require.Len(t, packages, 1)
+	require.Equal(t, amuletsPackageID, packages[0].PackageID)
+
+	// Versions of a package with the same name are told apart by their package ID
+	allKindsOfSDK33, allKindsOfSDK33Manifest := openTestDar(t, "../test-data/all-kinds-of-1.0.0.dar")
+	packages, err = CodegenDars([]fs.FS{allKindsOf, allKindsOfSDK33}, "github.com/org/repo/generated", false, model.ExternalPackages{}, model.FieldHints{})
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+	require.Equal(t, "all_kinds_of", packages[0].GoPackage)
+	require.Equal(t, allKindsOfSDK33Manifest.MainDalf, packages[1].Dalf)
+	require.Equal(t, "all_kinds_of_"+allKindsOfSDK33PackageID[:8], packages[1].GoPackage)
+	require.Equal(t, "github.com/org/repo/generated/all_kinds_of_"+allKindsOfSDK33PackageID[:8], packages[1].Import)
+
+	// Field hints are checked against the types of all packages
+	_, err = CodegenDars([]fs.FS{allKindsOf, amulets}, "github.com/org/repo/generated", false, model.ExternalPackages{}, model.FieldHints{
+		TypeFieldEncodings: map[string]map[string]model.FieldEncoding{
+			"AllKindsOf.OneOfEverything": {"someText": model.FieldEncodingBytes},
+			"Amulets.Missing":            {"owner": model.FieldEncodingBytes},
+		},
+	})
+	require.EqualError(t, err, "field hints refer to unknown type Amulets.Missing")
+}
+
+func TestCodegenDarsDependencies(t *testing.T) {
+	dar, _ := openTestDar(t, "../test-data/quickstart-finance-0.0.1_lf1.dar")
+	dir, importPath := generatedDir(t)
+
+	packages, err := CodegenDars([]fs.FS{dar}, importPath, false, model.ExternalPackages{}, model.FieldHints{})
+	require.NoError(t, err)
+	require.Len(t, packages, 17)
+
+	code := map[string]string{}
+	files := map[string]string{}
+	for _, pkg := range packages {
+		require.Equal(t, importPath+"/"+pkg.GoPackage, pkg.Import)
+		code[pkg.GoPackage] = pkg.Code
+		files[pkg.GoPackage+"/"+pkg.GoPackage+".go"] = pkg.Code
+	}
+
+	// Types and interfaces of the dependencies are referred to through the packages generated for them
+	require.Contains(t, code["quickstart_finance"], `daml_finance_interface_types_common "`+importPath+`/daml_finance_interface_types_common"`)
+	require.Contains(t, code["quickstart_finance"], "daml_finance_interface_types_common.InstrumentKey")
+	require.Contains(t, code["daml_finance_account"], `daml_finance_interface_account "`+importPath+`/daml_finance_interface_account"`)
+	require.Contains(t, code["daml_finance_account"], "var _ daml_finance_interface_account.IAccount = (*Account)(nil)")
+	require.Contains(t, code["daml_finance_interface_types_date"], "[]types.DAY_OF_WEEK")
+
+	writeGenerated(t, dir, files)
+	runGenerated(t, dir, "vet")
+}
+
+func TestCodegenDarsLibraryNames(t *testing.T) {
+	// A library whose name contains prim and stdlib, but is not part of the standard library
+	dar := renamedDar(t, "../test-data/quickstart-finance-0.0.1_lf1.dar", "daml-finance-interface-types-common", "primitives-stdlib")
+	dir, importPath := generatedDir(t)
+
+	packages, err := CodegenDars([]fs.FS{dar}, importPath, false, model.ExternalPackages{}, model.FieldHints{})
+	require.NoError(t, err)
+	require.Len(t, packages, 17)
+
+	files := map[string]string{}
+	for _, pkg := range packages {
+		files[pkg.GoPackage+"/"+pkg.GoPackage+".go"] = pkg.Code
+	}
+	require.Contains(t, files, "primitives_stdlib/primitives_stdlib.go")
+	require.Contains(t, files["quickstart_finance/quickstart_finance.go"], `primitives_stdlib "`+importPath+`/primitives_stdlib"`)
+
+	writeGenerated(t, dir, files)
+	runGenerated(t, dir, "vet")
+}
+
+func TestIsStdlibPackage(t *testing.T) {
+	for _, dalf := range []string{
+		"dep/daml-prim-75b070729b1fca5b00b4ac2bca7d4fbe1a1c9ac0e4b1e6a2c8c9c2ed3b0a4e3f.dalf",
+		"dep/daml-prim-DA-Types-5a4f9d6a6f2e3b5c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c.dalf",
+		"dep/daml-stdlib-2.9.1-8c7e4a9f0b1d2c3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e.dalf",
+		"dep/daml-stdlib-DA-Date-Types-1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b.dalf",
+		"dep/ghc-stdlib-9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b.dalf",
+	} {
+		require.True(t, IsStdlibPackage(dalf), dalf)
+	}
+	for _, dalf := range []string{
+		"dep/primitives-1.0.0-5a4f9d6a6f2e3b5c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c.dalf",
+		"dep/primary-market-1.0.0-8c7e4a9f0b1d2c3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e.dalf",
+		"dep/my-stdlib-1.0.0-1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b.dalf",
+		"dep/daml-script-2.9.1-9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b.dalf",
+	} {
+		require.False(t, IsStdlibPackage(dalf), dalf)
+	}
+}
+
+func TestGoPackageName(t *testing.T) {
+	require.Equal(t, "all_kinds_of", goPackageName("all-kinds-of"))
+	require.Equal(t, "my_package_v2", goPackageName("My.Package-v2"))
+	require.Equal(t, "daml_3d", goPackageName("3d"))
+	require.Equal(t, "daml_", goPackageName(""))
+
+	// Names that would not compile or would shadow an identifier of the generated code
+	require.Equal(t, "type_daml", goPackageName("type"))
+	require.Equal(t, "string_daml", goPackageName("String"))
+	require.Equal(t, "main_daml", goPackageName("main"))
+	require.Equal(t, "types_daml", goPackageName("types"))
+	require.Equal(t, "model_daml", goPackageName("model"))
+}
+
+func TestGeneratedImports(t *testing.T) {
+	code, err := Bind("main", &model.Package{Name: "test-package", Structs: map[string]*model.TmplStruct{}}, "3.4.10", true, false)
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", code, parser.ImportsOnly)
+	require.NoError(t, err)
+
+	var imports []string
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		require.NoError(t, err)
+		imports = append(imports, path.Base(importPath))
+	}
+	require.ElementsMatch(t, generatedImports, imports)
+}
@@ -0,0 +1,146 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	model2 "github.com/smartcontractkit/go-daml/codegen/model"
+)
+
+// declaredTypes records the fields and constructors of the data types declared in the processed
+// dalfs, keyed by fully-qualified type name (e.g. "Module.TypeName").
+type declaredTypes map[string]map[string]bool
+
+func (d declaredTypes) add(structs map[string]*model2.TmplStruct) {
+	for _, structDef := range structs {
+		if structDef.IsInterface || structDef.ModuleName == "" {
+			continue
+		}
+		fields := make(map[string]bool)
+		for _, field := range structDef.Fields {
+			fields[field.Name] = true
+		}
+		d[typeKey(structDef)] = fields
+	}
+}
+
+func typeKey(structDef *model2.TmplStruct) string {
+	return structDef.ModuleName + "." + structDef.DAMLName
+}
+
+// applyTypeFieldEncodings overrides the encoding of the fields configured in
+// FieldHints.TypeFieldEncodings.
+func applyTypeFieldEncodings(structs map[string]*model2.TmplStruct, encodings map[string]map[string]model2.FieldEncoding) {
+	if len(encodings) == 0 {
+		return
+	}
+	for _, structDef := range structs {
+		if structDef.IsInterface || structDef.RawType == "Enum" {
+			continue
+		}
+		typeEncodings, ok := encodings[typeKey(structDef)]
+		if !ok {
+			continue
+		}
+		for _, field := range structDef.Fields {
+			if encoding, ok := typeEncodings[field.Name]; ok {
+				field.SetEncoding(encoding)
+			}
+		}
+	}
+}
+
+// checkFieldHints returns an error if the field hints refer to types, fields, constructors or
+// Params records that are not declared in the processed dalfs, as such hints would change nothing.
+func checkFieldHints(declared declaredTypes, fieldHints model2.FieldHints) error {
+	var unknown []string
+	for typeName, encodings := range fieldHints.TypeFieldEncodings {
+		fields, ok := declared[typeName]
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("type %s", typeName))
+			continue
+		}
+		for field := range encodings {
+			if !fields[field] {
+				unknown = append(unknown, fmt.Sprintf("field %s of type %s", field, typeName))
+			}
+		}
+	}
+
+	// Field name hints apply to the fields of every type
+	for _, names := range []struct {
+		encoding model2.FieldEncoding
+		fields   map[string]bool
+	}{
+		{model2.FieldEncodingBytes, fieldHints.BytesFields},
+		{model2.FieldEncodingBytes16, fieldHints.BytesHexFields},
+		{model2.FieldEncodingUint32, fieldHints.Uint32Fields},
+		{model2.FieldEncodingUint32List, fieldHints.Uint32ListFields},
+		{model2.FieldEncodingDecimal, fieldHints.DecimalFields},
+	} {
+		for field := range names.fields {
+			if !declared.hasField(field) {
+				unknown = append(unknown, fmt.Sprintf("%s field %s", names.encoding, field))
+			}
+		}
+	}
+
+	for _, tagMaps := range []struct {
+		kind string
+		tags map[string]map[string]byte
+	}{
+		{"variant", fieldHints.VariantTagByteMap},
+		{"enum", fieldHints.EnumTagByteMap},
+	} {
+		for typeName, tags := range tagMaps.tags {
+			constructors, ok := declared[typeName]
+			if !ok {
+				unknown = append(unknown, fmt.Sprintf("%s %s", tagMaps.kind, typeName))
+				continue
+			}
+			for constructor := range tags {
+				if !constructors[constructor] {
+					unknown = append(unknown, fmt.Sprintf("constructor %s of %s %s", constructor, tagMaps.kind, typeName))
+				}
+			}
+		}
+	}
+
+	for name := range fieldHints.ChoiceParamEncoderNames {
+		if !declared.hasType(name + "Params") {
+			unknown = append(unknown, fmt.Sprintf("record %sParams of choice param encoder %s", name, name))
+		}
+	}
+	for choice, params := range fieldHints.ChoiceOperationDataParams {
+		if !declared.hasType(params) {
+			unknown = append(unknown, fmt.Sprintf("record %s of operation data params of %s", params, choice))
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("field hints refer to unknown %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// hasField reports whether any declared type has the field.
+func (d declaredTypes) hasField(field string) bool {
+	for _, fields := range d {
+		if fields[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasType reports whether a type named name is declared in any module.
+func (d declaredTypes) hasType(name string) bool {
+	for typeName := range d {
+		if strings.HasSuffix(typeName, "."+name) {
+			return true
+		}
+	}
+	return false
+}
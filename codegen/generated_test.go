@@ -0,0 +1,65 @@
+package codegen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// generatedDir creates a temporary directory of the module under testdata for generated code,
+// and returns it together with its import path.
+func generatedDir(t *testing.T) (string, string) {
+	t.Helper()
+
+	if err := os.MkdirAll("testdata", 0o755); err != nil {
+		t.Fatalf("failed to create testdata: %v", err)
+	}
+	dir, err := os.MkdirTemp("testdata", "generated")
+	if err != nil {
+		t.Fatalf("failed to create output directory: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+		_ = os.Remove("testdata")
+	})
+
+	return dir, "github.com/smartcontractkit/go-daml/codegen/" + filepath.ToSlash(dir)
+}
+
+// writeGenerated writes the files, keyed by slash separated path, into dir.
+func writeGenerated(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// runGenerated runs the go command, e.g. vet or test, on the packages in dir.
+func runGenerated(t *testing.T, dir string, command string) {
+	t.Helper()
+
+	cmd := exec.Command("go", command, "./"+filepath.ToSlash(dir)+"/...")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go %s of the generated code failed: %v\n%s", command, err, out)
+	}
+}
+
+// vetGenerated writes the generated files, keyed by slash separated path, into a temporary directory
+// of the module under testdata and type checks them with go vet against the packages of the module.
+// It returns the import path of the temporary directory.
+func vetGenerated(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir, importPath := generatedDir(t)
+	writeGenerated(t, dir, files)
+	runGenerated(t, dir, "vet")
+	return importPath
+}
@@ -47,10 +47,20 @@ type TmplField struct {
 	IsDecimal    bool // True if NUMERIC field should use MCMS encodeDecimal (sign byte + 10^10 shift; hex:"decimal" tag)
 }
 
+// SetEncoding replaces the hex encoding of the field. As with the DecimalFields hint, only
+// scalar NUMERIC fields use the decimal encoding.
+func (f *TmplField) SetEncoding(encoding FieldEncoding) {
+	f.IsBytes = encoding == FieldEncodingBytes
+	f.IsBytesHex = encoding == FieldEncodingBytes16
+	f.IsUint32 = encoding == FieldEncodingUint32
+	f.IsUint32List = encoding == FieldEncodingUint32List
+	f.IsDecimal = encoding == FieldEncodingDecimal && f.Type != nil && f.Type.GoType() == "types.NUMERIC"
+}
+
 type TmplChoice struct {
-	Name    string
-	ArgType DamlType
-	// ReturnType        DamlType // Not used for now
+	Name              string
+	ArgType           DamlType
+	ReturnType        DamlType // The Daml-LF return type of the choice
 	Interface         DamlType // If this choice is implementing an interface
 	InterfaceName     string   // The Go name of the interface this choice comes from (e.g., "ITransferable")
 	InterfaceDAMLName string   // The original DAML name of the interface (e.g., "Transferable")
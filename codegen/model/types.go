@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -121,6 +122,33 @@ type FieldHints struct {
 	// used in operationData when they don't follow the {Choice}Params naming convention.
 	// Example: "ProposeAdministrator" -> "ProposeAdminParams"
 	ChoiceOperationDataParams map[string]string
+	// TypeFieldEncodings overrides the encoding of the fields of specific templates, records and
+	// variants, keyed by fully-qualified type name (e.g. "Module.TypeName") and field name. They take
+	// precedence over the field name hints above, which apply to fields of every type.
+	// Example: {"MyModule.Transfer": {"recipient": FieldEncodingBytes16, "amount": FieldEncodingDefault}}
+	TypeFieldEncodings map[string]map[string]FieldEncoding
+}
+
+// FieldEncoding is the hex encoding of a field, named after its hex struct tag.
+type FieldEncoding string
+
+const (
+	// FieldEncodingDefault encodes the field according to its type, ignoring field name hints.
+	FieldEncodingDefault    FieldEncoding = "default"
+	FieldEncodingBytes      FieldEncoding = "bytes"
+	FieldEncodingBytes16    FieldEncoding = "bytes16"
+	FieldEncodingUint32     FieldEncoding = "uint32"
+	FieldEncodingUint32List FieldEncoding = "[]uint32"
+	FieldEncodingDecimal    FieldEncoding = "decimal"
+)
+
+// ParseFieldEncoding returns the field encoding named s.
+func ParseFieldEncoding(s string) (FieldEncoding, error) {
+	switch e := FieldEncoding(s); e {
+	case FieldEncodingDefault, FieldEncodingBytes, FieldEncodingBytes16, FieldEncodingUint32, FieldEncodingUint32List, FieldEncodingDecimal:
+		return e, nil
+	}
+	return "", fmt.Errorf("unknown field encoding %q, expected one of default, bytes, bytes16, uint32, []uint32, decimal", s)
 }
 
 type Int64 struct {
@@ -296,6 +324,38 @@ func (t Set) GoType() string {
 	return "types.SET"
 }
 
+type Tuple2 struct {
+	noImport
+}
+
+func (t Tuple2) GoType() string {
+	return "types.TUPLE2"
+}
+
+type Tuple3 struct {
+	noImport
+}
+
+func (t Tuple3) GoType() string {
+	return "types.TUPLE3"
+}
+
+type DayOfWeek struct {
+	noImport
+}
+
+func (t DayOfWeek) GoType() string {
+	return "types.DAY_OF_WEEK"
+}
+
+type Month struct {
+	noImport
+}
+
+func (t Month) GoType() string {
+	return "types.MONTH"
+}
+
 type Enum struct {
 	noImport
 }
@@ -153,6 +153,12 @@ func Bind(genPkg string, pkg *model.Package, sdkVersion string, isMainDalf bool,
 			}
 			return s.Name
 		},
+		"hasResultDecoder": func(c *model.TmplChoice) bool {
+			return isDecodableResultType(c.ReturnType, pkg.Structs)
+		},
+		"clientMethodName": func(s *model.TmplStruct, c *model.TmplChoice) string {
+			return clientChoiceMethodNames(s)[c.Name]
+		},
 		// hasKey checks if a key exists in a map[string]byte (for VariantTagMapping)
 		"hasKey": func(m map[string]byte, key string) bool {
 			_, ok := m[key]
@@ -265,6 +271,26 @@ func isEncoderRecord(s *model.TmplStruct) bool {
 	return s != nil && !s.IsInterface && !s.IsTemplate && s.RawType == "Record"
 }
 
+// templateClientMembers are the methods and the field that <Template>Client gets from its embedded
+// *bind.TemplateClient, which choice methods must not redeclare or shadow
+var templateClientMembers = []string{"Create", "Exercise", "Submit", "TemplateClient"}
+
+// clientChoiceMethodNames returns the <Template>Client method names of the choices of s by choice
+// name, suffixing choices that collide with the members of the embedded client with Choice.
+func clientChoiceMethodNames(s *model.TmplStruct) map[string]string {
+	used := make(map[string]bool, len(templateClientMembers)+len(s.Choices))
+	for _, name := range templateClientMembers {
+		used[name] = true
+	}
+
+	names := make(map[string]string, len(s.Choices))
+	for _, choice := range s.Choices {
+		name := capitalize(choice.Name)
+		names[choice.Name] = reserveMethodName(name, name+"Choice", used)
+	}
+	return names
+}
+
 func reserveMethodName(preferred string, fallback string, used map[string]bool) string {
 	for _, name := range []string{preferred, fallback} {
 		if name == "" || used[name] {
@@ -287,6 +313,34 @@ func reserveMethodName(preferred string, fallback string, used map[string]bool)
 	}
 }
 
+// isDecodableResultType reports whether a typed result decoder can be generated for a choice
+// returning t. Unit results carry no data, and types from packages that are not generated
+// cannot be referred to.
+func isDecodableResultType(t model.DamlType, structs map[string]*model.TmplStruct) bool {
+	switch v := t.(type) {
+	case nil, model.Unit:
+		return false
+	case model.Optional:
+		return isDecodableResultType(v.Inner, structs)
+	case model.List:
+		return isDecodableResultType(v.Inner, structs)
+	case model.GenMap:
+		return (v.Key == nil || isDecodableResultType(v.Key, structs)) &&
+			(v.Value == nil || isDecodableResultType(v.Value, structs))
+	case model.TextMap:
+		return v.Value == nil || isDecodableResultType(v.Value, structs)
+	case model.Unknown:
+		for _, s := range structs {
+			if capitalize(s.Name) == v.GoType() {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
 func capitalize(input string) string {
 	if len(input) == 0 {
 		return input
@@ -1,10 +1,13 @@
 package codegen
 
 import (
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/smartcontractkit/go-daml/codegen/model"
+	"github.com/smartcontractkit/go-daml/pkg/bind"
 )
 
 func TestBind(t *testing.T) {
@@ -249,6 +252,375 @@ func TestBindWithTypedTextMapField(t *testing.T) {
 	}
 }
 
+func TestBindTemplateFromCreatedEvent(t *testing.T) {
+	structs := map[string]*model.TmplStruct{
+		"Iou2": {
+			Name:       "Iou2",
+			DAMLName:   "Iou",
+			ModuleName: "Finance.Iou",
+			RawType:    "Template",
+			IsTemplate: true,
+			Fields: []*model.TmplField{
+				{Name: "issuer", Type: model.Party{}},
+			},
+		},
+	}
+
+	pkg := &model.Package{
+		Name:    "test-package",
+		Structs: structs,
+	}
+
+	result, err := Bind("main", pkg, "3.4.10", true, false)
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	if !strings.Contains(result, "func Iou2FromCreatedEvent(event *model.CreatedEvent) (*Iou2, error) {") {
+		t.Errorf("Generated code should contain Iou2FromCreatedEvent, got:\n%s", result)
+	}
+
+	// The ledger reports the original DAML name, not the deduplicated Go name
+	if !strings.Contains(result, `bind.DecodeCreatedEvent(event, "Finance.Iou", "Iou", &t)`) {
+		t.Error("Generated code should verify the DAML template name when decoding")
+	}
+}
+
+func TestBindTemplateIDUsesDAMLName(t *testing.T) {
+	structs := map[string]*model.TmplStruct{
+		"Iou2": {
+			Name:       "Iou2",
+			DAMLName:   "Iou",
+			ModuleName: "Finance.Iou",
+			RawType:    "Template",
+			IsTemplate: true,
+			Fields: []*model.TmplField{
+				{Name: "issuer", Type: model.Party{}},
+			},
+			Choices: []*model.TmplChoice{
+				{Name: "Archive", ArgType: model.Unit{}, ReturnType: model.Unit{}},
+			},
+		},
+	}
+
+	result, err := Bind("main", &model.Package{Name: "test-package", Structs: structs}, "3.4.10", true, false)
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	// Ledger events, the ACS cache and the template registry match templates by their DAML name
+	expected := []string{
+		`return fmt.Sprintf("#%s:%s:%s", PackageName, "Finance.Iou", "Iou")`,
+		`return fmt.Sprintf("%s:%s:%s", packageID, "Finance.Iou", "Iou")`,
+		`TemplateID: fmt.Sprintf("#%s:%s:%s", PackageName, "Finance.Iou", "Iou"),`,
+		`TemplateID: fmt.Sprintf("#%s:%s:%s", packageID, "Finance.Iou", "Iou"),`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generated code should contain %q, got:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, `"Finance.Iou", "Iou2"`) {
+		t.Error("Generated code should not refer to the template by its Go name")
+	}
+}
+
+func TestBindTemplateDecodeChoiceResult(t *testing.T) {
+	structs := map[string]*model.TmplStruct{
+		"Iou": {
+			Name:       "Iou",
+			ModuleName: "Finance.Iou",
+			RawType:    "Template",
+			IsTemplate: true,
+			Fields: []*model.TmplField{
+				{Name: "issuer", Type: model.Party{}},
+			},
+			Choices: []*model.TmplChoice{
+				{Name: "Archive", ArgType: model.Unit{}, ReturnType: model.Unit{}},
+				{Name: "Iou_Transfer", ArgType: model.Unit{}, ReturnType: model.ContractId{}},
+				{Name: "Iou_Split", ArgType: model.Unit{}, ReturnType: model.Tuple2{}},
+				{Name: "Iou_Summary", ArgType: model.Unit{}, ReturnType: model.Optional{Inner: model.Unknown{String: "Summary"}}},
+				{Name: "Iou_Lookup", ArgType: model.Unit{}, ReturnType: model.Unknown{String: "Either"}},
+			},
+		},
+		"Summary": {
+			Name:       "Summary",
+			ModuleName: "Finance.Iou",
+			RawType:    "Record",
+			Fields: []*model.TmplField{
+				{Name: "total", Type: model.Numeric{}},
+			},
+		},
+	}
+
+	pkg := &model.Package{
+		Name:    "test-package",
+		Structs: structs,
+	}
+
+	result, err := Bind("main", pkg, "3.4.10", true, false)
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	expected := []string{
+		"func (t Iou) DecodeIouTransferResult(event *model.ExercisedEvent) (types.CONTRACT_ID, error) {",
+		`bind.DecodeExerciseResult(event, "Finance.Iou", "Iou", "Iou_Transfer", &result)`,
+		"func (t Iou) DecodeIouSplitResult(event *model.ExercisedEvent) (types.TUPLE2, error) {",
+		"func (t Iou) DecodeIouSummaryResult(event *model.ExercisedEvent) (*Summary, error) {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generated code should contain %q, got:\n%s", want, result)
+		}
+	}
+
+	// Unit results carry no data and types outside the package cannot be named
+	if strings.Contains(result, "DecodeArchiveResult") {
+		t.Error("Generated code should not contain a result decoder for Archive")
+	}
+	if strings.Contains(result, "DecodeIouLookupResult") {
+		t.Error("Generated code should not contain a result decoder for an unresolved return type")
+	}
+}
+
+func TestBindTemplateClient(t *testing.T) {
+	structs := map[string]*model.TmplStruct{
+		"Iou": {
+			Name:       "Iou",
+			ModuleName: "Finance.Iou",
+			RawType:    "Template",
+			IsTemplate: true,
+			Fields: []*model.TmplField{
+				{Name: "issuer", Type: model.Party{}},
+			},
+			Choices: []*model.TmplChoice{
+				{Name: "Archive", ArgType: model.Unit{}, ReturnType: model.Unit{}},
+				{Name: "Transfer", ArgType: model.Unknown{String: "Transfer"}, ReturnType: model.ContractId{}},
+			},
+		},
+		"Transfer": {
+			Name:       "Transfer",
+			ModuleName: "Finance.Iou",
+			RawType:    "Record",
+			Fields: []*model.TmplField{
+				{Name: "newOwner", Type: model.Party{}},
+			},
+		},
+	}
+
+	pkg := &model.Package{
+		Name:    "test-package",
+		Structs: structs,
+	}
+
+	result, err := Bind("main", pkg, "3.4.10", true, false)
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	expected := []string{
+		"func NewIouClient(cl *client.DamlBindingClient, actAs []string, opts ...bind.ClientOption) *IouClient {",
+		`bind.NewTemplateClient(cl.CommandService, "Finance.Iou", "Iou", actAs, opts...)`,
+		"func (c *IouClient) Create(ctx context.Context, t Iou) (types.CONTRACT_ID, error) {",
+		"func (c *IouClient) Archive(ctx context.Context, contractID types.CONTRACT_ID) error {",
+		"func (c *IouClient) Transfer(ctx context.Context, contractID types.CONTRACT_ID, args Transfer) (types.CONTRACT_ID, error) {",
+		"c.TemplateClient.Exercise(ctx, Iou{}.Transfer(string(contractID), args), &result)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generated code should contain %q, got:\n%s", want, result)
+		}
+	}
+
+	if strings.Contains(result, "TransferClient") {
+		t.Error("Generated code should not contain a client for a record")
+	}
+}
+
+func TestBindTemplateClientReservedChoiceNames(t *testing.T) {
+	structs := map[string]*model.TmplStruct{
+		"Account": {
+			Name:       "Account",
+			ModuleName: "Bank.Account",
+			RawType:    "Template",
+			IsTemplate: true,
+			Fields: []*model.TmplField{
+				{Name: "owner", Type: model.Party{}},
+			},
+			Choices: []*model.TmplChoice{
+				{Name: "Create", ArgType: model.Unit{}, ReturnType: model.ContractId{}},
+				{Name: "Exercise", ArgType: model.Unit{}, ReturnType: model.Unit{}},
+				{Name: "Submit", ArgType: model.Unit{}, ReturnType: model.Unit{}},
+			},
+		},
+	}
+
+	pkg := &model.Package{
+		Name:    "test-package",
+		Structs: structs,
+	}
+
+	result, err := Bind("account", pkg, "3.4.10", true, false)
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	expected := []string{
+		"func (c *AccountClient) Create(ctx context.Context, t Account) (types.CONTRACT_ID, error) {",
+		"func (c *AccountClient) CreateChoice(ctx context.Context, contractID types.CONTRACT_ID) (types.CONTRACT_ID, error) {",
+		"func (c *AccountClient) ExerciseChoice(ctx context.Context, contractID types.CONTRACT_ID) error {",
+		"func (c *AccountClient) SubmitChoice(ctx context.Context, contractID types.CONTRACT_ID) error {",
+		"c.TemplateClient.Exercise(ctx, Account{}.Create(string(contractID)), &result)",
+		"c.TemplateClient.Exercise(ctx, Account{}.Exercise(string(contractID)), nil)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generated code should contain %q, got:\n%s", want, result)
+		}
+	}
+
+	vetGenerated(t, map[string]string{"account/account.go": result})
+}
+
+func TestTemplateClientMembers(t *testing.T) {
+	var members []string
+	clientType := reflect.TypeOf(&bind.TemplateClient{})
+	for i := range clientType.NumMethod() {
+		members = append(members, clientType.Method(i).Name)
+	}
+	members = append(members, clientType.Elem().Name())
+
+	if !slices.Equal(members, templateClientMembers) {
+		t.Errorf("templateClientMembers = %v, want %v", templateClientMembers, members)
+	}
+}
+
+func TestBindTemplateRegister(t *testing.T) {
+	structs := map[string]*model.TmplStruct{
+		"Iou": {
+			Name:       "Iou",
+			ModuleName: "Finance.Iou",
+			RawType:    "Template",
+			IsTemplate: true,
+			Fields: []*model.TmplField{
+				{Name: "issuer", Type: model.Party{}},
+			},
+			Choices: []*model.TmplChoice{
+				{Name: "Archive", ArgType: model.Unit{}, ReturnType: model.Unit{}},
+				{Name: "Transfer", ArgType: model.Unknown{String: "Transfer"}, ReturnType: model.ContractId{}},
+			},
+		},
+		"Transfer": {
+			Name:       "Transfer",
+			ModuleName: "Finance.Iou",
+			RawType:    "Record",
+			Fields: []*model.TmplField{
+				{Name: "newOwner", Type: model.Party{}},
+			},
+		},
+	}
+
+	result, err := Bind("main", &model.Package{Name: "test-package", Structs: structs}, "3.4.10", true, false)
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	expected := []string{
+		"func RegisterIou(registry *bind.Registry) {",
+		"bind.RegisterTemplate[Iou](registry)",
+		`bind.RegisterChoice[Iou, types.UNIT](registry, "Archive")`,
+		`bind.RegisterChoice[Iou, Transfer](registry, "Transfer")`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generated code should contain %q, got:\n%s", want, result)
+		}
+	}
+
+	if strings.Contains(result, "func RegisterTransfer(") {
+		t.Error("Generated code should not contain a register function for a record")
+	}
+}
+
+// registeredIouTest decodes ledger events of the DAML template Finance.Iou:Iou, generated as Iou2
+const registeredIouTest = `package iou
+
+import (
+	"testing"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+
+	"github.com/smartcontractkit/go-daml/pkg/bind"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+)
+
+func TestRegisterIou2(t *testing.T) {
+	registry := bind.NewRegistry()
+	RegisterIou2(registry)
+
+	event, err := registry.Decode("update-1", &model.Event{Created: &model.CreatedEvent{
+		ContractID: "00abc",
+		TemplateID: "6d7e83e8:Finance.Iou:Iou",
+		CreateArguments: &v2.Record{Fields: []*v2.RecordField{
+			{Label: "issuer", Value: &v2.Value{Sum: &v2.Value_Party{Party: "alice"}}},
+		}},
+	}})
+	if err != nil {
+		t.Fatalf("failed to decode created event: %v", err)
+	}
+	created, ok := event.(*bind.Created[Iou2])
+	if !ok || created.Payload.Issuer != "alice" {
+		t.Fatalf("unexpected event %#v", event)
+	}
+
+	event, err = registry.Decode("update-2", &model.Event{Exercised: &model.ExercisedEvent{
+		ContractID:     "00abc",
+		TemplateID:     "6d7e83e8:Finance.Iou:Iou",
+		Choice:         "Archive",
+		ChoiceArgument: &v2.Value{Sum: &v2.Value_Record{Record: &v2.Record{}}},
+	}})
+	if err != nil {
+		t.Fatalf("failed to decode exercised event: %v", err)
+	}
+	if _, ok := event.(*bind.Exercised[Iou2, types.UNIT]); !ok {
+		t.Fatalf("unexpected event %#v", event)
+	}
+}
+`
+
+func TestBindTemplateRegisterRenamedTemplate(t *testing.T) {
+	structs := map[string]*model.TmplStruct{
+		"Iou2": {
+			Name:       "Iou2",
+			DAMLName:   "Iou",
+			ModuleName: "Finance.Iou",
+			RawType:    "Template",
+			IsTemplate: true,
+			Fields: []*model.TmplField{
+				{Name: "issuer", Type: model.Party{}},
+			},
+			Choices: []*model.TmplChoice{
+				{Name: "Archive", ArgType: model.Unit{}, ReturnType: model.Unit{}},
+			},
+		},
+	}
+
+	result, err := Bind("iou", &model.Package{Name: "test-package", Structs: structs}, "3.4.10", true, false)
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	// The registry matches the ledger events by the DAML template name, not the Go type name
+	dir, _ := generatedDir(t)
+	writeGenerated(t, dir, map[string]string{
+		"iou/iou.go":      result,
+		"iou/iou_test.go": registeredIouTest,
+	})
+	runGenerated(t, dir, "test")
+}
+
 func TestCapitalize(t *testing.T) {
 	tests := []struct {
 		input    string
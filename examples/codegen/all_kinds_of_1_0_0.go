@@ -1,12 +1,14 @@
 package codegen_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 
 	"github.com/smartcontractkit/go-daml/pkg/bind"
+	"github.com/smartcontractkit/go-daml/pkg/client"
 	"github.com/smartcontractkit/go-daml/pkg/codec"
 	"github.com/smartcontractkit/go-daml/pkg/model"
 	"github.com/smartcontractkit/go-daml/pkg/types"
@@ -19,6 +21,10 @@ var (
 	_ = strings.NewReader
 	_ = model.Command{}
 	_ bind.BoundTemplate
+	_ = context.Background
+	_ *client.DamlBindingClient
+	_ = codec.NewJsonCodec
+	_ types.UNIT
 )
 
 const (
@@ -107,8 +113,8 @@ var _ types.ENUM = Color("")
 
 // MappyContract is a Template type
 type MappyContract struct {
-	Operator types.PARTY   `json:"operator"`
-	Value    types.TEXTMAP `json:"value"`
+	Operator types.PARTY           `json:"operator"`
+	Value    map[string]types.TEXT `json:"value"`
 }
 
 // GetTemplateID returns the template ID for this template using the package name
@@ -121,6 +127,16 @@ func (t MappyContract) GetTemplateIDWithPackageID(packageID string) string {
 	return fmt.Sprintf("%s:%s:%s", packageID, "AllKindsOf", "MappyContract")
 }
 
+// MappyContractFromCreatedEvent decodes the create arguments of a MappyContract contract
+// after verifying that the event was created from this template
+func MappyContractFromCreatedEvent(event *model.CreatedEvent) (*MappyContract, error) {
+	var t MappyContract
+	if err := bind.DecodeCreatedEvent(event, "AllKindsOf", "MappyContract", &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 // CreateCommand returns a CreateCommand for this template using the package name
 func (t MappyContract) CreateCommand() *model.CreateCommand {
 	args := make(map[string]any)
@@ -130,11 +146,10 @@ func (t MappyContract) CreateCommand() *model.CreateCommand {
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
 	args["value"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.Value).(mapper); ok {
-			return m.ToMap()
+		if t.Value == nil {
+			return map[string]any{"_type": "textmap", "value": map[string]any{}}
 		}
-		return t.Value
+		return map[string]any{"_type": "textmap", "value": t.Value}
 	}()
 
 	return &model.CreateCommand{
@@ -152,11 +167,10 @@ func (t MappyContract) CreateCommandWithPackageID(packageID string) *model.Creat
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
 	args["value"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.Value).(mapper); ok {
-			return m.ToMap()
+		if t.Value == nil {
+			return map[string]any{"_type": "textmap", "value": map[string]any{}}
 		}
-		return t.Value
+		return map[string]any{"_type": "textmap", "value": t.Value}
 	}()
 
 	return &model.CreateCommand{
@@ -198,6 +212,36 @@ func (t MappyContract) ArchiveWithPackageID(contractID string, packageID string)
 	}
 }
 
+// MappyContractClient submits MappyContract commands and decodes their results
+type MappyContractClient struct {
+	*bind.TemplateClient
+}
+
+// NewMappyContractClient creates a MappyContractClient submitting commands as the actAs parties
+func NewMappyContractClient(cl *client.DamlBindingClient, actAs []string, opts ...bind.ClientOption) *MappyContractClient {
+	return &MappyContractClient{
+		TemplateClient: bind.NewTemplateClient(cl.CommandService, "AllKindsOf", "MappyContract", actAs, opts...),
+	}
+}
+
+// Create creates a MappyContract contract and returns its contract ID
+func (c *MappyContractClient) Create(ctx context.Context, t MappyContract) (types.CONTRACT_ID, error) {
+	return c.TemplateClient.Create(ctx, t.CreateCommand())
+}
+
+// Archive exercises the Archive choice on the MappyContract contract contractID
+func (c *MappyContractClient) Archive(ctx context.Context, contractID types.CONTRACT_ID) error {
+	_, err := c.TemplateClient.Exercise(ctx, MappyContract{}.Archive(string(contractID)), nil)
+	return err
+}
+
+// RegisterMappyContract registers the MappyContract template and its choices with registry
+// for decoding update streams into typed events
+func RegisterMappyContract(registry *bind.Registry) {
+	bind.RegisterTemplate[MappyContract](registry)
+	bind.RegisterChoice[MappyContract, types.UNIT](registry, "Archive")
+}
+
 // MyPair is a Record type
 type MyPair struct {
 	Left  any `json:"left"`
@@ -208,21 +252,9 @@ type MyPair struct {
 func (t MyPair) ToMap() map[string]any {
 	m := make(map[string]any)
 
-	m["left"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.Left).(mapper); ok {
-			return m.ToMap()
-		}
-		return t.Left
-	}()
+	m["left"] = model.NestedToDAMLValue(t.Left)
 
-	m["right"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.Right).(mapper); ok {
-			return m.ToMap()
-		}
-		return t.Right
-	}()
+	m["right"] = model.NestedToDAMLValue(t.Right)
 
 	return m
 }
@@ -267,6 +299,16 @@ func (t OneOfEverything) GetTemplateIDWithPackageID(packageID string) string {
 	return fmt.Sprintf("%s:%s:%s", packageID, "AllKindsOf", "OneOfEverything")
 }
 
+// OneOfEverythingFromCreatedEvent decodes the create arguments of a OneOfEverything contract
+// after verifying that the event was created from this template
+func OneOfEverythingFromCreatedEvent(event *model.CreatedEvent) (*OneOfEverything, error) {
+	var t OneOfEverything
+	if err := bind.DecodeCreatedEvent(event, "AllKindsOf", "OneOfEverything", &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 // CreateCommand returns a CreateCommand for this template using the package name
 func (t OneOfEverything) CreateCommand() *model.CreateCommand {
 	args := make(map[string]any)
@@ -327,44 +369,20 @@ func (t OneOfEverything) CreateCommand() *model.CreateCommand {
 	}()
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
-	args["someSimplePair"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.SomeSimplePair).(mapper); ok {
-			return m.ToMap()
-		}
-		return t.SomeSimplePair
-	}()
+	args["someSimplePair"] = model.NestedToDAMLValue(t.SomeSimplePair)
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
-	args["someNestedPair"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.SomeNestedPair).(mapper); ok {
-			return m.ToMap()
-		}
-		return t.SomeNestedPair
-	}()
+	args["someNestedPair"] = model.NestedToDAMLValue(t.SomeNestedPair)
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
-	args["someUglyNesting"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.SomeUglyNesting).(mapper); ok {
-			return m.ToMap()
-		}
-		return t.SomeUglyNesting
-	}()
+	args["someUglyNesting"] = model.NestedToDAMLValue(t.SomeUglyNesting)
 
 	if t.SomeMeasurement != "" {
 		args["someMeasurement"] = t.SomeMeasurement
 	}
 
 	if t.SomeEnum != "" {
-		args["someEnum"] = func() any {
-			type mapper interface{ ToMap() map[string]any }
-			if m, ok := any(t.SomeEnum).(mapper); ok {
-				return m.ToMap()
-			}
-			return t.SomeEnum
-		}()
+		args["someEnum"] = model.NestedToDAMLValue(t.SomeEnum)
 	}
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
@@ -436,44 +454,20 @@ func (t OneOfEverything) CreateCommandWithPackageID(packageID string) *model.Cre
 	}()
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
-	args["someSimplePair"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.SomeSimplePair).(mapper); ok {
-			return m.ToMap()
-		}
-		return t.SomeSimplePair
-	}()
+	args["someSimplePair"] = model.NestedToDAMLValue(t.SomeSimplePair)
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
-	args["someNestedPair"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.SomeNestedPair).(mapper); ok {
-			return m.ToMap()
-		}
-		return t.SomeNestedPair
-	}()
+	args["someNestedPair"] = model.NestedToDAMLValue(t.SomeNestedPair)
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
-	args["someUglyNesting"] = func() any {
-		type mapper interface{ ToMap() map[string]any }
-		if m, ok := any(t.SomeUglyNesting).(mapper); ok {
-			return m.ToMap()
-		}
-		return t.SomeUglyNesting
-	}()
+	args["someUglyNesting"] = model.NestedToDAMLValue(t.SomeUglyNesting)
 
 	if t.SomeMeasurement != "" {
 		args["someMeasurement"] = t.SomeMeasurement
 	}
 
 	if t.SomeEnum != "" {
-		args["someEnum"] = func() any {
-			type mapper interface{ ToMap() map[string]any }
-			if m, ok := any(t.SomeEnum).(mapper); ok {
-				return m.ToMap()
-			}
-			return t.SomeEnum
-		}()
+		args["someEnum"] = model.NestedToDAMLValue(t.SomeEnum)
 	}
 
 	// IMPORTANT: always include non-optional fields (GENMAP/MAP/LIST/[] etc), even if empty
@@ -539,6 +533,43 @@ func (t OneOfEverything) AcceptWithPackageID(contractID string, packageID string
 	}
 }
 
+// OneOfEverythingClient submits OneOfEverything commands and decodes their results
+type OneOfEverythingClient struct {
+	*bind.TemplateClient
+}
+
+// NewOneOfEverythingClient creates a OneOfEverythingClient submitting commands as the actAs parties
+func NewOneOfEverythingClient(cl *client.DamlBindingClient, actAs []string, opts ...bind.ClientOption) *OneOfEverythingClient {
+	return &OneOfEverythingClient{
+		TemplateClient: bind.NewTemplateClient(cl.CommandService, "AllKindsOf", "OneOfEverything", actAs, opts...),
+	}
+}
+
+// Create creates a OneOfEverything contract and returns its contract ID
+func (c *OneOfEverythingClient) Create(ctx context.Context, t OneOfEverything) (types.CONTRACT_ID, error) {
+	return c.TemplateClient.Create(ctx, t.CreateCommand())
+}
+
+// Archive exercises the Archive choice on the OneOfEverything contract contractID
+func (c *OneOfEverythingClient) Archive(ctx context.Context, contractID types.CONTRACT_ID) error {
+	_, err := c.TemplateClient.Exercise(ctx, OneOfEverything{}.Archive(string(contractID)), nil)
+	return err
+}
+
+// Accept exercises the Accept choice on the OneOfEverything contract contractID
+func (c *OneOfEverythingClient) Accept(ctx context.Context, contractID types.CONTRACT_ID, args Accept) error {
+	_, err := c.TemplateClient.Exercise(ctx, OneOfEverything{}.Accept(string(contractID), args), nil)
+	return err
+}
+
+// RegisterOneOfEverything registers the OneOfEverything template and its choices with registry
+// for decoding update streams into typed events
+func RegisterOneOfEverything(registry *bind.Registry) {
+	bind.RegisterTemplate[OneOfEverything](registry)
+	bind.RegisterChoice[OneOfEverything, types.UNIT](registry, "Archive")
+	bind.RegisterChoice[OneOfEverything, Accept](registry, "Accept")
+}
+
 // VPair is a variant/union type
 type VPair struct {
 	Left  *any   `json:"Left,omitempty"`
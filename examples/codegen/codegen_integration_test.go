@@ -13,7 +13,6 @@ import (
 	"github.com/smartcontractkit/go-daml/pkg/client"
 	"github.com/smartcontractkit/go-daml/pkg/errors"
 	"github.com/smartcontractkit/go-daml/pkg/model"
-	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
 	"github.com/smartcontractkit/go-daml/pkg/testutil"
 	. "github.com/smartcontractkit/go-daml/pkg/types"
 	"github.com/stretchr/testify/require"
@@ -86,7 +85,7 @@ func TestCodegenIntegration(t *testing.T) {
 
 	mappyContract := MappyContract{
 		Operator: PARTY(party),
-		Value: TEXTMAP{
+		Value: map[string]TEXT{
 			"key1": "value1",
 			"key2": "value2",
 		},
@@ -177,9 +176,8 @@ func TestCodegenIntegration(t *testing.T) {
 	for _, event := range txResp.Transaction.Events {
 		if event.Created != nil && event.Created.CreateArguments != nil {
 			foundTypedContract = true
-			var contract MappyContract
-			err = ledger.RecordToStruct(event.Created.CreateArguments, &contract)
-			require.NoError(t, err, "RecordToStruct should succeed")
+			contract, err := MappyContractFromCreatedEvent(event.Created)
+			require.NoError(t, err, "MappyContractFromCreatedEvent should succeed")
 
 			log.Info().
 				Str("operator", string(contract.Operator)).
@@ -188,8 +186,8 @@ func TestCodegenIntegration(t *testing.T) {
 
 			require.Equal(t, PARTY(party), contract.Operator, "operator should match")
 			require.NotNil(t, contract.Value, "value should not be nil")
-			require.Equal(t, "value1", contract.Value["key1"], "key1 should have correct value")
-			require.Equal(t, "value2", contract.Value["key2"], "key2 should have correct value")
+			require.Equal(t, TEXT("value1"), contract.Value["key1"], "key1 should have correct value")
+			require.Equal(t, TEXT("value2"), contract.Value["key2"], "key2 should have correct value")
 		}
 	}
 	require.True(t, foundTypedContract, "should find at least one typed created event")
@@ -417,9 +415,8 @@ func TestCodegenIntegrationAllFieldsContract(t *testing.T) {
 	for _, event := range txResp.Transaction.Events {
 		if event.Created != nil && event.Created.CreateArguments != nil {
 			foundTypedContract = true
-			var contract OneOfEverything
-			err = ledger.RecordToStruct(event.Created.CreateArguments, &contract)
-			require.NoError(t, err, "RecordToStruct should succeed")
+			contract, err := OneOfEverythingFromCreatedEvent(event.Created)
+			require.NoError(t, err, "OneOfEverythingFromCreatedEvent should succeed")
 
 			log.Info().
 				Str("operator", string(contract.Operator)).
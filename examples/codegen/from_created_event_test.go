@@ -0,0 +1,101 @@
+package codegen_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+	. "github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func createdEventFor(cmd *model.CreateCommand, templateID string) *model.CreatedEvent {
+	return &model.CreatedEvent{
+		ContractID:      "00contract",
+		TemplateID:      templateID,
+		CreateArguments: ledger.ConvertToRecord(cmd.Arguments),
+		PackageName:     PackageName,
+	}
+}
+
+func TestOneOfEverythingFromCreatedEventRoundTrip(t *testing.T) {
+	someMaybe := INT64(42)
+	left := any(MyPair{Left: INT64(10), Right: INT64(20)})
+	contract := OneOfEverything{
+		Operator:        PARTY("alice::1220"),
+		SomeBoolean:     true,
+		SomeInteger:     190,
+		SomeDecimal:     NUMERIC("0.0000000200"),
+		SomeMaybe:       &someMaybe,
+		SomeMaybeNot:    nil,
+		SomeText:        "some text",
+		SomeDate:        DATE(time.Date(2025, 4, 17, 0, 0, 0, 0, time.UTC)),
+		SomeDatetime:    TIMESTAMP(time.Date(2025, 4, 17, 12, 30, 15, 123456000, time.UTC)),
+		SomeSimpleList:  []INT64{1, 2, 3},
+		SomeSimplePair:  MyPair{Left: INT64(100), Right: INT64(1<<53 + 1)},
+		SomeNestedPair:  MyPair{Left: MyPair{Left: INT64(10), Right: INT64(20)}, Right: MyPair{Left: INT64(30), Right: INT64(40)}},
+		SomeUglyNesting: VPair{Both: &VPair{Left: &left}},
+		SomeMeasurement: NUMERIC("0.0000000300"),
+		SomeEnum:        ColorGreen,
+	}
+
+	event := createdEventFor(contract.CreateCommand(), PackageID+":AllKindsOf:OneOfEverything")
+	decoded, err := OneOfEverythingFromCreatedEvent(event)
+	require.NoError(t, err)
+
+	require.Equal(t, contract.Operator, decoded.Operator)
+	require.Equal(t, contract.SomeBoolean, decoded.SomeBoolean)
+	require.Equal(t, contract.SomeInteger, decoded.SomeInteger)
+	require.Equal(t, NUMERIC("0.00000002"), decoded.SomeDecimal)
+	require.Equal(t, NUMERIC("0.00000003"), decoded.SomeMeasurement)
+	require.NotNil(t, decoded.SomeMaybe)
+	require.Equal(t, someMaybe, *decoded.SomeMaybe)
+	require.Nil(t, decoded.SomeMaybeNot)
+	require.Equal(t, contract.SomeText, decoded.SomeText)
+	require.True(t, time.Time(contract.SomeDate).Equal(time.Time(decoded.SomeDate)))
+	require.True(t, time.Time(contract.SomeDatetime).Equal(time.Time(decoded.SomeDatetime)))
+	require.Equal(t, contract.SomeSimpleList, decoded.SomeSimpleList)
+	require.Equal(t, ColorGreen, decoded.SomeEnum)
+	require.Equal(t, UNIT{}, decoded.TheUnit)
+
+	// Type variables are not known to codegen, so polymorphic fields decode to their generic form,
+	// with integers as INT64 so that they keep their precision.
+	require.Equal(t, contract.SomeSimplePair, decoded.SomeSimplePair)
+	require.Equal(t, map[string]any{"left": INT64(30), "right": INT64(40)}, decoded.SomeNestedPair.Right)
+
+	require.Nil(t, decoded.SomeUglyNesting.Left)
+	require.Nil(t, decoded.SomeUglyNesting.Right)
+	require.NotNil(t, decoded.SomeUglyNesting.Both)
+	require.NotNil(t, decoded.SomeUglyNesting.Both.Left)
+	require.Equal(t, map[string]any{"left": INT64(10), "right": INT64(20)}, *decoded.SomeUglyNesting.Both.Left)
+}
+
+func TestMappyContractFromCreatedEventRoundTrip(t *testing.T) {
+	contract := MappyContract{
+		Operator: PARTY("alice::1220"),
+		Value: map[string]TEXT{
+			"key1": "value1",
+			"key2": "value2",
+		},
+	}
+
+	event := createdEventFor(contract.CreateCommand(), "#"+PackageName+":AllKindsOf:MappyContract")
+	decoded, err := MappyContractFromCreatedEvent(event)
+	require.NoError(t, err)
+	require.Equal(t, contract, *decoded)
+}
+
+func TestFromCreatedEventRejectsOtherTemplates(t *testing.T) {
+	contract := MappyContract{Operator: PARTY("alice::1220")}
+	event := createdEventFor(contract.CreateCommand(), PackageID+":AllKindsOf:MappyContract")
+
+	_, err := OneOfEverythingFromCreatedEvent(event)
+	require.ErrorContains(t, err, "template ID mismatch")
+
+	_, err = MappyContractFromCreatedEvent(nil)
+	require.Error(t, err)
+
+	_, err = MappyContractFromCreatedEvent(&model.CreatedEvent{TemplateID: PackageID + ":AllKindsOf:MappyContract"})
+	require.ErrorContains(t, err, "no create arguments")
+}
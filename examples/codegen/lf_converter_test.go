@@ -0,0 +1,95 @@
+package codegen_test
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/go-daml/pkg/lf"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	. "github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/smartcontractkit/go-daml/pkg/value"
+	"github.com/stretchr/testify/require"
+)
+
+func darConverter(t *testing.T, darPath string) *lf.Converter {
+	reader, err := zip.OpenReader(darPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	converter := lf.NewConverter(nil)
+	for _, file := range reader.File {
+		if !strings.HasSuffix(file.Name, ".dalf") {
+			continue
+		}
+		rc, err := file.Open()
+		require.NoError(t, err)
+		dalf, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+
+		pkg, err := lf.DecodeArchive(dalf)
+		require.NoError(t, err)
+		converter.AddPackage(pkg)
+	}
+	return converter
+}
+
+func TestConvertGeneratedCommands(t *testing.T) {
+	converter := darConverter(t, "../../test-data/all-kinds-of-1.0.0_lf.dar")
+
+	someMaybe := INT64(42)
+	pair := MyPair{Left: INT64(10), Right: INT64(20)}
+	left := any(MyPair{Left: pair, Right: pair})
+	contract := OneOfEverything{
+		Operator:        PARTY("alice::1220"),
+		SomeBoolean:     true,
+		SomeInteger:     190,
+		SomeDecimal:     NUMERIC("0.0000000200"),
+		SomeMaybe:       &someMaybe,
+		SomeText:        "some text",
+		SomeDate:        DATE(time.Date(2025, 4, 17, 0, 0, 0, 0, time.UTC)),
+		SomeDatetime:    TIMESTAMP(time.Date(2025, 4, 17, 12, 30, 15, 123456000, time.UTC)),
+		SomeSimpleList:  []INT64{1, 2, 3},
+		SomeSimplePair:  MyPair{Left: INT64(100), Right: INT64(200)},
+		SomeNestedPair:  MyPair{Left: MyPair{Left: INT64(10), Right: INT64(20)}, Right: MyPair{Left: INT64(30), Right: INT64(40)}},
+		SomeUglyNesting: VPair{Both: &VPair{Left: &left}},
+		SomeMeasurement: NUMERIC("0.0000000300"),
+		SomeEnum:        ColorGreen,
+	}
+	mappy := MappyContract{Operator: PARTY("alice::1220"), Value: map[string]TEXT{"key1": "value1"}}
+
+	create, mappyCreate := contract.CreateCommand(), mappy.CreateCommand()
+	require.NoError(t, converter.ConvertCommands(context.Background(), []*model.Command{{Command: create}, {Command: mappyCreate}}))
+	require.Equal(t, value.Some(value.Int64(42)), create.Arguments["someMaybe"])
+	require.Equal(t, value.None(), create.Arguments["someMaybeNot"])
+	require.Equal(t, "0.0000000200", create.Arguments["someDecimal"].(value.Numeric).String())
+	require.Equal(t, value.TextMap{{Key: "key1", Value: value.Text("value1")}}, mappyCreate.Arguments["value"])
+
+	decoded, err := OneOfEverythingFromCreatedEvent(createdEventFor(create, PackageID+":AllKindsOf:OneOfEverything"))
+	require.NoError(t, err)
+	require.Equal(t, contract.SomeSimpleList, decoded.SomeSimpleList)
+	require.Equal(t, ColorGreen, decoded.SomeEnum)
+	require.NotNil(t, decoded.SomeUglyNesting.Both)
+
+	// Values that do not match the package are reported by field path
+	left = any(pair)
+	err = converter.ConvertCommands(context.Background(), []*model.Command{{Command: contract.CreateCommand()}})
+	require.EqualError(t, err, "invalid command 0: invalid value: "+
+		"someUglyNesting.Both.Left.left: wrong type: expected AllKindsOf.MyPair Int, got types.INT64; "+
+		"someUglyNesting.Both.Left.right: wrong type: expected AllKindsOf.MyPair Int, got types.INT64")
+	left = any(MyPair{Left: pair, Right: pair})
+
+	decodedMappy, err := MappyContractFromCreatedEvent(createdEventFor(mappyCreate, PackageID+":AllKindsOf:MappyContract"))
+	require.NoError(t, err)
+	require.Equal(t, mappy, *decodedMappy)
+
+	// Numerics are checked against the scale of their field
+	contract.SomeDecimal = "0.00000000001"
+	err = converter.ConvertCommands(context.Background(), []*model.Command{{Command: contract.CreateCommand()}})
+	require.ErrorIs(t, err, lf.ErrNumericScale)
+	require.ErrorContains(t, err, "someDecimal: numeric out of range")
+}
@@ -0,0 +1,117 @@
+package codegen_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+	"github.com/smartcontractkit/go-daml/pkg/testutil"
+	. "github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPreparedTransactionHashIntegration checks the local V2 transaction hash against the hash
+// computed by the participant, for transactions covering every kind of DAML value.
+func TestPreparedTransactionHashIntegration(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	sandbox, err := testutil.CreateSandbox(t)
+	require.NoError(t, err)
+	cl := sandbox.BindingClient
+
+	packageID, err := packageUpload(ctx, "all-kinds-of", darFilePath, cl)
+	require.NoError(t, err)
+
+	party := ""
+	users, err := cl.UserMng.ListUsers(ctx)
+	require.NoError(t, err)
+	for _, u := range users {
+		if u.ID == testutil.SandboxUserId {
+			party = u.PrimaryParty
+		}
+	}
+	require.NotEmpty(t, party)
+
+	syncResp, err := cl.StateService.GetConnectedSynchronizers(ctx, &model.GetConnectedSynchronizersRequest{})
+	require.NoError(t, err)
+	require.NotEmpty(t, syncResp.ConnectedSynchronizers)
+	synchronizerID := syncResp.ConnectedSynchronizers[0].SynchronizerID
+
+	mappyContract := MappyContract{
+		Operator: PARTY(party),
+		Value: map[string]TEXT{
+			"key1": "value1",
+			"key2": "value2",
+		},
+	}
+	contractIDs, err := createContract(ctx, party, packageID, cl, mappyContract)
+	require.NoError(t, err)
+	require.NotEmpty(t, contractIDs)
+
+	someMaybe := INT64(42)
+	oneOfEverything := OneOfEverything{
+		Operator:        PARTY(party),
+		SomeBoolean:     true,
+		SomeInteger:     190,
+		SomeDecimal:     NUMERIC("0.0000000200"),
+		SomeMeasurement: NUMERIC("0.0000000300"),
+		SomeMaybe:       &someMaybe,
+		SomeDate:        DATE(time.Now().UTC()),
+		SomeDatetime:    TIMESTAMP(time.Now().UTC()),
+		SomeSimpleList:  []INT64{1, 2, 3},
+		SomeSimplePair:  MyPair{Left: INT64(100), Right: INT64(200)},
+		SomeNestedPair: MyPair{
+			Left:  MyPair{Left: INT64(10), Right: INT64(20)},
+			Right: MyPair{Left: INT64(30), Right: INT64(40)},
+		},
+		SomeUglyNesting: VPair{
+			Both: &VPair{
+				Left: func() *interface{} {
+					val := interface{}(MyPair{Left: INT64(10), Right: INT64(20)})
+					return &val
+				}(),
+			},
+		},
+		SomeText: "some text",
+		SomeEnum: ColorRed,
+	}
+
+	tests := []struct {
+		name    string
+		command model.CommandType
+		kind    ledger.PreparedNodeKind
+	}{
+		{"create", createCommandWithPackageID(oneOfEverything, packageID), ledger.PreparedNodeCreate},
+		{"create map", createCommandWithPackageID(mappyContract, packageID), ledger.PreparedNodeCreate},
+		{"exercise", correctExerciseCommandPackageID(mappyContract.Archive(contractIDs[0]), packageID), ledger.PreparedNodeExercise},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prepared, err := cl.InteractiveSubmissionService.PrepareSubmission(ctx, &model.PrepareSubmissionRequest{
+				UserID:         testutil.SandboxUserId,
+				CommandID:      "prepare-" + time.Now().Format("20060102150405.000000"),
+				Commands:       []*model.Command{{Command: tt.command}},
+				ActAs:          []string{party},
+				SynchronizerID: synchronizerID,
+				VerboseHashing: true,
+			})
+			require.NoError(t, err)
+			require.Equal(t, model.HashingSchemeVersionV2, prepared.HashingSchemeVersion)
+
+			// The hash of the participant is the known answer for the local encoding
+			hash, err := ledger.HashPreparedTransaction(prepared.PreparedTransaction, prepared.HashingSchemeVersion)
+			require.NoError(t, err)
+			require.Equal(t, prepared.PreparedTransactionHash, hash,
+				"hashing details of the participant:\n%s", prepared.HashingDetails)
+
+			summary, err := ledger.VerifyPreparedTransaction(prepared)
+			require.NoError(t, err)
+			require.Equal(t, []string{party}, summary.ActAs)
+			require.NotEmpty(t, summary.Nodes)
+			require.Equal(t, tt.kind, summary.Nodes[0].Kind)
+		})
+	}
+}
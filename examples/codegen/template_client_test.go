@@ -0,0 +1,49 @@
+package codegen_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smartcontractkit/go-daml/pkg/bind"
+	"github.com/smartcontractkit/go-daml/pkg/client"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	. "github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingCommandService struct {
+	commands []*model.Commands
+	events   []*model.Event
+}
+
+func (r *recordingCommandService) SubmitAndWait(_ context.Context, req *model.SubmitAndWaitRequest) (*model.SubmitAndWaitResponse, error) {
+	r.commands = append(r.commands, req.Commands)
+	return &model.SubmitAndWaitResponse{}, nil
+}
+
+func (r *recordingCommandService) SubmitAndWaitForTransaction(_ context.Context, req *model.SubmitAndWaitRequest) (*model.SubmitAndWaitForTransactionResponse, error) {
+	r.commands = append(r.commands, req.Commands)
+	return &model.SubmitAndWaitForTransactionResponse{Transaction: &model.Transaction{Events: r.events}}, nil
+}
+
+func TestMappyContractClient(t *testing.T) {
+	svc := &recordingCommandService{events: []*model.Event{
+		{Created: &model.CreatedEvent{ContractID: "00mappy", TemplateID: PackageID + ":AllKindsOf:MappyContract"}},
+	}}
+	cl := NewMappyContractClient(&client.DamlBindingClient{CommandService: svc}, []string{"alice::1220"}, bind.WithUserID("app"))
+
+	contract := MappyContract{Operator: PARTY("alice::1220"), Value: map[string]TEXT{"key": "value"}}
+	cid, err := cl.Create(context.Background(), contract)
+	require.NoError(t, err)
+	require.Equal(t, CONTRACT_ID("00mappy"), cid)
+
+	require.NoError(t, cl.Archive(context.Background(), cid))
+
+	require.Len(t, svc.commands, 2)
+	create := svc.commands[0].Commands[0].Command.(*model.CreateCommand)
+	require.Equal(t, contract.CreateCommand(), create)
+	archive := svc.commands[1].Commands[0].Command.(*model.ExerciseCommand)
+	require.Equal(t, "00mappy", archive.ContractID)
+	require.Equal(t, "Archive", archive.Choice)
+	require.Equal(t, "app", svc.commands[1].UserID)
+}
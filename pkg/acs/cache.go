@@ -0,0 +1,332 @@
+// Package acs maintains an in-memory active contract set (ACS). A Cache bootstraps from the
+// ACS snapshot at the ledger end and then applies the created and archived events of the
+// update stream, so that the current contracts can be queried without round trips to the ledger.
+package acs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/smartcontractkit/go-daml/pkg/bind"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+)
+
+type ChangeKind int
+
+const (
+	ChangeCreated ChangeKind = iota + 1
+	ChangeArchived
+)
+
+// Change describes a contract that was added to or removed from the cache.
+type Change struct {
+	Kind       ChangeKind
+	Offset     int64
+	ContractID string
+	TemplateID string
+	// Created is the created event of the contract, for both created and archived contracts
+	Created *model.CreatedEvent
+}
+
+type subscriber struct {
+	ctx context.Context
+	ch  chan Change
+}
+
+type Cache struct {
+	stateService  ledger.StateService
+	updateService ledger.UpdateService
+	eventFormat   *model.EventFormat
+	opts          []ledger.UpdateSubscriberOption
+
+	mu         sync.RWMutex
+	contracts  map[string]*model.CreatedEvent
+	byTemplate map[templateKey]map[string]*model.CreatedEvent
+	// Package names of the package IDs of the contracts, to look up templates by package ID
+	packageNames map[string]string
+	offset       int64
+
+	subsMu      sync.Mutex
+	subscribers []*subscriber
+
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// New returns a cache of the contracts visible through eventFormat. opts configure how the update
+// stream is resumed after failures, the offset checkpoint store is managed by the cache itself.
+func New(stateService ledger.StateService, updateService ledger.UpdateService, eventFormat *model.EventFormat, opts ...ledger.UpdateSubscriberOption) *Cache {
+	return &Cache{
+		stateService:  stateService,
+		updateService: updateService,
+		eventFormat:   eventFormat,
+		opts:          opts,
+		contracts:     make(map[string]*model.CreatedEvent),
+		byTemplate:    make(map[templateKey]map[string]*model.CreatedEvent),
+		packageNames:  make(map[string]string),
+		ready:         make(chan struct{}),
+	}
+}
+
+// Run loads the active contracts at the ledger end and then keeps the cache up to date with the
+// update stream. It blocks until ctx is cancelled, returning nil, or until the stream fails permanently.
+func (c *Cache) Run(ctx context.Context) error {
+	ledgerEnd, err := c.stateService.GetLedgerEnd(ctx, &model.GetLedgerEndRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get ledger end: %w", err)
+	}
+
+	if err := c.bootstrap(ctx, ledgerEnd.Offset); err != nil {
+		return err
+	}
+	c.readyOnce.Do(func() { close(c.ready) })
+
+	opts := append(append([]ledger.UpdateSubscriberOption{}, c.opts...), ledger.WithCheckpointStore(ledger.NewMemoryCheckpointStore(ledgerEnd.Offset)))
+	updates, errs := ledger.NewUpdateSubscriber(c.updateService, c.stateService, c.eventFormat, opts...).Subscribe(ctx)
+	for resp := range updates {
+		if resp.Update != nil && resp.Update.Transaction != nil {
+			c.notify(ctx, c.applyTransaction(resp.Update.Transaction))
+		}
+	}
+
+	if err := <-errs; err != nil {
+		return fmt.Errorf("update stream failed at offset %d: %w", c.Offset(), err)
+	}
+	return nil
+}
+
+// WaitReady blocks until the initial snapshot of active contracts has been loaded.
+func (c *Cache) WaitReady(ctx context.Context) error {
+	select {
+	case <-c.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Cache) bootstrap(ctx context.Context, offset int64) error {
+	responses, errs := c.stateService.GetActiveContracts(ctx, &model.GetActiveContractsRequest{
+		ActiveAtOffset: offset,
+		EventFormat:    c.eventFormat,
+	})
+
+	contracts := make([]*model.CreatedEvent, 0)
+	for resp := range responses {
+		switch entry := resp.ContractEntry.(type) {
+		case *model.ActiveContractEntry:
+			if entry.ActiveContract != nil && entry.ActiveContract.CreatedEvent != nil {
+				contracts = append(contracts, entry.ActiveContract.CreatedEvent)
+			}
+		case *model.IncompleteAssignedEntry:
+			// Assigned to a synchronizer, but the reassignment has not completed yet; the contract is active
+			if entry.IncompleteAssigned != nil && entry.IncompleteAssigned.AssignedEvent != nil && entry.IncompleteAssigned.AssignedEvent.CreatedEvent != nil {
+				contracts = append(contracts, entry.IncompleteAssigned.AssignedEvent.CreatedEvent)
+			}
+		}
+	}
+	if err := <-errs; err != nil {
+		return fmt.Errorf("failed to read active contracts at offset %d: %w", offset, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	for _, created := range contracts {
+		c.add(created)
+	}
+	c.offset = offset
+	c.mu.Unlock()
+
+	log.Debug().Msgf("loaded %d active contracts at offset %d", len(contracts), offset)
+	return nil
+}
+
+func (c *Cache) applyTransaction(tx *model.Transaction) []Change {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	changes := make([]Change, 0, len(tx.Events))
+	for _, event := range tx.Events {
+		switch {
+		case event.Created != nil:
+			c.add(event.Created)
+			changes = append(changes, Change{
+				Kind:       ChangeCreated,
+				Offset:     tx.Offset,
+				ContractID: event.Created.ContractID,
+				TemplateID: event.Created.TemplateID,
+				Created:    event.Created,
+			})
+		case event.Archived != nil:
+			created, ok := c.contracts[event.Archived.ContractID]
+			if !ok {
+				continue
+			}
+			c.remove(created)
+			changes = append(changes, Change{
+				Kind:       ChangeArchived,
+				Offset:     tx.Offset,
+				ContractID: event.Archived.ContractID,
+				TemplateID: event.Archived.TemplateID,
+				Created:    created,
+			})
+		}
+	}
+	c.offset = tx.Offset
+
+	return changes
+}
+
+func (c *Cache) add(created *model.CreatedEvent) {
+	c.contracts[created.ContractID] = created
+
+	key := c.createdKey(created)
+	if c.byTemplate[key] == nil {
+		c.byTemplate[key] = make(map[string]*model.CreatedEvent)
+	}
+	c.byTemplate[key][created.ContractID] = created
+}
+
+func (c *Cache) remove(created *model.CreatedEvent) {
+	delete(c.contracts, created.ContractID)
+
+	key := c.createdKey(created)
+	delete(c.byTemplate[key], created.ContractID)
+	if len(c.byTemplate[key]) == 0 {
+		delete(c.byTemplate, key)
+	}
+}
+
+// Offset returns the offset the cache reflects the active contracts at.
+func (c *Cache) Offset() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offset
+}
+
+// Get returns the created event of an active contract.
+func (c *Cache) Get(contractID string) (*model.CreatedEvent, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	created, ok := c.contracts[contractID]
+	return created, ok
+}
+
+// ByTemplate returns the active contracts of a template. templateID may be qualified by a
+// #package-name reference or a package ID, which match the contracts of all package versions
+// of that package name, or not at all, which matches the template of every package.
+func (c *Cache) ByTemplate(templateID string) []*model.CreatedEvent {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	packageRef, moduleName, entityName, ok := bind.SplitTemplateID(templateID)
+	if !ok {
+		return []*model.CreatedEvent{}
+	}
+
+	var keys []templateKey
+	if packageRef == "" {
+		for key := range c.byTemplate {
+			if key.moduleName == moduleName && key.entityName == entityName {
+				keys = append(keys, key)
+			}
+		}
+	} else {
+		keys = append(keys, templateKey{c.packageName(packageRef), moduleName, entityName})
+	}
+
+	result := make([]*model.CreatedEvent, 0)
+	for _, key := range keys {
+		for _, created := range c.byTemplate[key] {
+			result = append(result, created)
+		}
+	}
+	return result
+}
+
+// Len returns the number of active contracts.
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.contracts)
+}
+
+// Subscribe returns a channel of the changes applied to the cache after the call, which is closed
+// when ctx is cancelled. Changes are delivered in order, and the cache waits for slow subscribers.
+func (c *Cache) Subscribe(ctx context.Context) <-chan Change {
+	sub := &subscriber{ctx: ctx, ch: make(chan Change, 64)}
+
+	c.subsMu.Lock()
+	c.subscribers = append(c.subscribers, sub)
+	c.subsMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		c.subsMu.Lock()
+		defer c.subsMu.Unlock()
+		for i, s := range c.subscribers {
+			if s == sub {
+				c.subscribers = append(c.subscribers[:i], c.subscribers[i+1:]...)
+				break
+			}
+		}
+		close(sub.ch)
+	}()
+
+	return sub.ch
+}
+
+func (c *Cache) notify(ctx context.Context, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	for _, sub := range c.subscribers {
+		for _, change := range changes {
+			select {
+			case sub.ch <- change:
+			case <-sub.ctx.Done():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// templateKey identifies a template across the versions of its package, so that same-named
+// templates of different packages are kept apart.
+type templateKey struct {
+	packageName string
+	moduleName  string
+	entityName  string
+}
+
+// createdKey returns the template key of a contract and records the package name of its package ID.
+func (c *Cache) createdKey(created *model.CreatedEvent) templateKey {
+	packageID, moduleName, entityName, _ := bind.SplitTemplateID(created.TemplateID)
+	if created.PackageName != "" {
+		c.packageNames[packageID] = created.PackageName
+	}
+	return templateKey{c.packageName(packageID), moduleName, entityName}
+}
+
+// packageName returns the package name of a package ID or #package-name reference. Package IDs
+// of packages without contracts in the cache are returned as they are.
+func (c *Cache) packageName(packageRef string) string {
+	if name, ok := strings.CutPrefix(packageRef, "#"); ok {
+		return name
+	}
+	if name, ok := c.packageNames[packageRef]; ok {
+		return name
+	}
+	return packageRef
+}
@@ -0,0 +1,210 @@
+package acs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+)
+
+type assetTest struct {
+	Owner types.PARTY `json:"owner"`
+	Name  types.TEXT  `json:"name"`
+}
+
+func (assetTest) GetTemplateID() string {
+	return "#asset-test:Main.Assets:Asset"
+}
+
+type fakeStateService struct {
+	ledger.StateService
+	ledgerEnd int64
+	active    []*model.GetActiveContractsResponse
+}
+
+func (f *fakeStateService) GetLedgerEnd(context.Context, *model.GetLedgerEndRequest) (*model.GetLedgerEndResponse, error) {
+	return &model.GetLedgerEndResponse{Offset: f.ledgerEnd}, nil
+}
+
+func (f *fakeStateService) GetActiveContracts(_ context.Context, _ *model.GetActiveContractsRequest) (<-chan *model.GetActiveContractsResponse, <-chan error) {
+	responseCh := make(chan *model.GetActiveContractsResponse, len(f.active))
+	errCh := make(chan error, 1)
+	for _, resp := range f.active {
+		responseCh <- resp
+	}
+	close(responseCh)
+	close(errCh)
+	return responseCh, errCh
+}
+
+func (f *fakeStateService) GetLatestPrunedOffsets(context.Context, *model.GetLatestPrunedOffsetsRequest) (*model.GetLatestPrunedOffsetsResponse, error) {
+	return &model.GetLatestPrunedOffsetsResponse{}, nil
+}
+
+type fakeUpdateService struct {
+	ledger.UpdateService
+	begin   chan int64
+	updates chan *model.Update
+}
+
+func (f *fakeUpdateService) GetUpdates(ctx context.Context, req *model.GetUpdatesRequest) (<-chan *model.GetUpdatesResponse, <-chan error) {
+	f.begin <- req.BeginExclusive
+
+	responseCh := make(chan *model.GetUpdatesResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(responseCh)
+		defer close(errCh)
+		for {
+			select {
+			case update := <-f.updates:
+				select {
+				case responseCh <- &model.GetUpdatesResponse{Update: update}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return responseCh, errCh
+}
+
+func asset(contractID, templateID, owner string) *model.CreatedEvent {
+	return &model.CreatedEvent{
+		ContractID:  contractID,
+		TemplateID:  templateID,
+		PackageName: "asset-test",
+		CreateArguments: &v2.Record{Fields: []*v2.RecordField{
+			{Label: "owner", Value: &v2.Value{Sum: &v2.Value_Party{Party: owner}}},
+			{Label: "name", Value: &v2.Value{Sum: &v2.Value_Text{Text: contractID}}},
+		}},
+	}
+}
+
+func TestCache(t *testing.T) {
+	state := &fakeStateService{
+		ledgerEnd: 10,
+		active: []*model.GetActiveContractsResponse{
+			{ContractEntry: &model.ActiveContractEntry{ActiveContract: &model.ActiveContract{
+				CreatedEvent: asset("00a", "6d7e83e8:Main.Assets:Asset", "alice"),
+			}}},
+			{ContractEntry: &model.IncompleteAssignedEntry{IncompleteAssigned: &model.IncompleteAssigned{
+				AssignedEvent: &model.AssignedEvent{CreatedEvent: asset("00b", "9f1c0a2b:Main.Assets:Asset", "bob")},
+			}}},
+			{ContractEntry: &model.ActiveContractEntry{ActiveContract: &model.ActiveContract{
+				CreatedEvent: &model.CreatedEvent{ContractID: "00c", TemplateID: "6d7e83e8:Main.Other:Other", PackageName: "asset-test"},
+			}}},
+		},
+	}
+	updates := &fakeUpdateService{begin: make(chan int64, 1), updates: make(chan *model.Update)}
+	cache := New(state, updates, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- cache.Run(ctx) }()
+
+	readyCtx, readyCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer readyCancel()
+	require.NoError(t, cache.WaitReady(readyCtx))
+	require.Equal(t, int64(10), <-updates.begin)
+
+	require.Equal(t, 3, cache.Len())
+	require.Equal(t, int64(10), cache.Offset())
+	for _, templateID := range []string{"Main.Assets:Asset", "#asset-test:Main.Assets:Asset", "6d7e83e8:Main.Assets:Asset"} {
+		require.Len(t, cache.ByTemplate(templateID), 2, templateID)
+	}
+	require.Empty(t, cache.ByTemplate("Main.Assets:Other"))
+
+	changes := cache.Subscribe(ctx)
+	updates.updates <- &model.Update{Transaction: &model.Transaction{
+		Offset: 11,
+		Events: []*model.Event{
+			{Created: asset("00d", "6d7e83e8:Main.Assets:Asset", "carol")},
+			{Archived: &model.ArchivedEvent{ContractID: "00a", TemplateID: "6d7e83e8:Main.Assets:Asset"}},
+			{Archived: &model.ArchivedEvent{ContractID: "00unknown", TemplateID: "6d7e83e8:Main.Assets:Asset"}},
+		},
+	}}
+
+	created := <-changes
+	require.Equal(t, ChangeCreated, created.Kind)
+	require.Equal(t, "00d", created.ContractID)
+	require.Equal(t, int64(11), created.Offset)
+
+	archived := <-changes
+	require.Equal(t, ChangeArchived, archived.Kind)
+	require.Equal(t, "00a", archived.ContractID)
+	require.Equal(t, "alice", archived.Created.CreateArguments.(*v2.Record).Fields[0].Value.GetParty())
+
+	require.Equal(t, int64(11), cache.Offset())
+	_, ok := cache.Get("00a")
+	require.False(t, ok)
+
+	contracts, err := List[assetTest](cache)
+	require.NoError(t, err)
+	owners := make([]types.PARTY, 0, len(contracts))
+	for _, contract := range contracts {
+		owners = append(owners, contract.Payload.Owner)
+	}
+	require.ElementsMatch(t, []types.PARTY{"bob", "carol"}, owners)
+
+	contract, err := Get[assetTest](cache, "00d")
+	require.NoError(t, err)
+	require.Equal(t, assetTest{Owner: "carol", Name: "00d"}, contract.Payload)
+	require.Equal(t, types.CONTRACT_ID("00d"), contract.ContractID)
+
+	contract, err = Get[assetTest](cache, "00a")
+	require.NoError(t, err)
+	require.Nil(t, contract)
+
+	_, err = Get[assetTest](cache, "00c")
+	require.ErrorContains(t, err, "template ID mismatch")
+
+	cancel()
+	require.NoError(t, <-done)
+	_, ok = <-changes
+	require.False(t, ok)
+}
+
+func TestCachePackages(t *testing.T) {
+	other := asset("00b", "7a3e5d1f:Main.Assets:Asset", "bob")
+	other.PackageName = "other-assets"
+	state := &fakeStateService{
+		ledgerEnd: 10,
+		active: []*model.GetActiveContractsResponse{
+			{ContractEntry: &model.ActiveContractEntry{ActiveContract: &model.ActiveContract{CreatedEvent: asset("00a", "6d7e83e8:Main.Assets:Asset", "alice")}}},
+			{ContractEntry: &model.ActiveContractEntry{ActiveContract: &model.ActiveContract{CreatedEvent: other}}},
+		},
+	}
+	cache := New(state, nil, nil)
+	require.NoError(t, cache.bootstrap(context.Background(), 10))
+
+	// Same-named templates of other packages are kept apart
+	require.Len(t, cache.ByTemplate("Main.Assets:Asset"), 2)
+	for _, templateID := range []string{"#asset-test:Main.Assets:Asset", "6d7e83e8:Main.Assets:Asset"} {
+		contracts := cache.ByTemplate(templateID)
+		require.Len(t, contracts, 1, templateID)
+		require.Equal(t, "00a", contracts[0].ContractID)
+	}
+	for _, templateID := range []string{"#other-assets:Main.Assets:Asset", "7a3e5d1f:Main.Assets:Asset"} {
+		contracts := cache.ByTemplate(templateID)
+		require.Len(t, contracts, 1, templateID)
+		require.Equal(t, "00b", contracts[0].ContractID)
+	}
+
+	contracts, err := List[assetTest](cache)
+	require.NoError(t, err)
+	require.Len(t, contracts, 1)
+	require.Equal(t, types.PARTY("alice"), contracts[0].Payload.Owner)
+
+	_, err = Get[assetTest](cache, "00b")
+	require.EqualError(t, err, "package mismatch: expected asset-test, got other-assets for 00b")
+}
@@ -0,0 +1,69 @@
+package acs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/smartcontractkit/go-daml/pkg/bind"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+)
+
+// Template is implemented by the template types generated by godaml.
+type Template = bind.Template
+
+// Contract is an active contract decoded into its generated template type.
+type Contract[T Template] struct {
+	ContractID types.CONTRACT_ID
+	Payload    T
+	Created    *model.CreatedEvent
+}
+
+// Get returns the active contract with contractID decoded as T. It returns nil if the
+// contract is not active, and an error if it is not a contract of template T.
+func Get[T Template](c *Cache, contractID string) (*Contract[T], error) {
+	created, ok := c.Get(contractID)
+	if !ok {
+		return nil, nil
+	}
+	return decode[T](created)
+}
+
+// List returns the active contracts of template T.
+func List[T Template](c *Cache) ([]*Contract[T], error) {
+	var zero T
+	createdEvents := c.ByTemplate(zero.GetTemplateID())
+
+	contracts := make([]*Contract[T], 0, len(createdEvents))
+	for _, created := range createdEvents {
+		contract, err := decode[T](created)
+		if err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, contract)
+	}
+	return contracts, nil
+}
+
+func decode[T Template](created *model.CreatedEvent) (*Contract[T], error) {
+	var payload T
+	templateID := payload.GetTemplateID()
+	packageRef, moduleName, entityName, ok := bind.SplitTemplateID(templateID)
+	if !ok {
+		return nil, fmt.Errorf("invalid template ID %q of %T", templateID, payload)
+	}
+	// Templates of other packages may have the same module and entity name
+	if packageName, byName := strings.CutPrefix(packageRef, "#"); byName && created.PackageName != "" && created.PackageName != packageName {
+		return nil, fmt.Errorf("package mismatch: expected %s, got %s for %s", packageName, created.PackageName, created.ContractID)
+	}
+
+	if err := bind.DecodeCreatedEvent(created, moduleName, entityName, &payload); err != nil {
+		return nil, err
+	}
+
+	return &Contract[T]{
+		ContractID: types.CONTRACT_ID(created.ContractID),
+		Payload:    payload,
+		Created:    created,
+	}, nil
+}
@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+
+	// LedgerAPIScope is the scope Canton requires in scope-based tokens by default.
+	LedgerAPIScope = "daml_ledger_api"
+
+	defaultTokenTTL = time.Hour
+)
+
+// ParticipantAudience returns the audience of audience-based tokens for a participant.
+func ParticipantAudience(participantID string) string {
+	return "https://daml.com/jwt/aud/participant/" + participantID
+}
+
+// JWTSigner signs Daml ledger API tokens, e.g. for participants running with the unsafe HMAC
+// or RSA/ECDSA certificate based auth services in local and CI environments.
+type JWTSigner struct {
+	algorithm string
+	keyID     string
+	sign      func(signingInput []byte) ([]byte, error)
+}
+
+// NewHS256Signer returns a signer for participants configured with unsafe-jwt-hmac-256 and secret.
+func NewHS256Signer(secret []byte) *JWTSigner {
+	return &JWTSigner{
+		algorithm: HS256,
+		sign: func(signingInput []byte) ([]byte, error) {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(signingInput)
+			return mac.Sum(nil), nil
+		},
+	}
+}
+
+// NewRS256Signer returns a signer for participants configured with rs-256-crt or rs-256-jwks.
+func NewRS256Signer(key *rsa.PrivateKey) *JWTSigner {
+	return &JWTSigner{
+		algorithm: RS256,
+		sign: func(signingInput []byte) ([]byte, error) {
+			digest := sha256.Sum256(signingInput)
+			return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		},
+	}
+}
+
+// NewES256Signer returns a signer for participants configured with es-256-crt. key must be a P-256 key.
+func NewES256Signer(key *ecdsa.PrivateKey) (*JWTSigner, error) {
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("ES256 requires a P-256 key, got %s", key.Curve.Params().Name)
+	}
+
+	return &JWTSigner{
+		algorithm: ES256,
+		sign: func(signingInput []byte) ([]byte, error) {
+			digest := sha256.Sum256(signingInput)
+			r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+			if err != nil {
+				return nil, err
+			}
+
+			// JWS encodes ECDSA signatures as the fixed size concatenation of r and s
+			signature := make([]byte, 64)
+			r.FillBytes(signature[:32])
+			s.FillBytes(signature[32:])
+			return signature, nil
+		},
+	}, nil
+}
+
+// WithKeyID sets the kid header, which participants using a JWKS endpoint select the key by.
+func (s *JWTSigner) WithKeyID(keyID string) *JWTSigner {
+	s.keyID = keyID
+	return s
+}
+
+// Sign returns a signed JWT with claims, which must marshal to a JSON object.
+func (s *JWTSigner) Sign(claims any) (string, error) {
+	header := map[string]string{"alg": s.algorithm, "typ": "JWT"}
+	if s.keyID != "" {
+		header["kid"] = s.keyID
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	signature, err := s.sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// CantonToken describes a Daml ledger API token for a user. Audience-based tokens set Audience,
+// usually to ParticipantAudience; scope-based tokens set Scope, usually to LedgerAPIScope, and
+// optionally an Audience.
+type CantonToken struct {
+	UserID   string
+	Audience string
+	Scope    string
+	Issuer   string
+	// TTL is the lifetime of minted tokens, one hour by default
+	TTL time.Duration
+}
+
+type cantonClaims struct {
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Mint returns a token for token that expires after its TTL.
+func (s *JWTSigner) Mint(token CantonToken) (string, error) {
+	signed, _, err := s.mint(token, time.Now())
+	return signed, err
+}
+
+func (s *JWTSigner) mint(token CantonToken, now time.Time) (string, time.Time, error) {
+	if token.UserID == "" {
+		return "", time.Time{}, fmt.Errorf("token user ID is required")
+	}
+	if token.Audience == "" && token.Scope == "" {
+		return "", time.Time{}, fmt.Errorf("token requires an audience or a scope")
+	}
+
+	ttl := token.TTL
+	if ttl == 0 {
+		ttl = defaultTokenTTL
+	}
+	expiry := now.Add(ttl)
+
+	signed, err := s.Sign(cantonClaims{
+		Subject:   token.UserID,
+		Audience:  token.Audience,
+		Scope:     token.Scope,
+		Issuer:    token.Issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiry.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiry, nil
+}
+
+// TokenProvider returns a TokenProvider that mints tokens for token, minting a new one
+// when less than a tenth of the TTL of the current one remains.
+func (s *JWTSigner) TokenProvider(token CantonToken) TokenProvider {
+	var (
+		mu        sync.Mutex
+		current   string
+		refreshAt time.Time
+	)
+
+	return func() (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		if current != "" && now.Before(refreshAt) {
+			return current, nil
+		}
+
+		signed, expiry, err := s.mint(token, now)
+		if err != nil {
+			return "", err
+		}
+		current = signed
+		refreshAt = expiry.Add(-expiry.Sub(now) / 10)
+		return current, nil
+	}
+}
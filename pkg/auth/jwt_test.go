@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeJWT(t *testing.T, token string) (map[string]string, map[string]any, []byte, []byte) {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	var header map[string]string
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(headerJSON, &header))
+
+	var claims map[string]any
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+
+	return header, claims, []byte(parts[0] + "." + parts[1]), signature
+}
+
+func TestJWTSignerAlgorithms(t *testing.T) {
+	secret := []byte("unsafe-secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	es256, err := NewES256Signer(ecKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		signer *JWTSigner
+		alg    string
+		verify func(signingInput, signature []byte) bool
+	}{
+		{NewHS256Signer(secret), HS256, func(signingInput, signature []byte) bool {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(signingInput)
+			return hmac.Equal(mac.Sum(nil), signature)
+		}},
+		{NewRS256Signer(rsaKey), RS256, func(signingInput, signature []byte) bool {
+			digest := sha256.Sum256(signingInput)
+			return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest[:], signature) == nil
+		}},
+		{es256, ES256, func(signingInput, signature []byte) bool {
+			digest := sha256.Sum256(signingInput)
+			r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+			return len(signature) == 64 && ecdsa.Verify(&ecKey.PublicKey, digest[:], r, s)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			token, err := tt.signer.WithKeyID("key-1").Mint(CantonToken{
+				UserID:   "app-provider",
+				Audience: ParticipantAudience("participant1"),
+				TTL:      10 * time.Minute,
+			})
+			require.NoError(t, err)
+
+			header, claims, signingInput, signature := decodeJWT(t, token)
+			require.Equal(t, map[string]string{"alg": tt.alg, "typ": "JWT", "kid": "key-1"}, header)
+			require.True(t, tt.verify(signingInput, signature))
+
+			require.Equal(t, "app-provider", claims["sub"])
+			require.Equal(t, "https://daml.com/jwt/aud/participant/participant1", claims["aud"])
+			require.NotContains(t, claims, "scope")
+			require.InDelta(t, float64(time.Now().Add(10*time.Minute).Unix()), claims["exp"], 5)
+		})
+	}
+}
+
+func TestJWTSignerScopeBasedToken(t *testing.T) {
+	token, err := NewHS256Signer([]byte("secret")).Mint(CantonToken{
+		UserID: "participant_admin",
+		Scope:  LedgerAPIScope,
+		Issuer: "ci",
+	})
+	require.NoError(t, err)
+
+	_, claims, _, _ := decodeJWT(t, token)
+	require.Equal(t, "participant_admin", claims["sub"])
+	require.Equal(t, "daml_ledger_api", claims["scope"])
+	require.Equal(t, "ci", claims["iss"])
+	require.NotContains(t, claims, "aud")
+
+	expiry, ok := jwtExpiry(token)
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiry, 5*time.Second)
+}
+
+func TestJWTSignerInvalid(t *testing.T) {
+	signer := NewHS256Signer([]byte("secret"))
+
+	_, err := signer.Mint(CantonToken{Scope: LedgerAPIScope})
+	require.ErrorContains(t, err, "user ID is required")
+
+	_, err = signer.Mint(CantonToken{UserID: "alice"})
+	require.ErrorContains(t, err, "audience or a scope")
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, err = NewES256Signer(p384)
+	require.ErrorContains(t, err, "requires a P-256 key")
+}
+
+func TestJWTSignerTokenProvider(t *testing.T) {
+	provider := NewHS256Signer([]byte("secret")).TokenProvider(CantonToken{UserID: "alice", Scope: LedgerAPIScope})
+
+	first, err := provider()
+	require.NoError(t, err)
+	second, err := provider()
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	_, err = NewHS256Signer([]byte("secret")).TokenProvider(CantonToken{UserID: "alice"})()
+	require.Error(t, err)
+}
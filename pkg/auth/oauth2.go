@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultExpiryDelta   = time.Minute
+	defaultTokenLifetime = 5 * time.Minute
+	defaultHTTPTimeout   = 30 * time.Second
+)
+
+// OAuth2Config configures an OAuth2 client credentials grant, e.g. against the IdP of a Canton participant.
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	// Audience and Scope are sent with the token request if set
+	Audience string
+	Scope    string
+	// ExpiryDelta is how long before expiry a token is refreshed, one minute by default
+	ExpiryDelta time.Duration
+	// DefaultLifetime is how long a token lives if neither the token response nor the token state its
+	// expiry, e.g. for opaque tokens without expires_in, five minutes by default
+	DefaultLifetime time.Duration
+	// HTTPClient is used for token requests, a client with a 30 second timeout by default
+	HTTPClient *http.Client
+}
+
+type oauth2TokenSource struct {
+	config OAuth2Config
+	now    func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OAuth2ClientCredentials returns a TokenProvider that fetches access tokens with the client
+// credentials grant. Tokens are cached until shortly before they expire, and concurrent calls
+// share a single refresh, so the provider can be passed to client.WithTokenProvider directly.
+func OAuth2ClientCredentials(config OAuth2Config) TokenProvider {
+	if config.ExpiryDelta == 0 {
+		config.ExpiryDelta = defaultExpiryDelta
+	}
+	if config.DefaultLifetime == 0 {
+		config.DefaultLifetime = defaultTokenLifetime
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	source := &oauth2TokenSource{
+		config: config,
+		now:    time.Now,
+	}
+	return source.Token
+}
+
+func (s *oauth2TokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.now().Before(s.refreshAt) {
+		return s.token, nil
+	}
+
+	token, expiry, err := s.fetch()
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.refreshAt = s.refreshTime(expiry)
+	return s.token, nil
+}
+
+func (s *oauth2TokenSource) fetch() (string, time.Time, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.config.ClientID},
+		"client_secret": {s.config.ClientSecret},
+	}
+	if s.config.Audience != "" {
+		form.Set("audience", s.config.Audience)
+	}
+	if s.config.Scope != "" {
+		form.Set("scope", s.config.Scope)
+	}
+
+	issuedAt := s.now()
+	resp, err := s.config.HTTPClient.PostForm(s.config.TokenURL, form)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to request OAuth2 token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read OAuth2 token response: %w", err)
+	}
+
+	var tokenResp oauth2TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil && resp.StatusCode == http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("failed to parse OAuth2 token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || tokenResp.Error != "" {
+		if tokenResp.Error != "" {
+			return "", time.Time{}, fmt.Errorf("OAuth2 token request failed with status %d: %s: %s", resp.StatusCode, tokenResp.Error, tokenResp.ErrorDescription)
+		}
+		return "", time.Time{}, fmt.Errorf("OAuth2 token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if tokenResp.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("OAuth2 token response has no access token")
+	}
+
+	expiry := issuedAt.Add(s.config.DefaultLifetime)
+	if tokenResp.ExpiresIn > 0 {
+		expiry = issuedAt.Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	} else if exp, ok := jwtExpiry(tokenResp.AccessToken); ok {
+		expiry = exp
+	}
+
+	return tokenResp.AccessToken, expiry, nil
+}
+
+// refreshTime returns when a token expiring at expiry has to be refreshed. Tokens that live
+// shorter than ExpiryDelta are refreshed half way through their lifetime.
+func (s *oauth2TokenSource) refreshTime(expiry time.Time) time.Time {
+	now := s.now()
+	lifetime := expiry.Sub(now)
+	if lifetime <= s.config.ExpiryDelta {
+		return now.Add(lifetime / 2)
+	}
+	return expiry.Add(-s.config.ExpiryDelta)
+}
+
+// jwtExpiry returns the exp claim of a JWT without verifying it.
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.Exp, 0), true
+}
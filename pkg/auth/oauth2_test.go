@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type tokenServer struct {
+	requests atomic.Int32
+	delay    time.Duration
+	response func(n int32) (int, any)
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := s.requests.Add(1)
+	time.Sleep(s.delay)
+
+	status, body := s.response(n)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newTokenSource(t *testing.T, server *tokenServer, now func() time.Time) *oauth2TokenSource {
+	t.Helper()
+
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	return &oauth2TokenSource{
+		config: OAuth2Config{
+			TokenURL:        srv.URL,
+			ClientID:        "ledger-client",
+			ClientSecret:    "secret",
+			ExpiryDelta:     time.Minute,
+			DefaultLifetime: 5 * time.Minute,
+			HTTPClient:      srv.Client(),
+		},
+		now: now,
+	}
+}
+
+func TestOAuth2ClientCredentialsRequest(t *testing.T) {
+	var form map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token-1", "token_type": "Bearer", "expires_in": 3600})
+	}))
+	defer srv.Close()
+
+	provider := OAuth2ClientCredentials(OAuth2Config{
+		TokenURL:     srv.URL,
+		ClientID:     "ledger-client",
+		ClientSecret: "secret",
+		Audience:     "https://daml.com/jwt/aud/participant/participant1",
+		Scope:        "daml_ledger_api",
+	})
+
+	token, err := provider()
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token)
+	require.Equal(t, map[string][]string{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {"ledger-client"},
+		"client_secret": {"secret"},
+		"audience":      {"https://daml.com/jwt/aud/participant/participant1"},
+		"scope":         {"daml_ledger_api"},
+	}, form)
+}
+
+func TestOAuth2ClientCredentialsCachesUntilExpiry(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	server := &tokenServer{response: func(n int32) (int, any) {
+		return http.StatusOK, map[string]any{"access_token": fmt.Sprintf("token-%d", n), "expires_in": 300}
+	}}
+	source := newTokenSource(t, server, func() time.Time { return now })
+
+	token, err := source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token)
+
+	// Still valid for longer than the expiry delta
+	now = now.Add(3 * time.Minute)
+	token, err = source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token)
+	require.Equal(t, int32(1), server.requests.Load())
+
+	// Within the expiry delta of the 5 minute lifetime
+	now = now.Add(90 * time.Second)
+	token, err = source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-2", token)
+	require.Equal(t, int32(2), server.requests.Load())
+}
+
+func TestOAuth2ClientCredentialsJWTExpiry(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	claims, err := json.Marshal(map[string]any{"sub": "ledger-client", "exp": now.Add(10 * time.Minute).Unix()})
+	require.NoError(t, err)
+	jwt := "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(claims) + ".c2lnbmF0dXJl"
+
+	server := &tokenServer{response: func(int32) (int, any) {
+		return http.StatusOK, map[string]any{"access_token": jwt}
+	}}
+	source := newTokenSource(t, server, func() time.Time { return now })
+
+	_, err = source.Token()
+	require.NoError(t, err)
+	require.Equal(t, now.Add(9*time.Minute), source.refreshAt)
+}
+
+func TestOAuth2ClientCredentialsDefaultLifetime(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	// An opaque token without expires_in
+	server := &tokenServer{response: func(n int32) (int, any) {
+		return http.StatusOK, map[string]any{"access_token": fmt.Sprintf("token-%d", n)}
+	}}
+	source := newTokenSource(t, server, func() time.Time { return now })
+
+	token, err := source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token)
+	require.Equal(t, now.Add(4*time.Minute), source.refreshAt)
+
+	// Renewed within the expiry delta of the default lifetime
+	now = now.Add(4 * time.Minute)
+	token, err = source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-2", token)
+	require.Equal(t, int32(2), server.requests.Load())
+}
+
+func TestOAuth2ClientCredentialsConcurrentRefresh(t *testing.T) {
+	server := &tokenServer{delay: 50 * time.Millisecond, response: func(n int32) (int, any) {
+		return http.StatusOK, map[string]any{"access_token": fmt.Sprintf("token-%d", n), "expires_in": 3600}
+	}}
+	source := newTokenSource(t, server, time.Now)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 20)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := source.Token()
+			require.NoError(t, err)
+			tokens[i] = token
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), server.requests.Load())
+	for _, token := range tokens {
+		require.Equal(t, "token-1", token)
+	}
+}
+
+func TestOAuth2ClientCredentialsError(t *testing.T) {
+	server := &tokenServer{response: func(n int32) (int, any) {
+		if n == 1 {
+			return http.StatusUnauthorized, map[string]any{"error": "invalid_client", "error_description": "Invalid client secret"}
+		}
+		return http.StatusOK, map[string]any{"access_token": "token", "expires_in": 3600}
+	}}
+	source := newTokenSource(t, server, time.Now)
+
+	_, err := source.Token()
+	require.ErrorContains(t, err, "status 401: invalid_client: Invalid client secret")
+
+	// Failures are not cached
+	token, err := source.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token", token)
+}
@@ -0,0 +1,133 @@
+package bind
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+)
+
+// TemplateClient submits commands for a single template on behalf of the actAs parties
+// and decodes contract IDs and choice results from the resulting transactions.
+// Generated <Template>Client types are thin typed wrappers around it.
+type TemplateClient struct {
+	commandService ledger.CommandService
+	moduleName     string
+	templateName   string
+	actAs          []string
+	readAs         []string
+	userID         string
+	workflowID     string
+}
+
+type ClientOption func(*TemplateClient)
+
+// WithUserID sets the user ID commands are submitted as. It can be omitted when
+// the ledger derives the user from the access token.
+func WithUserID(userID string) ClientOption {
+	return func(c *TemplateClient) {
+		c.userID = userID
+	}
+}
+
+func WithReadAs(parties ...string) ClientOption {
+	return func(c *TemplateClient) {
+		c.readAs = parties
+	}
+}
+
+func WithWorkflowID(workflowID string) ClientOption {
+	return func(c *TemplateClient) {
+		c.workflowID = workflowID
+	}
+}
+
+func NewTemplateClient(commandService ledger.CommandService, moduleName, templateName string, actAs []string, opts ...ClientOption) *TemplateClient {
+	c := &TemplateClient{
+		commandService: commandService,
+		moduleName:     moduleName,
+		templateName:   templateName,
+		actAs:          actAs,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Create submits cmd and returns the ID of the contract it created.
+func (c *TemplateClient) Create(ctx context.Context, cmd *model.CreateCommand) (types.CONTRACT_ID, error) {
+	tx, err := c.Submit(ctx, &model.Command{Command: cmd})
+	if err != nil {
+		return "", err
+	}
+
+	for _, event := range tx.Events {
+		if event.Created != nil && MatchesTemplateID(event.Created.TemplateID, c.moduleName, c.templateName) {
+			return types.CONTRACT_ID(event.Created.ContractID), nil
+		}
+	}
+
+	return "", fmt.Errorf("transaction %s did not create a %s:%s contract", tx.UpdateID, c.moduleName, c.templateName)
+}
+
+// Exercise submits cmd and decodes the result of the exercised choice into result.
+// result may be nil for choices whose result is not needed.
+func (c *TemplateClient) Exercise(ctx context.Context, cmd *model.ExerciseCommand, result any) (*model.Transaction, error) {
+	tx, err := c.Submit(ctx, &model.Command{Command: cmd})
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return tx, nil
+	}
+
+	for _, event := range tx.Events {
+		if event.Exercised != nil && event.Exercised.ContractID == cmd.ContractID && event.Exercised.Choice == cmd.Choice {
+			if err := DecodeExerciseResult(event.Exercised, c.moduleName, c.templateName, cmd.Choice, result); err != nil {
+				return nil, err
+			}
+			return tx, nil
+		}
+	}
+
+	return nil, fmt.Errorf("transaction %s has no exercise of %s on %s", tx.UpdateID, cmd.Choice, cmd.ContractID)
+}
+
+// Submit submits commands as a single transaction and waits for it. The transaction
+// is returned with ledger effects, so that exercised events and their results are included.
+func (c *TemplateClient) Submit(ctx context.Context, commands ...*model.Command) (*model.Transaction, error) {
+	filtersByParty := make(map[string]*model.Filters, len(c.actAs))
+	for _, party := range c.actAs {
+		filtersByParty[party] = &model.Filters{}
+	}
+
+	resp, err := c.commandService.SubmitAndWaitForTransaction(ctx, &model.SubmitAndWaitRequest{
+		Commands: &model.Commands{
+			WorkflowID: c.workflowID,
+			UserID:     c.userID,
+			CommandID:  uuid.NewString(),
+			Commands:   commands,
+			ActAs:      c.actAs,
+			ReadAs:     c.readAs,
+		},
+		TransactionFormat: &model.TransactionFormat{
+			EventFormat:      &model.EventFormat{FiltersByParty: filtersByParty},
+			TransactionShape: model.TransactionShapeLedgerEffects,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit commands for %s:%s: %w", c.moduleName, c.templateName, err)
+	}
+
+	if resp.Transaction == nil {
+		return nil, fmt.Errorf("update %s returned no transaction", resp.UpdateID)
+	}
+
+	return resp.Transaction, nil
+}
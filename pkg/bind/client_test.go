@@ -0,0 +1,87 @@
+package bind
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCommandService struct {
+	requests []*model.SubmitAndWaitRequest
+	events   []*model.Event
+}
+
+func (f *fakeCommandService) SubmitAndWait(_ context.Context, req *model.SubmitAndWaitRequest) (*model.SubmitAndWaitResponse, error) {
+	f.requests = append(f.requests, req)
+	return &model.SubmitAndWaitResponse{UpdateID: "update-1"}, nil
+}
+
+func (f *fakeCommandService) SubmitAndWaitForTransaction(_ context.Context, req *model.SubmitAndWaitRequest) (*model.SubmitAndWaitForTransactionResponse, error) {
+	f.requests = append(f.requests, req)
+	return &model.SubmitAndWaitForTransactionResponse{
+		UpdateID:    "update-1",
+		Transaction: &model.Transaction{UpdateID: "update-1", Events: f.events},
+	}, nil
+}
+
+func TestTemplateClientCreate(t *testing.T) {
+	svc := &fakeCommandService{events: []*model.Event{
+		{Created: &model.CreatedEvent{ContractID: "00other", TemplateID: "pkg:Main.Routes:Depot"}},
+		{Created: &model.CreatedEvent{ContractID: "00route", TemplateID: "pkg:Main.Routes:Route"}},
+	}}
+	c := NewTemplateClient(svc, "Main.Routes", "Route", []string{"alice::1220"}, WithUserID("app"), WithReadAs("bob::1220"))
+
+	cid, err := c.Create(context.Background(), &model.CreateCommand{TemplateID: "#routes:Main.Routes:Route"})
+	require.NoError(t, err)
+	require.Equal(t, types.CONTRACT_ID("00route"), cid)
+
+	require.Len(t, svc.requests, 1)
+	req := svc.requests[0]
+	require.Equal(t, "app", req.Commands.UserID)
+	require.Equal(t, []string{"alice::1220"}, req.Commands.ActAs)
+	require.Equal(t, []string{"bob::1220"}, req.Commands.ReadAs)
+	require.NotEmpty(t, req.Commands.CommandID)
+	require.Equal(t, model.TransactionShapeLedgerEffects, req.TransactionFormat.TransactionShape)
+	require.Contains(t, req.TransactionFormat.EventFormat.FiltersByParty, "alice::1220")
+
+	svc.events = nil
+	_, err = c.Create(context.Background(), &model.CreateCommand{TemplateID: "#routes:Main.Routes:Route"})
+	require.ErrorContains(t, err, "did not create a Main.Routes:Route contract")
+}
+
+func TestTemplateClientExercise(t *testing.T) {
+	svc := &fakeCommandService{events: []*model.Event{
+		{Exercised: &model.ExercisedEvent{
+			ContractID:     "00route",
+			TemplateID:     "pkg:Main.Routes:Route",
+			Choice:         "Route_Reassign",
+			ExerciseResult: "00next",
+		}},
+	}}
+	c := NewTemplateClient(svc, "Main.Routes", "Route", []string{"alice::1220"})
+
+	var result types.CONTRACT_ID
+	tx, err := c.Exercise(context.Background(), &model.ExerciseCommand{
+		TemplateID: "#routes:Main.Routes:Route",
+		ContractID: "00route",
+		Choice:     "Route_Reassign",
+	}, &result)
+	require.NoError(t, err)
+	require.Equal(t, "update-1", tx.UpdateID)
+	require.Equal(t, types.CONTRACT_ID("00next"), result)
+
+	_, err = c.Exercise(context.Background(), &model.ExerciseCommand{
+		ContractID: "00route",
+		Choice:     "Route_Close",
+	}, &result)
+	require.ErrorContains(t, err, "has no exercise of Route_Close on 00route")
+
+	_, err = c.Exercise(context.Background(), &model.ExerciseCommand{
+		ContractID: "00route",
+		Choice:     "Route_Close",
+	}, nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,74 @@
+package bind
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+)
+
+// DecodeCreatedEvent verifies that event was created from the template identified by
+// moduleName and templateName and decodes its create arguments into target.
+// The package part of the template ID is not compared, as upgraded packages
+// create contracts of the same template under different package IDs.
+func DecodeCreatedEvent(event *model.CreatedEvent, moduleName, templateName string, target any) error {
+	if event == nil {
+		return fmt.Errorf("created event is nil")
+	}
+
+	if !MatchesTemplateID(event.TemplateID, moduleName, templateName) {
+		return fmt.Errorf("template ID mismatch: expected %s:%s, got %s", moduleName, templateName, event.TemplateID)
+	}
+
+	if event.CreateArguments == nil {
+		return fmt.Errorf("created event %s has no create arguments", event.ContractID)
+	}
+
+	if err := ledger.RecordToStruct(event.CreateArguments, target); err != nil {
+		return fmt.Errorf("failed to decode create arguments of %s: %w", event.ContractID, err)
+	}
+
+	return nil
+}
+
+// DecodeExerciseResult verifies that event exercised choice on a contract of the template
+// identified by moduleName and templateName and decodes the exercise result into target.
+func DecodeExerciseResult(event *model.ExercisedEvent, moduleName, templateName, choice string, target any) error {
+	if event == nil {
+		return fmt.Errorf("exercised event is nil")
+	}
+
+	if !MatchesTemplateID(event.TemplateID, moduleName, templateName) {
+		return fmt.Errorf("template ID mismatch: expected %s:%s, got %s", moduleName, templateName, event.TemplateID)
+	}
+
+	if event.Choice != choice {
+		return fmt.Errorf("choice mismatch: expected %s, got %s", choice, event.Choice)
+	}
+
+	if err := ledger.ValueToStruct(event.ExerciseResult, target); err != nil {
+		return fmt.Errorf("failed to decode result of %s on %s: %w", choice, event.ContractID, err)
+	}
+
+	return nil
+}
+
+// MatchesTemplateID reports whether templateID refers to moduleName:entityName.
+// templateID may be qualified by a package ID or a #package-name reference.
+func MatchesTemplateID(templateID, moduleName, entityName string) bool {
+	_, module, entity, ok := SplitTemplateID(templateID)
+	return ok && module == moduleName && entity == entityName
+}
+
+// SplitTemplateID splits templateID into its package reference, module name and entity name.
+// The package reference is a package ID, a #package-name reference or empty for module:entity.
+// It reports false if templateID has no module name.
+func SplitTemplateID(templateID string) (packageRef, moduleName, entityName string, ok bool) {
+	parts := strings.Split(templateID, ":")
+	if len(parts) < 2 {
+		return "", "", "", false
+	}
+	n := len(parts)
+	return strings.Join(parts[:n-2], ":"), parts[n-2], parts[n-1], true
+}
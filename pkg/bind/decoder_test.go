@@ -0,0 +1,148 @@
+package bind
+
+import (
+	"testing"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+type routeTest struct {
+	Owner  types.PARTY                   `json:"owner"`
+	Limits map[types.INT64]types.NUMERIC `json:"limits"`
+	Labels map[string]types.TEXT         `json:"labels"`
+	Note   *types.TEXT                   `json:"note"`
+}
+
+func TestMatchesTemplateID(t *testing.T) {
+	tests := []struct {
+		templateID string
+		want       bool
+	}{
+		{"6d7e83e8:Main.Routes:Route", true},
+		{"#my-package:Main.Routes:Route", true},
+		{"Main.Routes:Route", true},
+		{"6d7e83e8:Main:Route", false},
+		{"6d7e83e8:Main.Routes:Router", false},
+		{"Route", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, MatchesTemplateID(tt.templateID, "Main.Routes", "Route"), tt.templateID)
+	}
+}
+
+func TestSplitTemplateID(t *testing.T) {
+	tests := []struct {
+		templateID string
+		packageRef string
+		moduleName string
+		entityName string
+		ok         bool
+	}{
+		{"6d7e83e8:Main.Routes:Route", "6d7e83e8", "Main.Routes", "Route", true},
+		{"#my-package:Main.Routes:Route", "#my-package", "Main.Routes", "Route", true},
+		{"Main.Routes:Route", "", "Main.Routes", "Route", true},
+		{"Route", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		packageRef, moduleName, entityName, ok := SplitTemplateID(tt.templateID)
+		require.Equal(t, tt.ok, ok, tt.templateID)
+		require.Equal(t, tt.packageRef, packageRef, tt.templateID)
+		require.Equal(t, tt.moduleName, moduleName, tt.templateID)
+		require.Equal(t, tt.entityName, entityName, tt.templateID)
+	}
+}
+
+func TestDecodeCreatedEvent(t *testing.T) {
+	record := &v2.Record{Fields: []*v2.RecordField{
+		{Label: "owner", Value: &v2.Value{Sum: &v2.Value_Party{Party: "alice::1220"}}},
+		{Label: "limits", Value: &v2.Value{Sum: &v2.Value_GenMap{GenMap: &v2.GenMap{Entries: []*v2.GenMap_Entry{
+			{Key: &v2.Value{Sum: &v2.Value_Int64{Int64: 1}}, Value: &v2.Value{Sum: &v2.Value_Numeric{Numeric: "1.5"}}},
+			{Key: &v2.Value{Sum: &v2.Value_Int64{Int64: 2}}, Value: &v2.Value{Sum: &v2.Value_Numeric{Numeric: "2.5"}}},
+		}}}}},
+		{Label: "labels", Value: &v2.Value{Sum: &v2.Value_TextMap{TextMap: &v2.TextMap{Entries: []*v2.TextMap_Entry{
+			{Key: "env", Value: &v2.Value{Sum: &v2.Value_Text{Text: "prod"}}},
+		}}}}},
+		{Label: "note", Value: &v2.Value{Sum: &v2.Value_Optional{Optional: &v2.Optional{}}}},
+	}}
+
+	event := &model.CreatedEvent{
+		ContractID:      "00abc",
+		TemplateID:      "6d7e83e8:Main.Routes:Route",
+		CreateArguments: record,
+	}
+
+	var route routeTest
+	require.NoError(t, DecodeCreatedEvent(event, "Main.Routes", "Route", &route))
+	require.Equal(t, routeTest{
+		Owner:  "alice::1220",
+		Limits: map[types.INT64]types.NUMERIC{1: "1.5", 2: "2.5"},
+		Labels: map[string]types.TEXT{"env": "prod"},
+	}, route)
+
+	err := DecodeCreatedEvent(event, "Main.Routes", "Other", &route)
+	require.ErrorContains(t, err, "template ID mismatch")
+}
+
+func TestDecodeExerciseResult(t *testing.T) {
+	event := &model.ExercisedEvent{
+		ContractID:     "00abc",
+		TemplateID:     "6d7e83e8:Main.Routes:Route",
+		Choice:         "Route_Reassign",
+		ExerciseResult: "00def",
+	}
+
+	var cid types.CONTRACT_ID
+	require.NoError(t, DecodeExerciseResult(event, "Main.Routes", "Route", "Route_Reassign", &cid))
+	require.Equal(t, types.CONTRACT_ID("00def"), cid)
+
+	err := DecodeExerciseResult(event, "Main.Routes", "Route", "Archive", &cid)
+	require.ErrorContains(t, err, "choice mismatch")
+
+	err = DecodeExerciseResult(event, "Main.Routes", "Other", "Route_Reassign", &cid)
+	require.ErrorContains(t, err, "template ID mismatch")
+}
+
+func TestDecodeExerciseResultTuple(t *testing.T) {
+	event := &model.ExercisedEvent{
+		TemplateID: "6d7e83e8:Main.Routes:Route",
+		Choice:     "Route_Split",
+		ExerciseResult: &v2.Value{Sum: &v2.Value_Record{Record: &v2.Record{Fields: []*v2.RecordField{
+			{Label: "_1", Value: &v2.Value{Sum: &v2.Value_ContractId{ContractId: "00left"}}},
+			{Label: "_2", Value: &v2.Value{Sum: &v2.Value_ContractId{ContractId: "00right"}}},
+		}}}},
+	}
+
+	var result types.TUPLE2
+	require.NoError(t, DecodeExerciseResult(event, "Main.Routes", "Route", "Route_Split", &result))
+	require.Equal(t, types.TUPLE2{First: "00left", Second: "00right"}, result)
+}
+
+func TestDecodeExerciseResultRecord(t *testing.T) {
+	// Exercise results of transactions are already converted from their protobuf form
+	event := &model.ExercisedEvent{
+		TemplateID: "#routes:Main.Routes:Route",
+		Choice:     "Route_Describe",
+		ExerciseResult: map[string]interface{}{
+			"owner":  "alice::1220",
+			"limits": map[string]interface{}{},
+			"labels": map[string]interface{}{"env": "prod"},
+			"note":   "primary",
+		},
+	}
+
+	var route routeTest
+	require.NoError(t, DecodeExerciseResult(event, "Main.Routes", "Route", "Route_Describe", &route))
+	note := types.TEXT("primary")
+	require.Equal(t, routeTest{
+		Owner:  "alice::1220",
+		Limits: map[types.INT64]types.NUMERIC{},
+		Labels: map[string]types.TEXT{"env": "prod"},
+		Note:   &note,
+	}, route)
+}
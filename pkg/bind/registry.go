@@ -0,0 +1,198 @@
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+)
+
+var (
+	// ErrUnknownTemplate is passed to the fallback for events of templates that are not registered.
+	ErrUnknownTemplate = errors.New("template is not registered")
+	// ErrUnknownChoice is passed to the fallback for exercises of choices that are not registered.
+	ErrUnknownChoice = errors.New("choice is not registered")
+)
+
+// Template is implemented by the template types generated by godaml.
+type Template interface {
+	GetTemplateID() string
+}
+
+// TemplateEvent is a ledger event decoded into the generated types of its template:
+// a *Created[T], *Archived[T] or *Exercised[T, Arg].
+type TemplateEvent interface {
+	// TemplateEventOffset returns the offset of the event
+	TemplateEventOffset() int64
+}
+
+// Created is a contract creation decoded into its template type T.
+type Created[T Template] struct {
+	UpdateID   string
+	ContractID types.CONTRACT_ID
+	Payload    T
+	Event      *model.CreatedEvent
+}
+
+func (e *Created[T]) TemplateEventOffset() int64 { return e.Event.Offset }
+
+// Archived is the archival of a contract of template T.
+type Archived[T Template] struct {
+	UpdateID   string
+	ContractID types.CONTRACT_ID
+	Event      *model.ArchivedEvent
+}
+
+func (e *Archived[T]) TemplateEventOffset() int64 { return e.Event.Offset }
+
+// Exercised is the exercise of a choice on a contract of template T, with the choice
+// argument decoded into Arg.
+type Exercised[T Template, Arg any] struct {
+	UpdateID   string
+	ContractID types.CONTRACT_ID
+	Choice     string
+	Argument   Arg
+	Consuming  bool
+	Event      *model.ExercisedEvent
+}
+
+func (e *Exercised[T, Arg]) TemplateEventOffset() int64 { return e.Event.Offset }
+
+type templateDecoder struct {
+	created  func(updateID string, event *model.CreatedEvent) (TemplateEvent, error)
+	archived func(updateID string, event *model.ArchivedEvent) TemplateEvent
+	choices  map[string]func(updateID string, event *model.ExercisedEvent) (TemplateEvent, error)
+}
+
+// Registry maps template IDs to the decoders of their generated types. Templates are matched
+// by the DAML module and entity name of their GetTemplateID, which is not the Go type name for
+// renamed templates, so events of every version of a package are decoded.
+// Generated code provides a Register<Template> function for every template.
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]*templateDecoder
+}
+
+func NewRegistry() *Registry {
+	return &Registry{templates: make(map[string]*templateDecoder)}
+}
+
+// RegisterTemplate registers the created and archived events of template T.
+func RegisterTemplate[T Template](r *Registry) {
+	var zero T
+	key := templateKey(zero.GetTemplateID())
+	moduleName, entityName, _ := strings.Cut(key, ":")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	decoder := r.decoder(key)
+	decoder.created = func(updateID string, event *model.CreatedEvent) (TemplateEvent, error) {
+		var payload T
+		if err := DecodeCreatedEvent(event, moduleName, entityName, &payload); err != nil {
+			return nil, err
+		}
+		return &Created[T]{
+			UpdateID:   updateID,
+			ContractID: types.CONTRACT_ID(event.ContractID),
+			Payload:    payload,
+			Event:      event,
+		}, nil
+	}
+	decoder.archived = func(updateID string, event *model.ArchivedEvent) TemplateEvent {
+		return &Archived[T]{
+			UpdateID:   updateID,
+			ContractID: types.CONTRACT_ID(event.ContractID),
+			Event:      event,
+		}
+	}
+}
+
+// RegisterChoice registers the exercised events of choice on template T, whose argument is
+// decoded into Arg. Choices without arguments use types.UNIT.
+func RegisterChoice[T Template, Arg any](r *Registry, choice string) {
+	var zero T
+	key := templateKey(zero.GetTemplateID())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decoder(key).choices[choice] = func(updateID string, event *model.ExercisedEvent) (TemplateEvent, error) {
+		var argument Arg
+		if err := ledger.ValueToStruct(event.ChoiceArgument, &argument); err != nil {
+			return nil, fmt.Errorf("failed to decode argument of %s on %s: %w", choice, event.ContractID, err)
+		}
+		return &Exercised[T, Arg]{
+			UpdateID:   updateID,
+			ContractID: types.CONTRACT_ID(event.ContractID),
+			Choice:     event.Choice,
+			Argument:   argument,
+			Consuming:  event.Consuming,
+			Event:      event,
+		}, nil
+	}
+}
+
+func (r *Registry) decoder(key string) *templateDecoder {
+	decoder, ok := r.templates[key]
+	if !ok {
+		decoder = &templateDecoder{choices: make(map[string]func(string, *model.ExercisedEvent) (TemplateEvent, error))}
+		r.templates[key] = decoder
+	}
+	return decoder
+}
+
+// Decode decodes event of the transaction updateID. Events of templates or choices that are
+// not registered return ErrUnknownTemplate or ErrUnknownChoice.
+func (r *Registry) Decode(updateID string, event *model.Event) (TemplateEvent, error) {
+	var templateID string
+	switch {
+	case event.Created != nil:
+		templateID = event.Created.TemplateID
+	case event.Archived != nil:
+		templateID = event.Archived.TemplateID
+	case event.Exercised != nil:
+		templateID = event.Exercised.TemplateID
+	default:
+		return nil, fmt.Errorf("event has no created, archived or exercised event")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	decoder, ok := r.templates[templateKey(templateID)]
+	switch {
+	case event.Created != nil:
+		if !ok || decoder.created == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, templateID)
+		}
+		return decoder.created(updateID, event.Created)
+	case event.Archived != nil:
+		if !ok || decoder.archived == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, templateID)
+		}
+		return decoder.archived(updateID, event.Archived), nil
+	default:
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, templateID)
+		}
+		decode, ok := decoder.choices[event.Exercised.Choice]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s on %s", ErrUnknownChoice, event.Exercised.Choice, templateID)
+		}
+		return decode(updateID, event.Exercised)
+	}
+}
+
+// templateKey reduces a template ID to module:entity, dropping the package ID or name.
+func templateKey(templateID string) string {
+	_, moduleName, entityName, ok := SplitTemplateID(templateID)
+	if !ok {
+		return templateID
+	}
+	return moduleName + ":" + entityName
+}
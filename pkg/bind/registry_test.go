@@ -0,0 +1,142 @@
+package bind
+
+import (
+	"context"
+	"testing"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+)
+
+type tokenTest struct {
+	Owner  types.PARTY   `json:"owner"`
+	Amount types.NUMERIC `json:"amount"`
+}
+
+func (t tokenTest) GetTemplateID() string { return "#airdrop:Main.Token:Token" }
+
+type transferTest struct {
+	NewOwner types.PARTY `json:"newOwner"`
+}
+
+func tokenEvent(contractID, templateID, owner string) *model.CreatedEvent {
+	return &model.CreatedEvent{
+		Offset:     7,
+		ContractID: contractID,
+		TemplateID: templateID,
+		CreateArguments: &v2.Record{Fields: []*v2.RecordField{
+			{Label: "owner", Value: &v2.Value{Sum: &v2.Value_Party{Party: owner}}},
+			{Label: "amount", Value: &v2.Value{Sum: &v2.Value_Numeric{Numeric: "10.0"}}},
+		}},
+	}
+}
+
+func newTokenRegistry() *Registry {
+	registry := NewRegistry()
+	RegisterTemplate[tokenTest](registry)
+	RegisterChoice[tokenTest, transferTest](registry, "Transfer")
+	RegisterChoice[tokenTest, types.UNIT](registry, "Archive")
+	return registry
+}
+
+func TestRegistryDecode(t *testing.T) {
+	registry := newTokenRegistry()
+
+	event, err := registry.Decode("update-1", &model.Event{Created: tokenEvent("00abc", "6d7e83e8:Main.Token:Token", "alice")})
+	require.NoError(t, err)
+	created, ok := event.(*Created[tokenTest])
+	require.True(t, ok)
+	require.Equal(t, "update-1", created.UpdateID)
+	require.Equal(t, types.CONTRACT_ID("00abc"), created.ContractID)
+	require.Equal(t, tokenTest{Owner: "alice", Amount: "10.0"}, created.Payload)
+	require.Equal(t, int64(7), event.TemplateEventOffset())
+
+	event, err = registry.Decode("update-2", &model.Event{Exercised: &model.ExercisedEvent{
+		ContractID: "00abc",
+		TemplateID: "6d7e83e8:Main.Token:Token",
+		Choice:     "Transfer",
+		ChoiceArgument: &v2.Value{Sum: &v2.Value_Record{Record: &v2.Record{Fields: []*v2.RecordField{
+			{Label: "newOwner", Value: &v2.Value{Sum: &v2.Value_Party{Party: "bob"}}},
+		}}}},
+		Consuming: true,
+	}})
+	require.NoError(t, err)
+	exercised, ok := event.(*Exercised[tokenTest, transferTest])
+	require.True(t, ok)
+	require.Equal(t, transferTest{NewOwner: "bob"}, exercised.Argument)
+	require.True(t, exercised.Consuming)
+
+	event, err = registry.Decode("update-3", &model.Event{Exercised: &model.ExercisedEvent{
+		ContractID:     "00abc",
+		TemplateID:     "6d7e83e8:Main.Token:Token",
+		Choice:         "Archive",
+		ChoiceArgument: &v2.Value{Sum: &v2.Value_Record{Record: &v2.Record{}}},
+		Consuming:      true,
+	}})
+	require.NoError(t, err)
+	require.IsType(t, &Exercised[tokenTest, types.UNIT]{}, event)
+
+	event, err = registry.Decode("update-3", &model.Event{Archived: &model.ArchivedEvent{ContractID: "00abc", TemplateID: "#airdrop:Main.Token:Token"}})
+	require.NoError(t, err)
+	archived, ok := event.(*Archived[tokenTest])
+	require.True(t, ok)
+	require.Equal(t, types.CONTRACT_ID("00abc"), archived.ContractID)
+
+	_, err = registry.Decode("update-4", &model.Event{Created: tokenEvent("00def", "6d7e83e8:Main.Token:Voucher", "alice")})
+	require.ErrorIs(t, err, ErrUnknownTemplate)
+
+	_, err = registry.Decode("update-4", &model.Event{Exercised: &model.ExercisedEvent{TemplateID: "6d7e83e8:Main.Token:Token", Choice: "Split"}})
+	require.ErrorIs(t, err, ErrUnknownChoice)
+}
+
+func TestRegistryDecodeUpdates(t *testing.T) {
+	registry := newTokenRegistry()
+
+	updates := make(chan *model.GetUpdatesResponse, 3)
+	updates <- &model.GetUpdatesResponse{Update: &model.Update{OffsetCheckpoint: &model.OffsetCheckpoint{Offset: 5}}}
+	updates <- &model.GetUpdatesResponse{Update: &model.Update{Transaction: &model.Transaction{
+		UpdateID: "update-1",
+		Events: []*model.Event{
+			{Created: tokenEvent("00abc", "6d7e83e8:Main.Token:Token", "alice")},
+			{Created: tokenEvent("00def", "6d7e83e8:Main.Token:Voucher", "alice")},
+			{Archived: &model.ArchivedEvent{ContractID: "00abc", TemplateID: "6d7e83e8:Main.Token:Token"}},
+		},
+	}}}
+	close(updates)
+
+	var unknown []string
+	var events []TemplateEvent
+	for event := range registry.DecodeUpdates(context.Background(), updates, func(updateID string, event *model.Event, err error) {
+		require.ErrorIs(t, err, ErrUnknownTemplate)
+		unknown = append(unknown, updateID+"/"+event.Created.ContractID)
+	}) {
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 2)
+	require.IsType(t, &Created[tokenTest]{}, events[0])
+	require.IsType(t, &Archived[tokenTest]{}, events[1])
+	require.Equal(t, []string{"update-1/00def"}, unknown)
+}
+
+func TestRegistryDecodeActiveContracts(t *testing.T) {
+	registry := newTokenRegistry()
+
+	contracts := make(chan *model.GetActiveContractsResponse, 2)
+	contracts <- &model.GetActiveContractsResponse{ContractEntry: &model.ActiveContractEntry{
+		ActiveContract: &model.ActiveContract{CreatedEvent: tokenEvent("00abc", "6d7e83e8:Main.Token:Token", "alice")},
+	}}
+	contracts <- &model.GetActiveContractsResponse{ContractEntry: &model.ActiveContractEntry{
+		ActiveContract: &model.ActiveContract{CreatedEvent: tokenEvent("00def", "6d7e83e8:Main.Token:Token", "bob")},
+	}}
+	close(contracts)
+
+	var owners []types.PARTY
+	for event := range registry.DecodeActiveContracts(context.Background(), contracts, nil) {
+		owners = append(owners, event.(*Created[tokenTest]).Payload.Owner)
+	}
+	require.Equal(t, []types.PARTY{"alice", "bob"}, owners)
+}
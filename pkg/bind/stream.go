@@ -0,0 +1,93 @@
+package bind
+
+import (
+	"context"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// FallbackFunc handles the events a Registry cannot decode: err wraps ErrUnknownTemplate or
+// ErrUnknownChoice for events that are not registered, or is the decoding error otherwise.
+type FallbackFunc func(updateID string, event *model.Event, err error)
+
+// DecodeUpdates decodes the transactions read from updates into template events, until updates
+// is closed or ctx is cancelled. Events that cannot be decoded are passed to fallback, which may
+// be nil to drop them. Reassignments and offset checkpoints are skipped.
+func (r *Registry) DecodeUpdates(ctx context.Context, updates <-chan *model.GetUpdatesResponse, fallback FallbackFunc) <-chan TemplateEvent {
+	events := make(chan TemplateEvent)
+	go func() {
+		defer close(events)
+		for {
+			var resp *model.GetUpdatesResponse
+			var ok bool
+			select {
+			case resp, ok = <-updates:
+			case <-ctx.Done():
+				return
+			}
+			if !ok {
+				return
+			}
+			if resp.Update == nil || resp.Update.Transaction == nil {
+				continue
+			}
+
+			tx := resp.Update.Transaction
+			for _, event := range tx.Events {
+				if !r.emit(ctx, events, tx.UpdateID, event, fallback) {
+					return
+				}
+			}
+		}
+	}()
+	return events
+}
+
+// DecodeActiveContracts decodes the active contracts read from contracts into *Created[T]
+// events, until contracts is closed or ctx is cancelled. Contracts that cannot be decoded are
+// passed to fallback, which may be nil to drop them. Incomplete reassignments are skipped.
+func (r *Registry) DecodeActiveContracts(ctx context.Context, contracts <-chan *model.GetActiveContractsResponse, fallback FallbackFunc) <-chan TemplateEvent {
+	events := make(chan TemplateEvent)
+	go func() {
+		defer close(events)
+		for {
+			var resp *model.GetActiveContractsResponse
+			var ok bool
+			select {
+			case resp, ok = <-contracts:
+			case <-ctx.Done():
+				return
+			}
+			if !ok {
+				return
+			}
+
+			entry, isActive := resp.ContractEntry.(*model.ActiveContractEntry)
+			if !isActive || entry.ActiveContract == nil || entry.ActiveContract.CreatedEvent == nil {
+				continue
+			}
+			if !r.emit(ctx, events, "", &model.Event{Created: entry.ActiveContract.CreatedEvent}, fallback) {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// emit sends the decoded event, or passes it to fallback. It returns false once ctx is cancelled.
+func (r *Registry) emit(ctx context.Context, events chan<- TemplateEvent, updateID string, event *model.Event, fallback FallbackFunc) bool {
+	decoded, err := r.Decode(updateID, event)
+	if err != nil {
+		if fallback != nil {
+			fallback(updateID, event, err)
+		}
+		return ctx.Err() == nil
+	}
+
+	select {
+	case events <- decoded:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -25,7 +27,10 @@ func NewClient(config *Config) *Client {
 }
 
 func (c *Client) Connect(ctx context.Context) (*Connection, error) {
-	opts := c.buildDialOptions()
+	opts, err := c.buildDialOptions(c.config.TLS)
+	if err != nil {
+		return nil, err
+	}
 
 	conn, err := grpc.DialContext(ctx, c.config.Address, opts...)
 	if err != nil {
@@ -36,7 +41,16 @@ func (c *Client) Connect(ctx context.Context) (*Connection, error) {
 
 	var adminConn *grpc.ClientConn
 	if c.config.AdminAddress != "" {
-		adminConn, err = grpc.DialContext(ctx, c.config.AdminAddress, opts...)
+		adminOpts := opts
+		if c.config.AdminTLS != nil {
+			adminOpts, err = c.buildDialOptions(c.config.AdminTLS)
+			if err != nil {
+				c.conn.Close()
+				return nil, err
+			}
+		}
+
+		adminConn, err = grpc.DialContext(ctx, c.config.AdminAddress, adminOpts...)
 		if err != nil {
 			c.conn.Close()
 			return nil, fmt.Errorf("failed to connect to DAML admin endpoint: %w", err)
@@ -60,11 +74,22 @@ func (c *Client) Close() error {
 	return err
 }
 
-func (c *Client) buildDialOptions() []grpc.DialOption {
+func (c *Client) buildDialOptions(tlsCfg *TLSConfig) ([]grpc.DialOption, error) {
 	var opts []grpc.DialOption
+	var unaryInterceptors []grpc.UnaryClientInterceptor
+	var streamInterceptors []grpc.StreamClientInterceptor
+
+	// Retries come first, so that every attempt goes through the auth interceptors and gets a fresh token
+	if c.config.Retry != nil {
+		unaryInterceptors = append(unaryInterceptors, c.config.Retry.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, c.config.Retry.StreamInterceptor())
+	}
 
-	if c.config.TLS != nil {
-		tlsConfig := c.buildTLSConfig()
+	if tlsCfg != nil {
+		tlsConfig, err := buildTLSConfig(tlsCfg)
+		if err != nil {
+			return nil, err
+		}
 		creds := credentials.NewTLS(tlsConfig)
 		opts = append(opts, grpc.WithTransportCredentials(creds))
 
@@ -77,26 +102,53 @@ func (c *Client) buildDialOptions() []grpc.DialOption {
 
 		if c.config.Auth != nil {
 			bearerAuth := c.createBearerAuth()
-			opts = append(opts,
-				grpc.WithUnaryInterceptor(bearerAuth.UnaryInterceptor()),
-				grpc.WithStreamInterceptor(bearerAuth.StreamInterceptor()),
-			)
+			unaryInterceptors = append(unaryInterceptors, bearerAuth.UnaryInterceptor())
+			streamInterceptors = append(streamInterceptors, bearerAuth.StreamInterceptor())
 		}
 	}
 
-	return opts
+	if len(unaryInterceptors) > 0 {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+			grpc.WithChainStreamInterceptor(streamInterceptors...),
+		)
+	}
+
+	return opts, nil
 }
 
-func (c *Client) buildTLSConfig() *tls.Config {
+func buildTLSConfig(cfg *TLSConfig) (*tls.Config, error) {
 	tlsConfig := &tls.Config{
-		ServerName:         c.config.TLS.ServerName,
-		InsecureSkipVerify: c.config.TLS.InsecureSkipVerify,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
 	}
 
-	if c.config.TLS.CertFile != "" {
+	if cfg.CertFile != "" {
+		pem, err := os.ReadFile(cfg.CertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", cfg.CertFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		if cfg.ClientCertFile == "" || cfg.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both client certificate and key files are required for mutual TLS")
+		}
+
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	return tlsConfig
+	return tlsConfig, nil
 }
 
 func (c *Client) createBearerAuth() *auth.BearerTokenAuth {
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+type testCA struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+}
+
+type testCert struct {
+	tls      tls.Certificate
+	certFile string
+	keyFile  string
+}
+
+func newTestCA(t *testing.T, dir, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+
+	return &testCA{cert: cert, key: key, certFile: certFile}
+}
+
+func (ca *testCA) issue(t *testing.T, dir, name string, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "PRIVATE KEY", keyDER)
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	return &testCert{tls: cert, certFile: certFile, keyFile: keyFile}
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+type versionServer struct {
+	v2.UnimplementedVersionServiceServer
+	version string
+}
+
+func (s *versionServer) GetLedgerApiVersion(context.Context, *v2.GetLedgerApiVersionRequest) (*v2.GetLedgerApiVersionResponse, error) {
+	return &v2.GetLedgerApiVersionResponse{Version: s.version}, nil
+}
+
+// startTLSServer serves the version service over TLS with serverCert, requiring client
+// certificates signed by clientCA if it is set. It returns the server address.
+func startTLSServer(t *testing.T, serverCert *testCert, clientCA *testCA, version string) string {
+	t.Helper()
+
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{serverCert.tls}}
+	if clientCA != nil {
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCA.cert)
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+	v2.RegisterVersionServiceServer(server, &versionServer{version: version})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func getVersion(ctx context.Context, conn *grpc.ClientConn) (string, error) {
+	resp, err := v2.NewVersionServiceClient(conn).GetLedgerApiVersion(ctx, &v2.GetLedgerApiVersionRequest{})
+	if err != nil {
+		return "", err
+	}
+	return resp.Version, nil
+}
+
+func TestConnectTLSWithCertFile(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	address := startTLSServer(t, ca.issue(t, dir, "server", x509.ExtKeyUsageServerAuth), nil, "3.3.0")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := NewClient(NewConfig(WithAddress(address), WithTLS(&TLSConfig{CertFile: ca.certFile})))
+	conn, err := client.Connect(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+
+	version, err := getVersion(ctx, conn.GRPCConn())
+	require.NoError(t, err)
+	require.Equal(t, "3.3.0", version)
+
+	// Without the CA the server certificate cannot be verified against the system roots
+	untrusted := NewClient(NewConfig(WithAddress(address), WithTLS(&TLSConfig{})))
+	conn, err = untrusted.Connect(ctx)
+	require.NoError(t, err)
+	defer untrusted.Close()
+
+	_, err = getVersion(ctx, conn.GRPCConn())
+	require.ErrorContains(t, err, "certificate")
+}
+
+func TestConnectMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	clientCert := ca.issue(t, dir, "client", x509.ExtKeyUsageClientAuth)
+	address := startTLSServer(t, ca.issue(t, dir, "server", x509.ExtKeyUsageServerAuth), ca, "3.3.0")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := NewClient(NewConfig(WithAddress(address), WithTLS(&TLSConfig{
+		CertFile:       ca.certFile,
+		ClientCertFile: clientCert.certFile,
+		ClientKeyFile:  clientCert.keyFile,
+	})))
+	conn, err := client.Connect(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+
+	version, err := getVersion(ctx, conn.GRPCConn())
+	require.NoError(t, err)
+	require.Equal(t, "3.3.0", version)
+
+	withoutClientCert := NewClient(NewConfig(WithAddress(address), WithTLS(&TLSConfig{CertFile: ca.certFile})))
+	conn, err = withoutClientCert.Connect(ctx)
+	require.NoError(t, err)
+	defer withoutClientCert.Close()
+
+	_, err = getVersion(ctx, conn.GRPCConn())
+	require.Error(t, err)
+}
+
+func TestConnectAdminTLS(t *testing.T) {
+	dir := t.TempDir()
+	ledgerCA := newTestCA(t, dir, "ledger-ca")
+	adminCA := newTestCA(t, dir, "admin-ca")
+	adminClientCert := adminCA.issue(t, dir, "admin-client", x509.ExtKeyUsageClientAuth)
+
+	address := startTLSServer(t, ledgerCA.issue(t, dir, "ledger", x509.ExtKeyUsageServerAuth), nil, "ledger")
+	adminAddress := startTLSServer(t, adminCA.issue(t, dir, "admin", x509.ExtKeyUsageServerAuth), adminCA, "admin")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := NewClient(NewConfig(
+		WithAddress(address),
+		WithAdminAddress(adminAddress),
+		WithTLS(&TLSConfig{CertFile: ledgerCA.certFile}),
+		WithAdminTLS(&TLSConfig{
+			CertFile:       adminCA.certFile,
+			ClientCertFile: adminClientCert.certFile,
+			ClientKeyFile:  adminClientCert.keyFile,
+		}),
+	))
+	conn, err := client.Connect(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+
+	version, err := getVersion(ctx, conn.GRPCConn())
+	require.NoError(t, err)
+	require.Equal(t, "ledger", version)
+
+	version, err = getVersion(ctx, conn.AdminGRPCConn())
+	require.NoError(t, err)
+	require.Equal(t, "admin", version)
+}
+
+func TestConnectInvalidTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name    string
+		tls     *TLSConfig
+		wantErr string
+	}{
+		{"missing CA file", &TLSConfig{CertFile: filepath.Join(dir, "missing.pem")}, "failed to read CA certificate file"},
+		{"invalid CA file", &TLSConfig{CertFile: notPEM}, "no PEM certificates found"},
+		{"client certificate without key", &TLSConfig{ClientCertFile: notPEM}, "both client certificate and key files are required"},
+		{"invalid client certificate", &TLSConfig{ClientCertFile: notPEM, ClientKeyFile: notPEM}, "failed to load client certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(NewConfig(WithAddress("127.0.0.1:0"), WithTLS(tt.tls)))
+			_, err := client.Connect(context.Background())
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -8,11 +8,19 @@ type Config struct {
 	Address      string
 	AdminAddress string
 	TLS          *TLSConfig
-	Auth         *AuthConfig
+	// AdminTLS configures TLS for AdminAddress. If nil, TLS is used for both endpoints.
+	AdminTLS *TLSConfig
+	Auth     *AuthConfig
+	// Retry enables retries of failed requests, see WithRetry
+	Retry *RetryConfig
 }
 
 type TLSConfig struct {
-	CertFile           string
+	// CertFile is a PEM bundle of CA certificates to verify the server with. If empty, the system roots are used.
+	CertFile string
+	// ClientCertFile and ClientKeyFile are the PEM certificate and key presented to the server for mutual TLS.
+	ClientCertFile     string
+	ClientKeyFile      string
 	ServerName         string
 	InsecureSkipVerify bool
 }
@@ -42,6 +50,12 @@ func WithTLS(tls *TLSConfig) ConfigOption {
 	}
 }
 
+func WithAdminTLS(tls *TLSConfig) ConfigOption {
+	return func(c *Config) {
+		c.AdminTLS = tls
+	}
+}
+
 func WithToken(token string) ConfigOption {
 	return func(c *Config) {
 		if c.Auth == nil {
@@ -25,8 +25,19 @@ func NewDamlClient(token string, grpcAddress string) *DamlClient {
 }
 
 func (c *DamlClient) WithTLSConfig(cfg TlsConfig) *DamlClient {
-	c.config.TLS = &TLSConfig{
-		CertFile: cfg.Certificate,
+	c.config.TLS = cfg.toTLSConfig()
+	return c
+}
+
+// WithAdminTLSConfig configures TLS for the admin endpoint separately from the ledger endpoint.
+func (c *DamlClient) WithAdminTLSConfig(cfg TlsConfig) *DamlClient {
+	c.config.AdminTLS = cfg.toTLSConfig()
+	return c
+}
+
+func (c *DamlClient) WithTokenProvider(provider auth.TokenProvider) *DamlClient {
+	c.config.Auth = &AuthConfig{
+		TokenProvider: provider,
 	}
 	return c
 }
@@ -47,7 +58,21 @@ func (c *DamlClient) Build(ctx context.Context) (*DamlBindingClient, error) {
 }
 
 type TlsConfig struct {
+	// Certificate is the CA certificate file to verify the server with
 	Certificate string
+	// ClientCertificate and ClientKey are the certificate and key files for mutual TLS
+	ClientCertificate string
+	ClientKey         string
+	ServerName        string
+}
+
+func (cfg TlsConfig) toTLSConfig() *TLSConfig {
+	return &TLSConfig{
+		CertFile:       cfg.Certificate,
+		ClientCertFile: cfg.ClientCertificate,
+		ClientKeyFile:  cfg.ClientKey,
+		ServerName:     cfg.ServerName,
+	}
 }
 
 func Connect(ctx context.Context, address string, opts ...ConfigOption) (*Connection, error) {
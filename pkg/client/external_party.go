@@ -0,0 +1,203 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/smartcontractkit/go-daml/pkg/crypto"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+const defaultOnboardingPollInterval = 500 * time.Millisecond
+
+type onboardingConfig struct {
+	permission   model.ParticipantPermission
+	pollInterval time.Duration
+}
+
+type OnboardingOption func(*onboardingConfig)
+
+// WithOnboardingPermission sets the permission of the participant hosting the external party.
+// Defaults to confirmation, as external parties submit through interactive submissions.
+func WithOnboardingPermission(permission model.ParticipantPermission) OnboardingOption {
+	return func(c *onboardingConfig) {
+		c.permission = permission
+	}
+}
+
+// WithOnboardingPollInterval sets how often the topology state is polled while waiting for
+// the party to become active.
+func WithOnboardingPollInterval(interval time.Duration) OnboardingOption {
+	return func(c *onboardingConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// OnboardExternalParty onboards an external party whose namespace and protocol key is the key of
+// signer, hosted on the participant of the client. It generates the namespace delegation,
+// party-to-key and party-to-participant topology transactions, signs them, allocates the party
+// on the synchronizer and waits until the party-to-participant mapping is active. It returns
+// the party ID, partyHint::<key fingerprint>.
+func (c *DamlBindingClient) OnboardExternalParty(ctx context.Context, synchronizerID, partyHint string, signer crypto.Signer, opts ...OnboardingOption) (string, error) {
+	cfg := &onboardingConfig{
+		permission:   model.ParticipantPermissionConfirmation,
+		pollInterval: defaultOnboardingPollInterval,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	participantID, err := c.PartyMng.GetParticipantID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get participant ID: %w", err)
+	}
+
+	publicKey, err := crypto.NewPublicKey(signer.PublicKey(), model.SigningKeyUsageNamespace, model.SigningKeyUsageProtocol)
+	if err != nil {
+		return "", err
+	}
+	namespace := signer.Fingerprint()
+	partyID := partyHint + "::" + namespace
+
+	store := &model.StoreID{Value: "synchronizer:" + synchronizerID}
+	proposals := []*model.GenerateTransactionProposal{
+		{
+			Operation: model.OperationAddReplace,
+			Serial:    1,
+			Mapping: &model.NamespaceDelegationMapping{
+				Namespace:        namespace,
+				TargetKey:        *publicKey,
+				IsRootDelegation: true,
+			},
+			Store: store,
+		},
+		{
+			Operation: model.OperationAddReplace,
+			Serial:    1,
+			Mapping: &model.PartyToKeyMapping{
+				Party:       partyID,
+				Threshold:   1,
+				SigningKeys: []model.PublicKey{*publicKey},
+			},
+			Store: store,
+		},
+		{
+			Operation: model.OperationAddReplace,
+			Serial:    1,
+			Mapping: &model.PartyToParticipantMapping{
+				Party:     partyID,
+				Threshold: 1,
+				Participants: []model.HostingParticipant{
+					{
+						ParticipantUID: participantID,
+						Permission:     cfg.permission,
+					},
+				},
+			},
+			Store: store,
+		},
+	}
+
+	generated, err := c.TopologyManagerWrite.GenerateTransactions(ctx, &model.GenerateTransactionsRequest{
+		Proposals: proposals,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate onboarding transactions: %w", err)
+	}
+	if len(generated.GeneratedTransactions) != len(proposals) {
+		return "", fmt.Errorf("expected %d onboarding transactions, got %d", len(proposals), len(generated.GeneratedTransactions))
+	}
+
+	onboardingTxs := make([]model.SignedTransaction, len(generated.GeneratedTransactions))
+	transactionHashes := make([][]byte, len(generated.GeneratedTransactions))
+	for i, tx := range generated.GeneratedTransactions {
+		signature, err := signer.Sign(ctx, tx.TransactionHash)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign onboarding transaction: %w", err)
+		}
+		onboardingTxs[i] = model.SignedTransaction{
+			Transaction: tx.SerializedTransaction,
+			Signatures:  []model.Signature{*signature},
+		}
+		transactionHashes[i] = tx.TransactionHash
+	}
+
+	multiHashSignature, err := signer.Sign(ctx, multiTransactionHash(transactionHashes))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign onboarding transactions hash: %w", err)
+	}
+
+	allocatedPartyID, err := c.PartyMng.AllocateExternalParty(ctx, synchronizerID, onboardingTxs, []model.Signature{*multiHashSignature}, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate external party: %w", err)
+	}
+
+	if err := c.waitForPartyToParticipant(ctx, store, allocatedPartyID, participantID, cfg.pollInterval); err != nil {
+		return "", err
+	}
+
+	return allocatedPartyID, nil
+}
+
+// waitForPartyToParticipant polls the topology store until the party-to-participant mapping of
+// party is active and hosts it on participantID.
+func (c *DamlBindingClient) waitForPartyToParticipant(ctx context.Context, store *model.StoreID, party, participantID string, pollInterval time.Duration) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := c.TopologyManagerRead.ListPartyToParticipant(ctx, &model.ListPartyToParticipantRequest{
+			BaseQuery:   &model.BaseQuery{Store: store},
+			FilterParty: party,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list party to participant mappings: %w", err)
+		}
+		if hostsParty(resp, party, participantID) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("party %s did not become active: %w", party, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func hostsParty(resp *model.ListPartyToParticipantResponse, party, participantID string) bool {
+	for _, result := range resp.Results {
+		if result.Item == nil || result.Item.Party != party {
+			continue
+		}
+		if result.Context != nil && result.Context.ValidUntil != nil {
+			continue
+		}
+		for _, p := range result.Item.Participants {
+			if p.ParticipantUID == participantID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// multiTransactionHash combines the hashes of topology transactions into the hash signed for all
+// of them: a Canton hash of the number of hashes followed by each length-prefixed hash, in the
+// order of their hex encoding.
+func multiTransactionHash(transactionHashes [][]byte) []byte {
+	sorted := slices.Clone(transactionHashes)
+	slices.SortFunc(sorted, bytes.Compare)
+
+	data := binary.BigEndian.AppendUint32(nil, uint32(len(sorted)))
+	for _, hash := range sorted {
+		data = binary.BigEndian.AppendUint32(data, uint32(len(hash)))
+		data = append(data, hash...)
+	}
+
+	return crypto.HashWithPurpose(crypto.HashPurposeMultiTopologyTransaction, data)
+}
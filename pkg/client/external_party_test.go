@@ -0,0 +1,159 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/go-daml/pkg/crypto"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/admin"
+	"github.com/smartcontractkit/go-daml/pkg/service/topology"
+)
+
+type fakePartyManagement struct {
+	admin.PartyManagement
+	participantID string
+	partyID       string
+	transactions  []model.SignedTransaction
+	multiHashSigs []model.Signature
+}
+
+func (f *fakePartyManagement) GetParticipantID(context.Context) (string, error) {
+	return f.participantID, nil
+}
+
+func (f *fakePartyManagement) AllocateExternalParty(_ context.Context, _ string, onboardingTransactions []model.SignedTransaction, multiHashSignatures []model.Signature, _ string) (string, error) {
+	f.transactions = onboardingTransactions
+	f.multiHashSigs = multiHashSignatures
+	return f.partyID, nil
+}
+
+type fakeTopologyManagerWrite struct {
+	topology.TopologyManagerWrite
+	proposals []*model.GenerateTransactionProposal
+}
+
+func (f *fakeTopologyManagerWrite) GenerateTransactions(_ context.Context, req *model.GenerateTransactionsRequest) (*model.GenerateTransactionsResponse, error) {
+	f.proposals = req.Proposals
+	resp := &model.GenerateTransactionsResponse{}
+	for i := range req.Proposals {
+		resp.GeneratedTransactions = append(resp.GeneratedTransactions, &model.GeneratedTransaction{
+			SerializedTransaction: []byte(fmt.Sprintf("transaction %d", i)),
+			TransactionHash:       transactionHash(i),
+		})
+	}
+	return resp, nil
+}
+
+type fakeTopologyManagerRead struct {
+	topology.TopologyManagerRead
+	activeAfter int
+	calls       int
+}
+
+func (f *fakeTopologyManagerRead) ListPartyToParticipant(_ context.Context, req *model.ListPartyToParticipantRequest) (*model.ListPartyToParticipantResponse, error) {
+	f.calls++
+	if f.calls < f.activeAfter {
+		return &model.ListPartyToParticipantResponse{}, nil
+	}
+	return &model.ListPartyToParticipantResponse{
+		Results: []*model.PartyToParticipantResult{
+			{
+				Context: &model.BaseResult{Store: req.BaseQuery.Store},
+				Item: &model.PartyToParticipantMapping{
+					Party:        req.FilterParty,
+					Threshold:    1,
+					Participants: []model.HostingParticipant{{ParticipantUID: "participant1::1220ab"}},
+				},
+			},
+		},
+	}, nil
+}
+
+func TestOnboardExternalParty(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer := crypto.NewEd25519Signer(privateKey)
+
+	partyMng := &fakePartyManagement{participantID: "participant1::1220ab", partyID: "alice::" + signer.Fingerprint()}
+	write := &fakeTopologyManagerWrite{}
+	read := &fakeTopologyManagerRead{activeAfter: 3}
+	cl := &DamlBindingClient{PartyMng: partyMng, TopologyManagerWrite: write, TopologyManagerRead: read}
+
+	partyID, err := cl.OnboardExternalParty(t.Context(), "sync::1220cd", "alice", signer, WithOnboardingPollInterval(time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, "alice::"+signer.Fingerprint(), partyID)
+	require.Equal(t, 3, read.calls)
+
+	require.Len(t, write.proposals, 3)
+	for _, proposal := range write.proposals {
+		require.Equal(t, "synchronizer:sync::1220cd", proposal.Store.Value)
+	}
+	delegation := write.proposals[0].Mapping.(*model.NamespaceDelegationMapping)
+	require.Equal(t, signer.Fingerprint(), delegation.Namespace)
+	require.Equal(t, signer.Fingerprint(), delegation.TargetKey.ID)
+	require.Equal(t, []byte(signer.PublicKey().(ed25519.PublicKey)), delegation.TargetKey.Key)
+	require.Equal(t, partyID, write.proposals[1].Mapping.(*model.PartyToKeyMapping).Party)
+	hosting := write.proposals[2].Mapping.(*model.PartyToParticipantMapping)
+	require.Equal(t, partyID, hosting.Party)
+	require.Equal(t, "participant1::1220ab", hosting.Participants[0].ParticipantUID)
+	require.Equal(t, model.ParticipantPermissionConfirmation, hosting.Participants[0].Permission)
+
+	require.Len(t, partyMng.transactions, 3)
+	hashes := make([][]byte, len(partyMng.transactions))
+	for i, tx := range partyMng.transactions {
+		hashes[i] = transactionHash(i)
+		require.Len(t, tx.Signatures, 1)
+		require.Equal(t, signer.Fingerprint(), tx.Signatures[0].SignedBy)
+		require.True(t, ed25519.Verify(signer.PublicKey().(ed25519.PublicKey), hashes[i], tx.Signatures[0].Signature))
+	}
+	require.Len(t, partyMng.multiHashSigs, 1)
+	require.True(t, ed25519.Verify(signer.PublicKey().(ed25519.PublicKey), multiTransactionHash(hashes), partyMng.multiHashSigs[0].Signature))
+}
+
+func TestOnboardExternalPartyTimeout(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	cl := &DamlBindingClient{
+		PartyMng:             &fakePartyManagement{participantID: "participant1::1220ab"},
+		TopologyManagerWrite: &fakeTopologyManagerWrite{},
+		TopologyManagerRead:  &fakeTopologyManagerRead{activeAfter: 1000},
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	_, err = cl.OnboardExternalParty(ctx, "sync::1220cd", "alice", crypto.NewEd25519Signer(privateKey), WithOnboardingPollInterval(time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "did not become active")
+}
+
+func TestMultiTransactionHash(t *testing.T) {
+	first := append([]byte{0x12, 0x20}, make([]byte, 32)...)
+	second := append([]byte{0x12, 0x20}, bytes.Repeat([]byte{0xff}, 32)...)
+
+	// The hash does not depend on the order of the transactions
+	require.Equal(t, multiTransactionHash([][]byte{first, second}), multiTransactionHash([][]byte{second, first}))
+
+	data := []byte{0, 0, 0, 55, 0, 0, 0, 2}
+	for _, hash := range [][]byte{first, second} {
+		data = binary.BigEndian.AppendUint32(data, uint32(len(hash)))
+		data = append(data, hash...)
+	}
+	sum := sha256.Sum256(data)
+	require.Equal(t, append([]byte{0x12, 0x20}, sum[:]...), multiTransactionHash([][]byte{second, first}))
+}
+
+func transactionHash(i int) []byte {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("transaction %d", i)))
+	return append([]byte{0x12, 0x20}, hash[:]...)
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	damlerrors "github.com/smartcontractkit/go-daml/pkg/errors"
+)
+
+// RetryConfig configures the retry interceptors. Requests are retried unchanged, so commands keep
+// their CommandID and SubmissionID and a submission whose first attempt did reach the ledger is
+// deduplicated instead of being executed twice; the retry then fails with DUPLICATE_COMMAND.
+type RetryConfig struct {
+	// MaxAttempts is the number of attempts including the first one, 5 by default
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, which is multiplied by Multiplier up to MaxBackoff
+	// for every subsequent retry. A retry delay sent by the participant takes precedence.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// IsRetryable decides which errors are retried, IsRetryableError by default
+	IsRetryable func(error) bool
+}
+
+// DefaultRetryConfig returns the retry configuration used by WithRetry for unset fields.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:    5,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		IsRetryable:    IsRetryableError,
+	}
+}
+
+// WithRetry retries unary calls and the establishment of server streams that fail with retryable errors.
+func WithRetry(retry RetryConfig) ConfigOption {
+	return func(c *Config) {
+		defaults := DefaultRetryConfig()
+		if retry.MaxAttempts == 0 {
+			retry.MaxAttempts = defaults.MaxAttempts
+		}
+		if retry.InitialBackoff == 0 {
+			retry.InitialBackoff = defaults.InitialBackoff
+		}
+		if retry.MaxBackoff == 0 {
+			retry.MaxBackoff = defaults.MaxBackoff
+		}
+		if retry.Multiplier == 0 {
+			retry.Multiplier = defaults.Multiplier
+		}
+		if retry.IsRetryable == nil {
+			retry.IsRetryable = defaults.IsRetryable
+		}
+		c.Retry = &retry
+	}
+}
+
+// IsRetryableError reports whether a request failed with a Daml error of a retryable category or with
+// retry info. Errors without Daml error details are retried if the participant was unavailable.
+func IsRetryableError(err error) bool {
+	damlErr := damlerrors.AsDamlError(err)
+	if damlErr == nil {
+		return false
+	}
+	if damlErr.Category() != 0 {
+		return damlErr.Retryable
+	}
+	return damlErr.GRPCCode == codes.Unavailable
+}
+
+func (r *RetryConfig) backoff(attempt int, err error) time.Duration {
+	if damlErr := damlerrors.AsDamlError(err); damlErr != nil && damlErr.RetryDelay > 0 {
+		return damlErr.RetryDelay
+	}
+
+	backoff := float64(r.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		backoff *= r.Multiplier
+		if backoff >= float64(r.MaxBackoff) {
+			return r.MaxBackoff
+		}
+	}
+	return time.Duration(backoff)
+}
+
+// wait sleeps before the next attempt after attempt failed with err. It returns false if no
+// further attempt should be made.
+func (r *RetryConfig) wait(ctx context.Context, method string, attempt int, err error) bool {
+	if attempt >= r.MaxAttempts || !r.IsRetryable(err) || ctx.Err() != nil {
+		return false
+	}
+
+	backoff := r.backoff(attempt, err)
+	log.Debug().Err(err).Msgf("%s failed on attempt %d, retrying in %s", method, attempt, backoff)
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (r *RetryConfig) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || !r.wait(ctx, method, attempt, err) {
+				return err
+			}
+		}
+	}
+}
+
+// StreamInterceptor retries server streams that fail before they deliver their first message.
+// Streams that fail later are not retried, as the caller has already seen part of the stream;
+// use ledger.UpdateSubscriber to resume update streams after the last received offset.
+func (r *RetryConfig) StreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if desc.ClientStreams || !desc.ServerStreams {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		for attempt := 1; ; attempt++ {
+			stream, err := streamer(ctx, desc, cc, method, opts...)
+			if err == nil {
+				return &retryingClientStream{
+					ClientStream: stream,
+					retry:        r,
+					ctx:          ctx,
+					attempt:      attempt,
+					newStream: func() (grpc.ClientStream, error) {
+						return streamer(ctx, desc, cc, method, opts...)
+					},
+					method: method,
+				}, nil
+			}
+			if !r.wait(ctx, method, attempt, err) {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryingClientStream replays the request of a server stream on a new stream when
+// receiving the first message fails with a retryable error.
+type retryingClientStream struct {
+	grpc.ClientStream
+	retry     *RetryConfig
+	ctx       context.Context
+	method    string
+	attempt   int
+	newStream func() (grpc.ClientStream, error)
+
+	request   interface{}
+	closed    bool
+	committed bool
+}
+
+func (s *retryingClientStream) SendMsg(m interface{}) error {
+	s.request = m
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *retryingClientStream) CloseSend() error {
+	s.closed = true
+	return s.ClientStream.CloseSend()
+}
+
+func (s *retryingClientStream) RecvMsg(m interface{}) error {
+	for {
+		err := s.ClientStream.RecvMsg(m)
+		if err == nil || err == io.EOF || s.committed {
+			s.committed = true
+			return err
+		}
+
+		for {
+			if !s.retry.wait(s.ctx, s.method, s.attempt, err) {
+				return err
+			}
+			s.attempt++
+			if err = s.reopen(); err == nil {
+				break
+			}
+		}
+	}
+}
+
+func (s *retryingClientStream) reopen() error {
+	stream, err := s.newStream()
+	if err != nil {
+		return err
+	}
+	if s.request != nil {
+		if err := stream.SendMsg(s.request); err != nil {
+			return err
+		}
+	}
+	if s.closed {
+		if err := stream.CloseSend(); err != nil {
+			return err
+		}
+	}
+	s.ClientStream = stream
+	return nil
+}
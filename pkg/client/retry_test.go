@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+)
+
+// scriptedErrors returns the scripted errors in order, and nil once they are exhausted.
+type scriptedErrors struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+func (s *scriptedErrors) next() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.errors) == 0 {
+		return nil
+	}
+	err := s.errors[0]
+	s.errors = s.errors[1:]
+	return err
+}
+
+type retryCommandServer struct {
+	v2.UnimplementedCommandServiceServer
+	scriptedErrors
+	commandIDs    []string
+	submissionIDs []string
+}
+
+func (s *retryCommandServer) SubmitAndWait(_ context.Context, req *v2.SubmitAndWaitRequest) (*v2.SubmitAndWaitResponse, error) {
+	s.mu.Lock()
+	s.commandIDs = append(s.commandIDs, req.Commands.CommandId)
+	s.submissionIDs = append(s.submissionIDs, req.Commands.SubmissionId)
+	s.mu.Unlock()
+
+	if err := s.next(); err != nil {
+		return nil, err
+	}
+	return &v2.SubmitAndWaitResponse{UpdateId: "update-1", CompletionOffset: 42}, nil
+}
+
+type retryStateServer struct {
+	v2.UnimplementedStateServiceServer
+	scriptedErrors
+	calls int
+}
+
+func (s *retryStateServer) GetActiveContracts(req *v2.GetActiveContractsRequest, stream grpc.ServerStreamingServer[v2.GetActiveContractsResponse]) error {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+
+	if err := s.next(); err != nil {
+		return err
+	}
+	for i := int64(1); i <= 3; i++ {
+		if err := stream.Send(&v2.GetActiveContractsResponse{WorkflowId: fmt.Sprintf("workflow-%d-%d", req.ActiveAtOffset, i)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func damlStatus(t *testing.T, code codes.Code, errorCode, category string, retryDelay time.Duration) error {
+	t.Helper()
+
+	st, err := status.New(code, errorCode+"("+category+",abc123): scripted failure").WithDetails(
+		&errdetails.ErrorInfo{Reason: errorCode, Metadata: map[string]string{"category": category}},
+	)
+	require.NoError(t, err)
+	if retryDelay > 0 {
+		st, err = st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)})
+		require.NoError(t, err)
+	}
+	return st.Err()
+}
+
+func startRetryServer(t *testing.T, commands *retryCommandServer, state *retryStateServer) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	v2.RegisterCommandServiceServer(server, commands)
+	v2.RegisterStateServiceServer(server, state)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func connectWithRetry(t *testing.T, address string, retry RetryConfig) *grpc.ClientConn {
+	t.Helper()
+
+	client := NewClient(NewConfig(WithAddress(address), WithToken("token"), WithRetry(retry)))
+	conn, err := client.Connect(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return conn.GRPCConn()
+}
+
+func TestRetryUnaryKeepsCommandIDs(t *testing.T) {
+	commands := &retryCommandServer{scriptedErrors: scriptedErrors{errors: []error{
+		status.Error(codes.Unavailable, "participant restarting"),
+		damlStatus(t, codes.Aborted, "SUBMISSION_ALREADY_IN_FLIGHT", "2", 30*time.Millisecond),
+	}}}
+	conn := connectWithRetry(t, startRetryServer(t, commands, &retryStateServer{}), RetryConfig{InitialBackoff: time.Millisecond})
+
+	start := time.Now()
+	resp, err := ledger.NewCommandServiceClient(conn).SubmitAndWait(context.Background(), &model.SubmitAndWaitRequest{
+		Commands: &model.Commands{
+			UserID:       "app-provider",
+			CommandID:    "cmd-1",
+			SubmissionID: "sub-1",
+			ActAs:        []string{"alice"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(42), resp.CompletionOffset)
+
+	// The retry delay sent by the participant is honoured
+	require.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	require.Equal(t, []string{"cmd-1", "cmd-1", "cmd-1"}, commands.commandIDs)
+	require.Equal(t, []string{"sub-1", "sub-1", "sub-1"}, commands.submissionIDs)
+}
+
+func TestRetryUnaryNonRetryable(t *testing.T) {
+	commands := &retryCommandServer{scriptedErrors: scriptedErrors{errors: []error{
+		damlStatus(t, codes.InvalidArgument, "INVALID_ARGUMENT", "8", 0),
+	}}}
+	conn := connectWithRetry(t, startRetryServer(t, commands, &retryStateServer{}), RetryConfig{InitialBackoff: time.Millisecond})
+
+	_, err := v2.NewCommandServiceClient(conn).SubmitAndWait(context.Background(), &v2.SubmitAndWaitRequest{Commands: &v2.Commands{CommandId: "cmd-1"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Len(t, commands.commandIDs, 1)
+}
+
+func TestRetryUnaryMaxAttempts(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "participant restarting")
+	commands := &retryCommandServer{scriptedErrors: scriptedErrors{errors: []error{unavailable, unavailable, unavailable, unavailable}}}
+	conn := connectWithRetry(t, startRetryServer(t, commands, &retryStateServer{}), RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	_, err := v2.NewCommandServiceClient(conn).SubmitAndWait(context.Background(), &v2.SubmitAndWaitRequest{Commands: &v2.Commands{CommandId: "cmd-1"}})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Len(t, commands.commandIDs, 3)
+}
+
+func TestRetryStream(t *testing.T) {
+	state := &retryStateServer{scriptedErrors: scriptedErrors{errors: []error{
+		damlStatus(t, codes.Unavailable, "SERVICE_NOT_RUNNING", "1", 0),
+		status.Error(codes.Unavailable, "participant restarting"),
+	}}}
+	conn := connectWithRetry(t, startRetryServer(t, &retryCommandServer{}, state), RetryConfig{InitialBackoff: time.Millisecond})
+
+	responses, errs := ledger.NewStateServiceClient(conn).GetActiveContracts(context.Background(), &model.GetActiveContractsRequest{ActiveAtOffset: 10})
+	var workflowIDs []string
+	for resp := range responses {
+		workflowIDs = append(workflowIDs, resp.WorkflowID)
+	}
+	require.NoError(t, <-errs)
+	require.Equal(t, []string{"workflow-10-1", "workflow-10-2", "workflow-10-3"}, workflowIDs)
+	require.Equal(t, 3, state.calls)
+}
@@ -1,8 +1,11 @@
 package codec
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"math/big"
 	"reflect"
@@ -75,12 +78,65 @@ func (codec *JsonCodec) Marshal(value interface{}) ([]byte, error) {
 
 // Unmarshal converts JSON bytes back to a DAML structure following transcode patterns
 func (codec *JsonCodec) Unmarshal(data []byte, target interface{}) error {
+	// Numbers are decoded as json.Number so that INT64 values beyond 2^53 survive
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
 	var intermediate interface{}
-	if err := json.Unmarshal(data, &intermediate); err != nil {
+	if err := decoder.Decode(&intermediate); err != nil {
 		return fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to unmarshal JSON: unexpected data after top-level value")
+	}
+
+	return codec.fromDynamicValue(numbersFromJSON(intermediate), target)
+}
 
-	return codec.fromDynamicValue(intermediate, target)
+// numbersFromJSON replaces the json.Number values in value with int64 if they are integers
+// and float64 otherwise.
+func numbersFromJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = numbersFromJSON(elem)
+		}
+		return v
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = numbersFromJSON(elem)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// untypedValue returns value with its integers as INT64, for targets that carry no DAML type,
+// such as fields of type variables.
+func untypedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return types.INT64(v)
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = untypedValue(elem)
+		}
+		return v
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = untypedValue(elem)
+		}
+		return v
+	default:
+		return value
+	}
 }
 
 // toDynamicValue converts Go values to JSON-compatible values following transcode codec patterns
@@ -670,7 +726,7 @@ func (codec *JsonCodec) assignValue(jsonValue interface{}, target reflect.Value)
 
 	case reflect.Interface:
 		if target.Type().NumMethod() == 0 {
-			target.Set(reflect.ValueOf(jsonValue))
+			target.Set(reflect.ValueOf(untypedValue(jsonValue)))
 			return nil
 		}
 		return fmt.Errorf("cannot assign to non-empty interface type: %v", target.Type())
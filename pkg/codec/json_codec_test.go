@@ -536,6 +536,27 @@ func TestJsonCodec_Unmarshal_RecordWithNilOptional(t *testing.T) {
 	assert.Nil(t, result.Optional)
 }
 
+func TestJsonCodec_Unmarshal_LargeInt64(t *testing.T) {
+	codec := NewJsonCodec()
+
+	type pair struct {
+		Left  INT64 `json:"left"`
+		Right any   `json:"right"`
+	}
+
+	jsonData := `{"left": 9007199254740993, "right": {"nested": [9007199254740993, 1.5]}}`
+
+	var result pair
+	err := codec.Unmarshal([]byte(jsonData), &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, INT64(9007199254740993), result.Left)
+	assert.Equal(t, map[string]any{"nested": []any{INT64(9007199254740993), 1.5}}, result.Right)
+
+	err = codec.Unmarshal([]byte(`{"left": 1} {}`), &result)
+	require.Error(t, err)
+}
+
 func TestJsonCodec_RoundTrip_Marshal_Unmarshal(t *testing.T) {
 	codec := NewJsonCodec()
 
@@ -0,0 +1,208 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// HashPurpose is the purpose that Canton prefixes hashed data with, so that hashes computed for
+// one purpose cannot be used for another.
+type HashPurpose uint32
+
+const (
+	// HashPurposePublicKeyFingerprint is the hash purpose of public key fingerprints.
+	HashPurposePublicKeyFingerprint HashPurpose = 12
+	// HashPurposeMultiTopologyTransaction is the hash purpose of the hash combining the hashes of
+	// several topology transactions, which is signed once for all of them.
+	HashPurposeMultiTopologyTransaction HashPurpose = 55
+)
+
+// HashWithPurpose computes the Canton hash of data for purpose: the SHA-256 multihash of data
+// prefixed with the purpose.
+func HashWithPurpose(purpose HashPurpose, data []byte) []byte {
+	h := sha256.New()
+	h.Write(binary.BigEndian.AppendUint32(nil, uint32(purpose)))
+	h.Write(data)
+	// SHA-256 multihash: hash function code and digest length
+	return h.Sum([]byte{0x12, 0x20})
+}
+
+// Fingerprint computes the Canton fingerprint of an Ed25519 or ECDSA public key, which identifies
+// the key in topology transactions and in the SignedBy of signatures. Fingerprints of Ed25519 keys
+// are computed over the raw key and those of ECDSA keys over the X.509 SubjectPublicKeyInfo.
+func Fingerprint(publicKey crypto.PublicKey) (string, error) {
+	var key []byte
+	switch k := publicKey.(type) {
+	case ed25519.PublicKey:
+		key = k
+	case *ecdsa.PublicKey:
+		spki, err := MarshalPublicKey(k, model.CryptoKeyFormatDERX509SubjectPublicKeyInfo)
+		if err != nil {
+			return "", err
+		}
+		key = spki
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return hex.EncodeToString(HashWithPurpose(HashPurposePublicKeyFingerprint, key)), nil
+}
+
+// MarshalPublicKey encodes an Ed25519 or ECDSA public key in format. The raw format of ECDSA keys
+// is the uncompressed curve point; the DER format is the X.509 SubjectPublicKeyInfo.
+func MarshalPublicKey(publicKey crypto.PublicKey, format model.CryptoKeyFormat) ([]byte, error) {
+	switch format {
+	case model.CryptoKeyFormatRaw:
+		switch k := publicKey.(type) {
+		case ed25519.PublicKey:
+			return []byte(k), nil
+		case *ecdsa.PublicKey:
+			ecdhKey, err := k.ECDH()
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode public key: %w", err)
+			}
+			return ecdhKey.Bytes(), nil
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+		}
+	case model.CryptoKeyFormatDER, model.CryptoKeyFormatDERX509SubjectPublicKeyInfo:
+		spki, err := x509.MarshalPKIXPublicKey(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode public key: %w", err)
+		}
+		return spki, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key format %d", format)
+	}
+}
+
+// ParsePublicKey decodes a public key encoded in format. spec is required to decode raw keys,
+// and otherwise checked against the decoded key if set.
+func ParsePublicKey(data []byte, format model.CryptoKeyFormat, spec model.SigningKeySpec) (crypto.PublicKey, error) {
+	var publicKey crypto.PublicKey
+	switch format {
+	case model.CryptoKeyFormatRaw:
+		switch spec {
+		case model.SigningKeySpecCurve25519:
+			if len(data) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid Ed25519 public key length %d", len(data))
+			}
+			publicKey = ed25519.PublicKey(data)
+		case model.SigningKeySpecP256, model.SigningKeySpecP384:
+			curve, ecdhCurve := elliptic.P256(), ecdh.P256()
+			if spec == model.SigningKeySpecP384 {
+				curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+			}
+			// Validates that the point is on the curve
+			if _, err := ecdhCurve.NewPublicKey(data); err != nil {
+				return nil, fmt.Errorf("invalid ECDSA public key: %w", err)
+			}
+			// Uncompressed point: 0x04 || X || Y
+			size := (len(data) - 1) / 2
+			publicKey = &ecdsa.PublicKey{
+				Curve: curve,
+				X:     new(big.Int).SetBytes(data[1 : 1+size]),
+				Y:     new(big.Int).SetBytes(data[1+size:]),
+			}
+		default:
+			return nil, fmt.Errorf("unsupported signing key spec %d for raw keys", spec)
+		}
+	case model.CryptoKeyFormatDER, model.CryptoKeyFormatDERX509SubjectPublicKeyInfo:
+		parsed, err := x509.ParsePKIXPublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		publicKey = parsed
+	default:
+		return nil, fmt.Errorf("unsupported public key format %d", format)
+	}
+
+	keySpec, _, err := keySpecAndScheme(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if spec != model.SigningKeySpecUnspecified && spec != keySpec {
+		return nil, fmt.Errorf("public key has spec %d, expected %d", keySpec, spec)
+	}
+	return publicKey, nil
+}
+
+// NewPublicKey builds the topology representation of an Ed25519 or ECDSA public key with the
+// given usages. Ed25519 keys are encoded raw and ECDSA keys as X.509 SubjectPublicKeyInfo.
+func NewPublicKey(publicKey crypto.PublicKey, usages ...model.SigningKeyUsage) (*model.PublicKey, error) {
+	spec, scheme, err := keySpecAndScheme(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	format := model.CryptoKeyFormatDERX509SubjectPublicKeyInfo
+	if spec == model.SigningKeySpecCurve25519 {
+		format = model.CryptoKeyFormatRaw
+	}
+	key, err := MarshalPublicKey(publicKey, format)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := Fingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]int32, len(usages))
+	for i, u := range usages {
+		usage[i] = int32(u)
+	}
+
+	return &model.PublicKey{
+		Format:  int32(format),
+		Key:     key,
+		ID:      fingerprint,
+		Scheme:  int32(scheme),
+		KeySpec: int32(spec),
+		Usage:   usage,
+	}, nil
+}
+
+// PublicKeyFromModel decodes the key of a topology public key.
+func PublicKeyFromModel(publicKey *model.PublicKey) (crypto.PublicKey, error) {
+	spec := model.SigningKeySpec(publicKey.KeySpec)
+	if spec == model.SigningKeySpecUnspecified {
+		switch model.SigningKeyScheme(publicKey.Scheme) {
+		case model.SigningKeySchemeED25519:
+			spec = model.SigningKeySpecCurve25519
+		case model.SigningKeySchemeECDSAP256:
+			spec = model.SigningKeySpecP256
+		case model.SigningKeySchemeECDSAP384:
+			spec = model.SigningKeySpecP384
+		}
+	}
+	return ParsePublicKey(publicKey.Key, model.CryptoKeyFormat(publicKey.Format), spec)
+}
+
+func keySpecAndScheme(publicKey crypto.PublicKey) (model.SigningKeySpec, model.SigningKeyScheme, error) {
+	switch k := publicKey.(type) {
+	case ed25519.PublicKey:
+		return model.SigningKeySpecCurve25519, model.SigningKeySchemeED25519, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return model.SigningKeySpecP256, model.SigningKeySchemeECDSAP256, nil
+		case elliptic.P384():
+			return model.SigningKeySpecP384, model.SigningKeySchemeECDSAP384, nil
+		}
+		return 0, 0, fmt.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+	default:
+		return 0, 0, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
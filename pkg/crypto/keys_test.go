@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+func generateKeys(t *testing.T) map[model.SigningKeySpec]crypto.PublicKey {
+	t.Helper()
+
+	ed25519Key, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	return map[model.SigningKeySpec]crypto.PublicKey{
+		model.SigningKeySpecCurve25519: ed25519Key,
+		model.SigningKeySpecP256:       &p256Key.PublicKey,
+		model.SigningKeySpecP384:       &p384Key.PublicKey,
+	}
+}
+
+func TestMarshalParsePublicKey(t *testing.T) {
+	formats := []model.CryptoKeyFormat{
+		model.CryptoKeyFormatRaw,
+		model.CryptoKeyFormatDER,
+		model.CryptoKeyFormatDERX509SubjectPublicKeyInfo,
+	}
+
+	for spec, publicKey := range generateKeys(t) {
+		for _, format := range formats {
+			data, err := MarshalPublicKey(publicKey, format)
+			require.NoError(t, err)
+
+			parsed, err := ParsePublicKey(data, format, spec)
+			require.NoError(t, err)
+			require.True(t, publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(parsed), "spec %d format %d", spec, format)
+		}
+
+		// Keys in DER format are self-describing
+		data, err := MarshalPublicKey(publicKey, model.CryptoKeyFormatDERX509SubjectPublicKeyInfo)
+		require.NoError(t, err)
+		_, err = ParsePublicKey(data, model.CryptoKeyFormatDERX509SubjectPublicKeyInfo, model.SigningKeySpecUnspecified)
+		require.NoError(t, err)
+	}
+
+	raw, err := MarshalPublicKey(generateKeys(t)[model.SigningKeySpecP256], model.CryptoKeyFormatRaw)
+	require.NoError(t, err)
+	require.Len(t, raw, 65)
+	_, err = ParsePublicKey(raw, model.CryptoKeyFormatRaw, model.SigningKeySpecP384)
+	require.ErrorContains(t, err, "invalid ECDSA public key")
+	_, err = ParsePublicKey(raw, model.CryptoKeyFormatRaw, model.SigningKeySpecUnspecified)
+	require.ErrorContains(t, err, "unsupported signing key spec")
+
+	der, err := MarshalPublicKey(generateKeys(t)[model.SigningKeySpecP256], model.CryptoKeyFormatDER)
+	require.NoError(t, err)
+	_, err = ParsePublicKey(der, model.CryptoKeyFormatDER, model.SigningKeySpecCurve25519)
+	require.ErrorContains(t, err, "expected")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = Fingerprint(&rsaKey.PublicKey)
+	require.ErrorContains(t, err, "unsupported public key type")
+}
+
+func TestNewPublicKey(t *testing.T) {
+	keys := generateKeys(t)
+
+	tests := []struct {
+		spec   model.SigningKeySpec
+		scheme model.SigningKeyScheme
+		format model.CryptoKeyFormat
+	}{
+		{model.SigningKeySpecCurve25519, model.SigningKeySchemeED25519, model.CryptoKeyFormatRaw},
+		{model.SigningKeySpecP256, model.SigningKeySchemeECDSAP256, model.CryptoKeyFormatDERX509SubjectPublicKeyInfo},
+		{model.SigningKeySpecP384, model.SigningKeySchemeECDSAP384, model.CryptoKeyFormatDERX509SubjectPublicKeyInfo},
+	}
+
+	for _, tt := range tests {
+		publicKey, err := NewPublicKey(keys[tt.spec], model.SigningKeyUsageNamespace, model.SigningKeyUsageProtocol)
+		require.NoError(t, err)
+		require.Equal(t, int32(tt.format), publicKey.Format)
+		require.Equal(t, int32(tt.scheme), publicKey.Scheme)
+		require.Equal(t, int32(tt.spec), publicKey.KeySpec)
+		require.Equal(t, []int32{1, 4}, publicKey.Usage)
+
+		fingerprint, err := Fingerprint(keys[tt.spec])
+		require.NoError(t, err)
+		require.Equal(t, fingerprint, publicKey.ID)
+
+		decoded, err := PublicKeyFromModel(publicKey)
+		require.NoError(t, err)
+		require.True(t, keys[tt.spec].(interface{ Equal(crypto.PublicKey) bool }).Equal(decoded))
+
+		// Keys without a spec are decoded by their scheme
+		publicKey.KeySpec = 0
+		_, err = PublicKeyFromModel(publicKey)
+		require.NoError(t, err)
+	}
+}
+
+func TestHashWithPurpose(t *testing.T) {
+	hash := HashWithPurpose(HashPurposeMultiTopologyTransaction, []byte("data"))
+
+	sum := sha256.Sum256([]byte("\x00\x00\x00\x37data"))
+	require.Equal(t, append([]byte{0x12, 0x20}, sum[:]...), hash)
+	require.NotEqual(t, hash, HashWithPurpose(HashPurposePublicKeyFingerprint, []byte("data")))
+}
@@ -0,0 +1,117 @@
+package crypto
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// Signer signs on behalf of an external party, e.g. the prepared transaction hashes of
+// interactive submissions. Implementations backed by a KMS or HSM only need to return
+// signatures in the format Canton expects for their signing algorithm.
+type Signer interface {
+	// Fingerprint returns the Canton fingerprint of the public key, which signatures are SignedBy
+	Fingerprint() string
+	PublicKey() crypto.PublicKey
+	Sign(ctx context.Context, data []byte) (*model.Signature, error)
+}
+
+// Ed25519Signer signs with an Ed25519 key, producing signatures in the concatenated R || S format.
+type Ed25519Signer struct {
+	privateKey  ed25519.PrivateKey
+	fingerprint string
+}
+
+func NewEd25519Signer(privateKey ed25519.PrivateKey) *Ed25519Signer {
+	// Fingerprints of Ed25519 keys cannot fail
+	fingerprint, _ := Fingerprint(privateKey.Public())
+	return &Ed25519Signer{
+		privateKey:  privateKey,
+		fingerprint: fingerprint,
+	}
+}
+
+func (s *Ed25519Signer) Fingerprint() string {
+	return s.fingerprint
+}
+
+func (s *Ed25519Signer) PublicKey() crypto.PublicKey {
+	return s.privateKey.Public()
+}
+
+func (s *Ed25519Signer) Sign(_ context.Context, data []byte) (*model.Signature, error) {
+	return &model.Signature{
+		Format:               model.SignatureFormatConcat,
+		Signature:            ed25519.Sign(s.privateKey, data),
+		SignedBy:             s.fingerprint,
+		SigningAlgorithmSpec: model.SigningAlgorithmSpecED25519,
+	}, nil
+}
+
+// ECDSASigner signs with an ECDSA P-256 or P-384 key, hashing the data with SHA-256 or SHA-384
+// respectively and producing DER encoded signatures.
+type ECDSASigner struct {
+	privateKey  *ecdsa.PrivateKey
+	fingerprint string
+	hash        crypto.Hash
+	algorithm   model.SigningAlgorithmSpec
+}
+
+func NewECDSASigner(privateKey *ecdsa.PrivateKey) (*ECDSASigner, error) {
+	s := &ECDSASigner{privateKey: privateKey}
+	switch privateKey.Curve {
+	case elliptic.P256():
+		s.hash, s.algorithm = crypto.SHA256, model.SigningAlgorithmSpecECDSASHA256
+	case elliptic.P384():
+		s.hash, s.algorithm = crypto.SHA384, model.SigningAlgorithmSpecECDSASHA384
+	default:
+		return nil, fmt.Errorf("unsupported ECDSA curve %s", privateKey.Curve.Params().Name)
+	}
+
+	fingerprint, err := Fingerprint(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	s.fingerprint = fingerprint
+
+	return s, nil
+}
+
+func (s *ECDSASigner) Fingerprint() string {
+	return s.fingerprint
+}
+
+func (s *ECDSASigner) PublicKey() crypto.PublicKey {
+	return &s.privateKey.PublicKey
+}
+
+func (s *ECDSASigner) Sign(_ context.Context, data []byte) (*model.Signature, error) {
+	var digest []byte
+	if s.hash == crypto.SHA256 {
+		sum := sha256.Sum256(data)
+		digest = sum[:]
+	} else {
+		sum := sha512.Sum384(data)
+		digest = sum[:]
+	}
+
+	signature, err := ecdsa.SignASN1(rand.Reader, s.privateKey, digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	return &model.Signature{
+		Format:               model.SignatureFormatDER,
+		Signature:            signature,
+		SignedBy:             s.fingerprint,
+		SigningAlgorithmSpec: s.algorithm,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+func TestEd25519Signer(t *testing.T) {
+	seed, err := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	require.NoError(t, err)
+	signer := NewEd25519Signer(ed25519.NewKeyFromSeed(seed))
+
+	// Fingerprints are SHA-256 multihashes of the fingerprint hash purpose and the raw key
+	require.Equal(t, "1220"+hex.EncodeToString(sha256Sum(append([]byte{0, 0, 0, 12}, signer.PublicKey().(ed25519.PublicKey)...))), signer.Fingerprint())
+	require.Len(t, signer.Fingerprint(), 68)
+
+	hash := sha256Sum([]byte("prepared transaction"))
+	signature, err := signer.Sign(context.Background(), hash)
+	require.NoError(t, err)
+	require.Equal(t, model.SignatureFormatConcat, signature.Format)
+	require.Equal(t, model.SigningAlgorithmSpecED25519, signature.SigningAlgorithmSpec)
+	require.Equal(t, signer.Fingerprint(), signature.SignedBy)
+	require.True(t, ed25519.Verify(signer.PublicKey().(ed25519.PublicKey), hash, signature.Signature))
+}
+
+func TestECDSASigner(t *testing.T) {
+	tests := []struct {
+		curve     elliptic.Curve
+		algorithm model.SigningAlgorithmSpec
+		digest    func([]byte) []byte
+	}{
+		{elliptic.P256(), model.SigningAlgorithmSpecECDSASHA256, sha256Sum},
+		{elliptic.P384(), model.SigningAlgorithmSpecECDSASHA384, func(data []byte) []byte {
+			sum := sha512.Sum384(data)
+			return sum[:]
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.curve.Params().Name, func(t *testing.T) {
+			privateKey, err := ecdsa.GenerateKey(tt.curve, rand.Reader)
+			require.NoError(t, err)
+			signer, err := NewECDSASigner(privateKey)
+			require.NoError(t, err)
+
+			spki, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+			require.NoError(t, err)
+			require.Equal(t, "1220"+hex.EncodeToString(sha256Sum(append([]byte{0, 0, 0, 12}, spki...))), signer.Fingerprint())
+
+			hash := sha256Sum([]byte("prepared transaction"))
+			signature, err := signer.Sign(context.Background(), hash)
+			require.NoError(t, err)
+			require.Equal(t, model.SignatureFormatDER, signature.Format)
+			require.Equal(t, tt.algorithm, signature.SigningAlgorithmSpec)
+			require.Equal(t, signer.Fingerprint(), signature.SignedBy)
+			require.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, tt.digest(hash), signature.Signature))
+		})
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+	_, err = NewECDSASigner(privateKey)
+	require.ErrorContains(t, err, "unsupported ECDSA curve P-224")
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
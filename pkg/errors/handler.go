@@ -1,64 +1,224 @@
 package errors
 
 import (
+	stderrors "errors"
+	"fmt"
 	"regexp"
 	"strconv"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 const genericErr = "DAML_GENERIC_ERROR_CODE"
 
+// ResourceContractID is the resource type of contract IDs in the ResourceInfo details of an error.
+const ResourceContractID = "CONTRACT_ID"
+
+var damlErrorRegex = regexp.MustCompile(`^([A-Z_]+)\((\d+),([^)]+)\):\s*(.*)$`)
+
+// Sentinels for common error codes, to be matched with errors.Is against the result of AsDamlError.
+var (
+	ErrContractNotFound              = &DamlError{ErrorCode: "CONTRACT_NOT_FOUND"}
+	ErrDuplicateCommand              = &DamlError{ErrorCode: "DUPLICATE_COMMAND"}
+	ErrInconsistentContractKey       = &DamlError{ErrorCode: "INCONSISTENT_CONTRACT_KEY"}
+	ErrDuplicateContractKey          = &DamlError{ErrorCode: "DUPLICATE_CONTRACT_KEY"}
+	ErrSubmissionAlreadyInFlight     = &DamlError{ErrorCode: "SUBMISSION_ALREADY_IN_FLIGHT"}
+	ErrCommandPreprocessingFailed    = &DamlError{ErrorCode: "COMMAND_PREPROCESSING_FAILED"}
+	ErrPackageNamesNotFound          = &DamlError{ErrorCode: "PACKAGE_NAMES_NOT_FOUND"}
+	ErrParticipantPrunedDataAccessed = &DamlError{ErrorCode: "PARTICIPANT_PRUNED_DATA_ACCESSED"}
+)
+
+// ErrorCategory is the category of a Daml error, which determines how clients should react to it.
+type ErrorCategory int
+
+const (
+	CategoryTransientServerFailure                          ErrorCategory = 1
+	CategoryContentionOnSharedResources                     ErrorCategory = 2
+	CategoryDeadlineExceededRequestStateUnknown             ErrorCategory = 3
+	CategorySystemInternalAssumptionViolated                ErrorCategory = 4
+	CategoryMaliciousOrFaultyBehaviour                      ErrorCategory = 5
+	CategoryAuthInterceptorInvalidAuthenticationCredentials ErrorCategory = 6
+	CategoryInsufficientPermission                          ErrorCategory = 7
+	CategoryInvalidIndependentOfSystemState                 ErrorCategory = 8
+	CategoryInvalidGivenCurrentSystemStateOther             ErrorCategory = 9
+	CategoryInvalidGivenCurrentSystemStateResourceExists    ErrorCategory = 10
+	CategoryInvalidGivenCurrentSystemStateResourceMissing   ErrorCategory = 11
+	CategoryInvalidGivenCurrentSystemStateSeekAfterEnd      ErrorCategory = 12
+	CategoryBackgroundProcessDegradationWarning             ErrorCategory = 13
+	CategoryInternalUnsupportedOperation                    ErrorCategory = 14
+)
+
+var categoryNames = map[ErrorCategory]string{
+	CategoryTransientServerFailure:                          "TransientServerFailure",
+	CategoryContentionOnSharedResources:                     "ContentionOnSharedResources",
+	CategoryDeadlineExceededRequestStateUnknown:             "DeadlineExceededRequestStateUnknown",
+	CategorySystemInternalAssumptionViolated:                "SystemInternalAssumptionViolated",
+	CategoryMaliciousOrFaultyBehaviour:                      "MaliciousOrFaultyBehaviour",
+	CategoryAuthInterceptorInvalidAuthenticationCredentials: "AuthInterceptorInvalidAuthenticationCredentials",
+	CategoryInsufficientPermission:                          "InsufficientPermission",
+	CategoryInvalidIndependentOfSystemState:                 "InvalidIndependentOfSystemState",
+	CategoryInvalidGivenCurrentSystemStateOther:             "InvalidGivenCurrentSystemStateOther",
+	CategoryInvalidGivenCurrentSystemStateResourceExists:    "InvalidGivenCurrentSystemStateResourceExists",
+	CategoryInvalidGivenCurrentSystemStateResourceMissing:   "InvalidGivenCurrentSystemStateResourceMissing",
+	CategoryInvalidGivenCurrentSystemStateSeekAfterEnd:      "InvalidGivenCurrentSystemStateSeekAfterEnd",
+	CategoryBackgroundProcessDegradationWarning:             "BackgroundProcessDegradationWarning",
+	CategoryInternalUnsupportedOperation:                    "InternalUnsupportedOperation",
+}
+
+func (c ErrorCategory) String() string {
+	if name, ok := categoryNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("ErrorCategory(%d)", int(c))
+}
+
+// Retryable reports whether requests failing with errors of the category may succeed when retried.
+func (c ErrorCategory) Retryable() bool {
+	switch c {
+	case CategoryTransientServerFailure, CategoryContentionOnSharedResources, CategoryDeadlineExceededRequestStateUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// Resource is a resource an error refers to, e.g. the ID of a contract that could not be found.
+type Resource struct {
+	Type string
+	Name string
+}
+
 type DamlError struct {
 	ErrorCode     string
 	CategoryID    int
 	CorrelationID interface{}
 	Message       string
+	// GRPCCode is the status code of the gRPC error, codes.Unknown for other errors
+	GRPCCode codes.Code
+	// Retryable is set for errors with retry info or of a retryable category
+	Retryable bool
+	// RetryDelay is the delay the participant asks for before retrying, if any
+	RetryDelay time.Duration
+	Resources  []Resource
+	Metadata   map[string]string
+
+	cause error
 }
 
+// AsDamlError returns the Daml error details of err, read from the ErrorInfo, RetryInfo, RequestInfo
+// and ResourceInfo details of the gRPC status and the CODE(category,correlationID) message prefix.
+// Errors that are not Daml errors have the generic error code and a negative CategoryID. It returns nil for a nil err.
 func AsDamlError(err error) *DamlError {
 	if err == nil {
-		return &DamlError{
-			ErrorCode:  genericErr,
-			CategoryID: -1,
-			Message:    err.Error(),
-		}
+		return nil
 	}
 
-	grpcStatus, ok := status.FromError(err)
-	if !ok {
+	// The status of a wrapped gRPC error, unlike status.FromError which prefixes its message with the wrapping
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !stderrors.As(err, &grpcErr) {
 		return &DamlError{
 			ErrorCode:  genericErr,
 			CategoryID: -2,
 			Message:    err.Error(),
+			GRPCCode:   codes.Unknown,
+			cause:      err,
 		}
 	}
 
-	damlErrorRegex := regexp.MustCompile(`^([A-Z_]+)\((\d+),([^)]+)\):\s*(.*)$`)
-	message := grpcStatus.Message()
+	grpcStatus := grpcErr.GRPCStatus()
+	damlErr := &DamlError{
+		ErrorCode:  genericErr,
+		CategoryID: -5,
+		Message:    err.Error(),
+		GRPCCode:   grpcStatus.Code(),
+		cause:      err,
+	}
 
-	matches := damlErrorRegex.FindStringSubmatch(message)
+	matches := damlErrorRegex.FindStringSubmatch(grpcStatus.Message())
 	if len(matches) == 5 {
 		categoryID, err := strconv.Atoi(matches[2])
 		if err != nil {
-			return &DamlError{
-				ErrorCode:  genericErr,
-				CategoryID: -3,
-				Message:    err.Error(),
+			damlErr.CategoryID = -3
+			damlErr.Message = err.Error()
+			return damlErr
+		}
+
+		damlErr.ErrorCode = matches[1]
+		damlErr.CategoryID = categoryID
+		damlErr.CorrelationID = matches[3]
+		damlErr.Message = matches[4]
+	}
+
+	damlErr.readDetails(grpcStatus)
+	damlErr.Retryable = damlErr.Retryable || damlErr.Category().Retryable()
+
+	return damlErr
+}
+
+func (e *DamlError) readDetails(grpcStatus *status.Status) {
+	for _, detail := range grpcStatus.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if e.ErrorCode == genericErr && d.Reason != "" {
+				e.ErrorCode = d.Reason
+				e.Message = grpcStatus.Message()
 			}
+			if len(d.Metadata) > 0 {
+				e.Metadata = d.Metadata
+			}
+			if category, err := strconv.Atoi(d.Metadata["category"]); err == nil {
+				e.CategoryID = category
+			}
+		case *errdetails.RetryInfo:
+			e.Retryable = true
+			if d.RetryDelay != nil {
+				e.RetryDelay = d.RetryDelay.AsDuration()
+			}
+		case *errdetails.RequestInfo:
+			if d.RequestId != "" {
+				e.CorrelationID = d.RequestId
+			}
+		case *errdetails.ResourceInfo:
+			e.Resources = append(e.Resources, Resource{Type: d.ResourceType, Name: d.ResourceName})
 		}
+	}
+}
 
-		return &DamlError{
-			ErrorCode:     matches[1],
-			CategoryID:    categoryID,
-			CorrelationID: matches[3],
-			Message:       matches[4],
+// Category returns the error category, or 0 for errors that are not Daml errors.
+func (e *DamlError) Category() ErrorCategory {
+	if e.CategoryID < 0 {
+		return 0
+	}
+	return ErrorCategory(e.CategoryID)
+}
+
+// ContractIDs returns the IDs of the contracts the error refers to.
+func (e *DamlError) ContractIDs() []string {
+	var contractIDs []string
+	for _, resource := range e.Resources {
+		if resource.Type == ResourceContractID {
+			contractIDs = append(contractIDs, resource.Name)
 		}
 	}
+	return contractIDs
+}
 
-	return &DamlError{
-		ErrorCode:  genericErr,
-		CategoryID: -5,
-		Message:    err.Error(),
+func (e *DamlError) Error() string {
+	if e.cause != nil {
+		return e.cause.Error()
 	}
+	return e.ErrorCode
+}
+
+func (e *DamlError) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is a DamlError with the same error code, so that errors.Is matches the sentinels.
+func (e *DamlError) Is(target error) bool {
+	t, ok := target.(*DamlError)
+	return ok && t.ErrorCode != genericErr && t.ErrorCode == e.ErrorCode
 }
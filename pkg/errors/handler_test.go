@@ -2,10 +2,15 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestAsDamlError(t *testing.T) {
@@ -80,12 +85,60 @@ func TestAsDamlError(t *testing.T) {
 	}
 }
 
-func TestAsDamlErrorPanicWithNil(t *testing.T) {
-	defer func() {
-		if r := recover(); r == nil {
-			t.Error("AsDamlError should panic when passed nil error")
-		}
-	}()
+func TestAsDamlErrorNil(t *testing.T) {
+	require.Nil(t, AsDamlError(nil))
+}
+
+func TestAsDamlErrorDetails(t *testing.T) {
+	st, err := status.New(codes.NotFound, "CONTRACT_NOT_FOUND(11,3f2a9c1d): Contract could not be found with id 00abc").WithDetails(
+		&errdetails.ErrorInfo{Reason: "CONTRACT_NOT_FOUND", Metadata: map[string]string{"category": "11", "definite_answer": "false"}},
+		&errdetails.RequestInfo{RequestId: "3f2a9c1d"},
+		&errdetails.ResourceInfo{ResourceType: ResourceContractID, ResourceName: "00abc"},
+	)
+	require.NoError(t, err)
+
+	damlErr := AsDamlError(st.Err())
+	require.Equal(t, "CONTRACT_NOT_FOUND", damlErr.ErrorCode)
+	require.Equal(t, CategoryInvalidGivenCurrentSystemStateResourceMissing, damlErr.Category())
+	require.Equal(t, "3f2a9c1d", damlErr.CorrelationID)
+	require.Equal(t, "Contract could not be found with id 00abc", damlErr.Message)
+	require.Equal(t, codes.NotFound, damlErr.GRPCCode)
+	require.Equal(t, []string{"00abc"}, damlErr.ContractIDs())
+	require.Equal(t, "false", damlErr.Metadata["definite_answer"])
+	require.False(t, damlErr.Retryable)
+
+	require.ErrorIs(t, damlErr, ErrContractNotFound)
+	require.NotErrorIs(t, damlErr, ErrDuplicateCommand)
+	require.Equal(t, codes.NotFound, status.Code(errors.Unwrap(damlErr)))
+	require.Equal(t, st.Err().Error(), damlErr.Error())
+
+	wrapped := AsDamlError(fmt.Errorf("failed to exercise choice: %w", st.Err()))
+	require.Equal(t, "CONTRACT_NOT_FOUND", wrapped.ErrorCode)
+	require.Equal(t, "Contract could not be found with id 00abc", wrapped.Message)
+}
+
+func TestAsDamlErrorRetryable(t *testing.T) {
+	st, err := status.New(codes.Aborted, "an opaque message").WithDetails(
+		&errdetails.ErrorInfo{Reason: "SUBMISSION_ALREADY_IN_FLIGHT", Metadata: map[string]string{"category": "2"}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)},
+	)
+	require.NoError(t, err)
+
+	damlErr := AsDamlError(st.Err())
+	require.Equal(t, "SUBMISSION_ALREADY_IN_FLIGHT", damlErr.ErrorCode)
+	require.Equal(t, "an opaque message", damlErr.Message)
+	require.Equal(t, CategoryContentionOnSharedResources, damlErr.Category())
+	require.True(t, damlErr.Retryable)
+	require.Equal(t, 2*time.Second, damlErr.RetryDelay)
+	require.ErrorIs(t, damlErr, ErrSubmissionAlreadyInFlight)
+
+	// Retryable by category even without retry info
+	damlErr = AsDamlError(status.Error(codes.Unavailable, "SEQUENCER_BACKPRESSURE(1,abc): overloaded"))
+	require.Equal(t, CategoryTransientServerFailure, damlErr.Category())
+	require.True(t, damlErr.Retryable)
 
-	AsDamlError(nil)
+	damlErr = AsDamlError(errors.New("regular error message"))
+	require.Zero(t, damlErr.Category())
+	require.False(t, damlErr.Retryable)
+	require.NotErrorIs(t, damlErr, &DamlError{ErrorCode: genericErr})
 }
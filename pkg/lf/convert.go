@@ -0,0 +1,654 @@
+package lf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/smartcontractkit/go-daml/pkg/value"
+)
+
+var (
+	// ErrMissingField is reported for missing fields of a record that are not optional.
+	ErrMissingField = errors.New("missing field")
+	// ErrUnknownField is reported for fields that the record type does not declare.
+	ErrUnknownField = errors.New("unknown field")
+	// ErrWrongType is reported for values that cannot be converted to the expected type.
+	ErrWrongType = errors.New("wrong type")
+	// ErrNumericScale is reported for numerics that do not fit the scale of their type.
+	ErrNumericScale = errors.New("numeric out of range")
+)
+
+// maxNumericPrecision is the number of significant digits of a Daml Numeric.
+const maxNumericPrecision = 38
+
+// FieldError is an invalid value at Path, such as transfers[1].amount, or the argument itself
+// when Path is empty.
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists all invalid values of an argument.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid value: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the field errors, so that errors.Is matches ErrMissingField and the like.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// conversion converts a single argument, collecting the errors of all its values.
+type conversion struct {
+	ctx       context.Context
+	converter *Converter
+	errs      []*FieldError
+}
+
+func (c *conversion) fail(path string, err error) value.Value {
+	c.errs = append(c.errs, &FieldError{Path: path, Err: err})
+	return nil
+}
+
+func (c *conversion) wrongType(path string, t *Type, v any) value.Value {
+	if v == nil {
+		return c.fail(path, fmt.Errorf("%w: expected %s, got nil", ErrWrongType, t))
+	}
+	return c.fail(path, fmt.Errorf("%w: expected %s, got %T", ErrWrongType, t, v))
+}
+
+func (c *conversion) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: c.errs}
+}
+
+func fieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// convert converts v to a value of type t, or records an error at path and returns nil.
+func (c *conversion) convert(path string, t *Type, v any) value.Value {
+	if t.Kind == TypeOptional {
+		return c.optional(path, t, v)
+	}
+
+	v = deref(v)
+	if v == nil && t.Kind != TypeUnit {
+		return c.wrongType(path, t, v)
+	}
+
+	switch t.Kind {
+	case TypeUnit:
+		switch x := v.(type) {
+		case nil, value.Unit, types.UNIT, struct{}:
+			return value.Unit{}
+		case map[string]any:
+			if isTagged(x, "unit") {
+				return value.Unit{}
+			}
+		}
+	case TypeBool:
+		if x, ok := v.(value.Bool); ok {
+			return x
+		}
+		if rv := goValue(v); rv.Kind() == reflect.Bool {
+			return value.Bool(rv.Bool())
+		}
+	case TypeInt64:
+		return c.int64(path, t, v)
+	case TypeText:
+		if x, ok := v.(value.Text); ok {
+			return x
+		}
+		if rv := goValue(v); rv.Kind() == reflect.String {
+			return value.Text(rv.String())
+		}
+	case TypeNumeric:
+		return c.numeric(path, t, v)
+	case TypeParty:
+		party, ok := v.(value.Party)
+		if m, tagged := v.(map[string]any); tagged && isTagged(m, "party") {
+			v = m["value"]
+		}
+		if rv := goValue(v); !ok && rv.Kind() == reflect.String {
+			party, ok = value.Party(rv.String()), true
+		}
+		if ok {
+			if party == "" {
+				return c.fail(path, fmt.Errorf("%w: party is empty", ErrWrongType))
+			}
+			return party
+		}
+	case TypeContractID:
+		contractID, ok := v.(value.ContractID)
+		if rv := goValue(v); !ok && rv.Kind() == reflect.String {
+			contractID, ok = value.ContractID(rv.String()), true
+		}
+		if ok {
+			if contractID == "" {
+				return c.fail(path, fmt.Errorf("%w: contract ID is empty", ErrWrongType))
+			}
+			return contractID
+		}
+	case TypeDate:
+		if x, ok := v.(value.Date); ok {
+			return x
+		}
+		if s, ok := v.(string); ok {
+			date, err := time.Parse(time.DateOnly, s)
+			if err != nil {
+				return c.fail(path, fmt.Errorf("%w: invalid date %q", ErrWrongType, s))
+			}
+			return value.DateFromTime(date)
+		}
+		if rv := goValue(v); rv.IsValid() && rv.Type().ConvertibleTo(timeType) {
+			return value.DateFromTime(rv.Convert(timeType).Interface().(time.Time))
+		}
+	case TypeTimestamp:
+		if x, ok := v.(value.Timestamp); ok {
+			return x
+		}
+		if s, ok := v.(string); ok {
+			timestamp, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return c.fail(path, fmt.Errorf("%w: invalid timestamp %q", ErrWrongType, s))
+			}
+			return value.TimestampFromTime(timestamp)
+		}
+		if rv := goValue(v); rv.IsValid() && rv.Type().ConvertibleTo(timeType) {
+			return value.TimestampFromTime(rv.Convert(timeType).Interface().(time.Time))
+		}
+	case TypeList:
+		return c.list(path, t, v)
+	case TypeTextMap:
+		return c.textMap(path, t, v)
+	case TypeGenMap:
+		return c.genMap(path, t, v)
+	case TypeCon:
+		return c.con(path, t, v)
+	case TypeVar:
+		return c.fail(path, fmt.Errorf("unbound type variable %s", t.Var))
+	default:
+		return c.fail(path, fmt.Errorf("type %s is not serializable", t))
+	}
+
+	return c.wrongType(path, t, v)
+}
+
+// deref dereferences pointers, returning nil for nil pointers. Big integers are numerics, not
+// pointers to them.
+func deref(v any) any {
+	switch x := v.(type) {
+	case *big.Int:
+		if x == nil {
+			return nil
+		}
+		return x
+	case types.DECIMAL:
+		if x == nil {
+			return nil
+		}
+		return x
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return v
+	}
+	if rv.IsNil() {
+		return nil
+	}
+	return deref(rv.Elem().Interface())
+}
+
+// goValue reflects v, unless v is already a Daml value whose type is checked explicitly.
+func goValue(v any) reflect.Value {
+	if _, ok := v.(value.Value); ok {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(v)
+}
+
+// isTagged reports whether m is a value tagged with _type by the generated ToMap methods.
+func isTagged(m map[string]any, tag string) bool {
+	return m["_type"] == tag
+}
+
+func (c *conversion) optional(path string, t *Type, v any) value.Value {
+	switch x := v.(type) {
+	case value.Optional:
+		if x.Value == nil {
+			return value.None()
+		}
+		v = x.Value
+	case map[string]any:
+		if isTagged(x, "optional") {
+			v = x["value"]
+		}
+	}
+
+	// Nil pointers are None
+	if deref(v) == nil {
+		return value.None()
+	}
+
+	elem := c.convert(path, t.Args[0], v)
+	if elem == nil {
+		return nil
+	}
+	return value.Some(elem)
+}
+
+func (c *conversion) int64(path string, t *Type, v any) value.Value {
+	if x, ok := v.(value.Int64); ok {
+		return x
+	}
+
+	switch x := v.(type) {
+	case json.Number:
+		v = string(x)
+	case string:
+	default:
+		rv := goValue(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return value.Int64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if rv.Uint() > math.MaxInt64 {
+				return c.fail(path, fmt.Errorf("%w: %d overflows Int", ErrWrongType, rv.Uint()))
+			}
+			return value.Int64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			f := rv.Float()
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return c.fail(path, fmt.Errorf("%w: %v is not an Int", ErrWrongType, f))
+			}
+			return value.Int64(f)
+		default:
+			return c.wrongType(path, t, v)
+		}
+	}
+
+	s := v.(string)
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return c.fail(path, fmt.Errorf("%w: %q is not an Int", ErrWrongType, s))
+	}
+	return value.Int64(i)
+}
+
+func (c *conversion) numeric(path string, t *Type, v any) value.Value {
+	if len(t.Args) != 1 || t.Args[0].Kind != TypeNat {
+		return c.fail(path, fmt.Errorf("numeric type %s has no scale", t))
+	}
+	scale := int32(t.Args[0].Nat)
+
+	var d decimal.Decimal
+	switch x := v.(type) {
+	case value.Numeric:
+		d = x.Decimal()
+	case decimal.Decimal:
+		d = x
+	case types.DECIMAL:
+		// Generated code represents Decimal, that is Numeric 10, as the unscaled integer
+		d = decimal.NewFromBigInt((*big.Int)(x), -10)
+	case *big.Int:
+		d = decimal.NewFromBigInt(x, -10)
+	case json.Number, string, types.NUMERIC:
+		s := strings.TrimSuffix(reflect.ValueOf(x).String(), ".")
+		var err error
+		if d, err = decimal.NewFromString(s); err != nil || strings.ContainsAny(s, "eE") {
+			return c.fail(path, fmt.Errorf("%w: %q is not a numeric", ErrWrongType, s))
+		}
+	default:
+		rv := goValue(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			d = decimal.NewFromInt(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			d = decimal.NewFromBigInt(new(big.Int).SetUint64(rv.Uint()), 0)
+		case reflect.Float32, reflect.Float64:
+			if math.IsNaN(rv.Float()) || math.IsInf(rv.Float(), 0) {
+				return c.fail(path, fmt.Errorf("%w: %v is not a numeric", ErrWrongType, rv.Float()))
+			}
+			d = decimal.NewFromFloat(rv.Float())
+		default:
+			return c.wrongType(path, t, v)
+		}
+	}
+
+	unscaled := d.Shift(scale)
+	if !unscaled.IsInteger() {
+		return c.fail(path, fmt.Errorf("%w: %s has more than %d digits after the decimal point", ErrNumericScale, d, scale))
+	}
+	numeric := value.NewNumeric(unscaled.BigInt(), scale)
+	if len(new(big.Int).Abs(numeric.Unscaled).String()) > maxNumericPrecision {
+		return c.fail(path, fmt.Errorf("%w: %s has more than %d digits before the decimal point", ErrNumericScale, d, maxNumericPrecision-scale))
+	}
+	return numeric
+}
+
+func (c *conversion) list(path string, t *Type, v any) value.Value {
+	if x, ok := v.(value.List); ok {
+		v = []value.Value(x)
+	}
+
+	rv := goValue(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return c.wrongType(path, t, v)
+	}
+
+	list := make(value.List, rv.Len())
+	for i := range list {
+		list[i] = c.convert(fmt.Sprintf("%s[%d]", path, i), t.Args[0], rv.Index(i).Interface())
+	}
+	return list
+}
+
+func (c *conversion) textMap(path string, t *Type, v any) value.Value {
+	switch x := v.(type) {
+	case value.TextMap:
+		textMap := make(value.TextMap, len(x))
+		for i, entry := range x {
+			textMap[i] = value.TextMapEntry{Key: entry.Key, Value: c.convert(fmt.Sprintf("%s[%q]", path, entry.Key), t.Args[0], entry.Value)}
+		}
+		return textMap
+	case map[string]any:
+		if isTagged(x, "textmap") {
+			v = deref(x["value"])
+		}
+	}
+
+	rv := goValue(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return c.wrongType(path, t, v)
+	}
+
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	textMap := make(value.TextMap, len(keys))
+	for i, key := range keys {
+		textMap[i] = value.TextMapEntry{
+			Key:   key.String(),
+			Value: c.convert(fmt.Sprintf("%s[%q]", path, key.String()), t.Args[0], rv.MapIndex(key).Interface()),
+		}
+	}
+	return textMap
+}
+
+func (c *conversion) genMap(path string, t *Type, v any) value.Value {
+	var entries [][2]any
+	switch x := v.(type) {
+	case value.GenMap:
+		for _, entry := range x {
+			entries = append(entries, [2]any{entry.Key, entry.Value})
+		}
+	case map[string]any:
+		if !isTagged(x, "genmap") {
+			break
+		}
+		if list, ok := x["entries"].([]any); ok {
+			for _, e := range list {
+				entry, ok := e.(map[string]any)
+				if !ok {
+					return c.wrongType(path, t, v)
+				}
+				entries = append(entries, [2]any{entry["key"], entry["value"]})
+			}
+			v = nil
+		} else {
+			v = deref(x["value"])
+		}
+	}
+
+	if v != nil && entries == nil {
+		rv := goValue(v)
+		if rv.Kind() != reflect.Map {
+			return c.wrongType(path, t, v)
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			entries = append(entries, [2]any{key.Interface(), rv.MapIndex(key).Interface()})
+		}
+	}
+
+	genMap := make(value.GenMap, len(entries))
+	for i, entry := range entries {
+		genMap[i] = value.GenMapEntry{
+			Key:   c.convert(fmt.Sprintf("%s[%v]", path, entry[0]), t.Args[0], entry[0]),
+			Value: c.convert(fmt.Sprintf("%s[%v]", path, entry[0]), t.Args[1], entry[1]),
+		}
+	}
+	return genMap
+}
+
+func (c *conversion) con(path string, t *Type, v any) value.Value {
+	dataType, err := c.converter.dataType(c.ctx, t.Con)
+	if err != nil {
+		return c.fail(path, err)
+	}
+	if len(dataType.Params) != len(t.Args) {
+		return c.fail(path, fmt.Errorf("type %s expects %d type arguments", t, len(dataType.Params)))
+	}
+
+	params := make(map[string]*Type, len(dataType.Params))
+	for i, param := range dataType.Params {
+		params[param] = t.Args[i]
+	}
+
+	switch dataType.Kind {
+	case DataTypeRecord:
+		return c.record(path, t, dataType, params, v)
+	case DataTypeVariant:
+		return c.variant(path, t, dataType, params, v)
+	case DataTypeEnum:
+		return c.enum(path, t, dataType, v)
+	default:
+		return c.fail(path, fmt.Errorf("type %s is not serializable", t))
+	}
+}
+
+func (c *conversion) record(path string, t *Type, dataType *DataType, params map[string]*Type, v any) value.Value {
+	fields, ok := recordFields(v)
+	if !ok {
+		return c.wrongType(path, t, v)
+	}
+
+	record := value.Record{Fields: make([]value.Field, 0, len(dataType.Fields))}
+	declared := make(map[string]bool, len(dataType.Fields))
+	for _, field := range dataType.Fields {
+		declared[field.Name] = true
+		fieldType := substitute(field.Type, params)
+
+		v, ok := fields[field.Name]
+		if !ok && fieldType.Kind != TypeOptional {
+			c.fail(fieldPath(path, field.Name), fmt.Errorf("%w of type %s", ErrMissingField, fieldType))
+			continue
+		}
+		record.Fields = append(record.Fields, value.Field{Label: field.Name, Value: c.convert(fieldPath(path, field.Name), fieldType, v)})
+	}
+
+	var unknown []string
+	for name := range fields {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		c.fail(fieldPath(path, name), fmt.Errorf("%w of %s", ErrUnknownField, dataType.ID.ModuleName+"."+dataType.ID.EntityName))
+	}
+
+	return record
+}
+
+// recordFields returns the fields of a record value by label.
+func recordFields(v any) (map[string]any, bool) {
+	switch x := v.(type) {
+	case value.Record:
+		fields := make(map[string]any, len(x.Fields))
+		for _, field := range x.Fields {
+			fields[field.Label] = field.Value
+		}
+		return fields, true
+	case model.DamlMapper:
+		return x.ToMap(), true
+	case types.TUPLE2:
+		return map[string]any{"_1": x.First, "_2": x.Second}, true
+	case types.TUPLE3:
+		return map[string]any{"_1": x.First, "_2": x.Second, "_3": x.Third}, true
+	case types.RELTIME:
+		return map[string]any{"microseconds": time.Duration(x).Microseconds()}, true
+	case types.SET:
+		entries := make([]any, len(x))
+		for i, elem := range x {
+			entries[i] = map[string]any{"key": elem, "value": value.Unit{}}
+		}
+		return map[string]any{"map": map[string]any{"_type": "genmap", "entries": entries}}, true
+	case map[string]any:
+		return x, true
+	}
+
+	rv := goValue(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	fields := make(map[string]any, rv.Len())
+	for _, key := range rv.MapKeys() {
+		fields[key.String()] = rv.MapIndex(key).Interface()
+	}
+	return fields, true
+}
+
+func (c *conversion) variant(path string, t *Type, dataType *DataType, params map[string]*Type, v any) value.Value {
+	var constructor string
+	var arg any
+	switch x := v.(type) {
+	case value.Variant:
+		constructor, arg = x.Constructor, x.Value
+	case types.VARIANT:
+		constructor, arg = x.GetVariantTag(), x.GetVariantValue()
+	case map[string]any:
+		if tag, ok := x["tag"].(string); ok && len(x) <= 2 {
+			constructor, arg = tag, x["value"]
+		} else if len(x) == 1 {
+			for tag, val := range x {
+				constructor, arg = tag, val
+			}
+		} else {
+			return c.wrongType(path, t, v)
+		}
+	default:
+		return c.wrongType(path, t, v)
+	}
+
+	for _, field := range dataType.Fields {
+		if field.Name == constructor {
+			argValue := c.convert(fieldPath(path, constructor), substitute(field.Type, params), arg)
+			if argValue == nil {
+				return nil
+			}
+			return value.Variant{Constructor: constructor, Value: argValue}
+		}
+	}
+	return c.fail(path, fmt.Errorf("%w: unknown constructor %q of variant %s", ErrWrongType, constructor, t))
+}
+
+func (c *conversion) enum(path string, t *Type, dataType *DataType, v any) value.Value {
+	var constructor string
+	switch x := v.(type) {
+	case value.Enum:
+		constructor = x.Constructor
+	case types.ENUM:
+		constructor = x.GetEnumConstructor()
+	default:
+		rv := goValue(v)
+		if rv.Kind() != reflect.String {
+			return c.wrongType(path, t, v)
+		}
+		constructor = rv.String()
+	}
+
+	for _, name := range dataType.Constructors {
+		if name == constructor {
+			return value.Enum{Constructor: constructor}
+		}
+	}
+	return c.fail(path, fmt.Errorf("%w: unknown constructor %q of enum %s", ErrWrongType, constructor, t))
+}
+
+// substitute replaces the type parameters of t by their arguments.
+func substitute(t *Type, params map[string]*Type) *Type {
+	if len(params) == 0 {
+		return t
+	}
+
+	if t.Kind == TypeVar {
+		if arg, ok := params[t.Var]; ok {
+			if len(t.Args) == 0 {
+				return arg
+			}
+			applied := *arg
+			applied.Args = append(append([]*Type(nil), arg.Args...), substituteAll(t.Args, params)...)
+			return &applied
+		}
+	}
+
+	if len(t.Args) == 0 {
+		return t
+	}
+	result := *t
+	result.Args = substituteAll(t.Args, params)
+	return &result
+}
+
+func substituteAll(types []*Type, params map[string]*Type) []*Type {
+	result := make([]*Type, len(types))
+	for i, t := range types {
+		result[i] = substitute(t, params)
+	}
+	return result
+}
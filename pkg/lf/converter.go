@@ -0,0 +1,416 @@
+package lf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+	"github.com/smartcontractkit/go-daml/pkg/value"
+)
+
+// Converter validates and converts the arguments of templates and choices against the type
+// signatures of their packages, which it loads from the ledger on first use and caches.
+type Converter struct {
+	packages  ledger.PackageService
+	preferred ledger.InteractiveSubmissionService
+	parties   []string
+
+	mu    sync.RWMutex
+	cache map[string]*Package
+	// Package IDs of #package-name references, set by UsePackage or resolved on first use
+	pinned   map[string]string
+	resolved map[string]string
+}
+
+// ConverterOption configures a Converter.
+type ConverterOption func(*Converter)
+
+// WithPreferredPackages resolves #package-name references to the package that the ledger uses for
+// commands submitted by parties, as returned by the preferred package version lookup of service.
+func WithPreferredPackages(service ledger.InteractiveSubmissionService, parties ...string) ConverterOption {
+	return func(c *Converter) {
+		c.preferred = service
+		c.parties = parties
+	}
+}
+
+// NewConverter returns a converter loading packages from packages.
+func NewConverter(packages ledger.PackageService, opts ...ConverterOption) *Converter {
+	c := &Converter{
+		packages: packages,
+		cache:    make(map[string]*Package),
+		pinned:   make(map[string]string),
+		resolved: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// AddPackage adds a decoded package, e.g. from a DAR, so that it is not loaded from the ledger.
+func (c *Converter) AddPackage(pkg *Package) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache[pkg.ID] = pkg
+	// The package may be a higher version than the one resolved before
+	delete(c.resolved, pkg.Name)
+}
+
+// UsePackage resolves #package-name references of the package name to packageID.
+func (c *Converter) UsePackage(name, packageID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pinned[name] = packageID
+}
+
+// Package returns the package packageID, loading it from the ledger if it is not cached.
+func (c *Converter) Package(ctx context.Context, packageID string) (*Package, error) {
+	c.mu.RLock()
+	pkg, ok := c.cache[packageID]
+	c.mu.RUnlock()
+	if ok {
+		return pkg, nil
+	}
+
+	if c.packages == nil {
+		return nil, fmt.Errorf("package %s is unknown", packageID)
+	}
+	resp, err := c.packages.GetPackage(ctx, &model.GetPackageRequest{PackageID: packageID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package %s: %w", packageID, err)
+	}
+	pkg, err = DecodePackage(packageID, resp.ArchivePayload)
+	if err != nil {
+		return nil, &decodeError{err: err}
+	}
+
+	c.AddPackage(pkg)
+	return pkg, nil
+}
+
+// resolvePackage returns the package referenced by a template ID, either by package ID or as
+// #package-name. Package names resolve to the package set by UsePackage, else to the preferred
+// package of the ledger if WithPreferredPackages is set, else to the highest version of the
+// package known to the ledger, or added if the converter has no package service. The package ID
+// of a name is resolved once.
+func (c *Converter) resolvePackage(ctx context.Context, ref string) (*Package, error) {
+	name, byName := strings.CutPrefix(ref, "#")
+	if !byName || isPackageID(name) {
+		return c.Package(ctx, name)
+	}
+
+	c.mu.RLock()
+	packageID, ok := c.pinned[name]
+	if !ok {
+		packageID, ok = c.resolved[name]
+	}
+	c.mu.RUnlock()
+	if ok {
+		return c.Package(ctx, packageID)
+	}
+
+	var pkg *Package
+	var err error
+	if c.preferred != nil {
+		pkg, err = c.preferredPackage(ctx, name)
+	} else {
+		pkg, err = c.highestVersion(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.resolved[name] = pkg.ID
+	c.mu.Unlock()
+	return pkg, nil
+}
+
+// preferredPackage returns the package that the ledger uses for the package name.
+func (c *Converter) preferredPackage(ctx context.Context, name string) (*Package, error) {
+	resp, err := c.preferred.GetPreferredPackageVersion(ctx, &model.GetPreferredPackageVersionRequest{
+		Parties:     c.parties,
+		PackageName: name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferred package %s: %w", name, err)
+	}
+	if resp.PackageReference == nil {
+		return nil, fmt.Errorf("package %s is unknown", name)
+	}
+	return c.Package(ctx, resp.PackageReference.PackageID)
+}
+
+// highestVersion returns the highest version of the package name. Packages that cannot be decoded,
+// such as packages of other Daml-LF versions, are skipped.
+func (c *Converter) highestVersion(ctx context.Context, name string) (*Package, error) {
+	var candidates []string
+	if c.packages != nil {
+		resp, err := c.packages.ListPackages(ctx, &model.ListPackagesRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list packages: %w", err)
+		}
+		candidates = resp.PackageIDs
+	} else {
+		c.mu.RLock()
+		for packageID := range c.cache {
+			candidates = append(candidates, packageID)
+		}
+		c.mu.RUnlock()
+	}
+
+	var best *Package
+	for _, packageID := range candidates {
+		pkg, err := c.Package(ctx, packageID)
+		var decodeErr *decodeError
+		if errors.As(err, &decodeErr) {
+			log.Debug().Err(err).Msgf("skipping package %s", packageID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if pkg.Name == name && (best == nil || compareVersions(pkg.Version, best.Version) > 0) {
+			best = pkg
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("package %s is unknown", name)
+	}
+	return best, nil
+}
+
+// decodeError is the error of a package loaded from the ledger that cannot be decoded
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string { return e.err.Error() }
+
+func (e *decodeError) Unwrap() error { return e.err }
+
+func isPackageID(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares dotted package versions such as 1.10.0 numerically.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+func parseID(id string) (ref, moduleName, entityName string, err error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[0] == "#" {
+		return "", "", "", fmt.Errorf("invalid identifier %q, expected package:module:entity", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func (c *Converter) dataType(ctx context.Context, id value.Identifier) (*DataType, error) {
+	pkg, err := c.Package(ctx, id.PackageID)
+	if err != nil {
+		return nil, err
+	}
+	dataType, ok := pkg.DataType(id.ModuleName, id.EntityName)
+	if !ok {
+		return nil, fmt.Errorf("type %s is unknown", id)
+	}
+	return dataType, nil
+}
+
+// Template returns the template templateID, formatted as package:module:entity with a package ID
+// or #package-name.
+func (c *Converter) Template(ctx context.Context, templateID string) (*Template, error) {
+	ref, moduleName, entityName, err := parseID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := c.resolvePackage(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, ok := pkg.Template(moduleName, entityName)
+	if !ok {
+		return nil, fmt.Errorf("template %s is unknown", templateID)
+	}
+	return tmpl, nil
+}
+
+// Choice returns choice of the template or interface templateID. Choices of templates include the
+// choices of the interfaces they implement.
+func (c *Converter) Choice(ctx context.Context, templateID, choice string) (*Choice, error) {
+	ref, moduleName, entityName, err := parseID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := c.resolvePackage(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if ifc, ok := pkg.Interface(moduleName, entityName); ok {
+		if ch, ok := ifc.Choices[choice]; ok {
+			return ch, nil
+		}
+		return nil, fmt.Errorf("choice %s of interface %s is unknown", choice, templateID)
+	}
+
+	tmpl, ok := pkg.Template(moduleName, entityName)
+	if !ok {
+		return nil, fmt.Errorf("template %s is unknown", templateID)
+	}
+	if ch, ok := tmpl.Choices[choice]; ok {
+		return ch, nil
+	}
+	for _, id := range tmpl.Implements {
+		ifcPkg, err := c.Package(ctx, id.PackageID)
+		if err != nil {
+			return nil, err
+		}
+		if ifc, ok := ifcPkg.Interface(id.ModuleName, id.EntityName); ok {
+			if ch, ok := ifc.Choices[choice]; ok {
+				return ch, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("choice %s of template %s is unknown", choice, templateID)
+}
+
+// Convert converts v to a Daml value of type t. All invalid values are reported together in a
+// *ValidationError.
+func (c *Converter) Convert(ctx context.Context, t *Type, v any) (value.Value, error) {
+	conv := &conversion{ctx: ctx, converter: c}
+	result := conv.convert("", t, v)
+	if err := conv.err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateArguments validates the create arguments of templateID and returns them converted to
+// Daml values, ready to be used as the Arguments of a model.CreateCommand.
+func (c *Converter) CreateArguments(ctx context.Context, templateID string, args any) (map[string]any, error) {
+	tmpl, err := c.Template(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.recordArguments(ctx, &Type{Kind: TypeCon, Con: tmpl.ID}, args)
+}
+
+// ChoiceArguments validates the arguments of choice of templateID and returns them converted to
+// Daml values, ready to be used as the Arguments of a model.ExerciseCommand.
+func (c *Converter) ChoiceArguments(ctx context.Context, templateID, choice string, args any) (map[string]any, error) {
+	ch, err := c.Choice(ctx, templateID, choice)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.recordArguments(ctx, ch.ArgType, args)
+}
+
+func (c *Converter) recordArguments(ctx context.Context, t *Type, args any) (map[string]any, error) {
+	v, err := c.Convert(ctx, t, args)
+	if err != nil {
+		return nil, err
+	}
+	return recordMap(v, t)
+}
+
+func recordMap(v value.Value, t *Type) (map[string]any, error) {
+	record, ok := v.(value.Record)
+	if !ok {
+		return nil, fmt.Errorf("argument type %s is not a record", t)
+	}
+
+	fields := make(map[string]any, len(record.Fields))
+	for _, field := range record.Fields {
+		fields[field.Label] = field.Value
+	}
+	return fields, nil
+}
+
+// ConvertCommands validates the arguments and keys of commands and replaces them by their
+// conversion to Daml values. Commands are left unchanged if any of them is invalid.
+func (c *Converter) ConvertCommands(ctx context.Context, commands []*model.Command) error {
+	type update struct {
+		target *map[string]any
+		value  map[string]any
+	}
+	var updates []update
+
+	for i, cmd := range commands {
+		var err error
+		switch command := cmd.Command.(type) {
+		case *model.CreateCommand:
+			var args map[string]any
+			if args, err = c.CreateArguments(ctx, command.TemplateID, command.Arguments); err == nil {
+				updates = append(updates, update{&command.Arguments, args})
+			}
+		case *model.ExerciseCommand:
+			var args map[string]any
+			if args, err = c.ChoiceArguments(ctx, command.TemplateID, command.Choice, command.Arguments); err == nil {
+				updates = append(updates, update{&command.Arguments, args})
+			}
+		case *model.ExerciseByKeyCommand:
+			var key, args map[string]any
+			if key, err = c.keyArguments(ctx, command.TemplateID, command.Key); err == nil {
+				updates = append(updates, update{&command.Key, key})
+				if args, err = c.ChoiceArguments(ctx, command.TemplateID, command.Choice, command.Arguments); err == nil {
+					updates = append(updates, update{&command.Arguments, args})
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid command %d: %w", i, err)
+		}
+	}
+
+	for _, u := range updates {
+		*u.target = u.value
+	}
+	return nil
+}
+
+func (c *Converter) keyArguments(ctx context.Context, templateID string, key map[string]any) (map[string]any, error) {
+	tmpl, err := c.Template(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.Key == nil {
+		return nil, fmt.Errorf("template %s has no key", templateID)
+	}
+
+	return c.recordArguments(ctx, tmpl.Key, key)
+}
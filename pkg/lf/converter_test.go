@@ -0,0 +1,332 @@
+package lf
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	damlcommon "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive"
+	"github.com/shopspring/decimal"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+	"github.com/smartcontractkit/go-daml/pkg/service/ledger"
+	"github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/smartcontractkit/go-daml/pkg/value"
+	"github.com/stretchr/testify/require"
+)
+
+const oneOfEverything = "#all-kinds-of:AllKindsOf:OneOfEverything"
+
+// fakePackageService serves the packages of a DAR
+type fakePackageService struct {
+	ledger.PackageService
+	payloads map[string][]byte
+
+	mu    sync.Mutex
+	gets  int
+	lists int
+}
+
+func newFakePackageService(t *testing.T, darPath string) *fakePackageService {
+	service := &fakePackageService{payloads: make(map[string][]byte)}
+	for _, dalf := range readDalfs(t, darPath) {
+		var archive damlcommon.Archive
+		require.NoError(t, proto.Unmarshal(dalf, &archive))
+		service.payloads[archive.Hash] = archive.Payload
+	}
+	return service
+}
+
+func (s *fakePackageService) ListPackages(_ context.Context, _ *model.ListPackagesRequest) (*model.ListPackagesResponse, error) {
+	s.mu.Lock()
+	s.lists++
+	s.mu.Unlock()
+
+	resp := &model.ListPackagesResponse{}
+	for packageID := range s.payloads {
+		resp.PackageIDs = append(resp.PackageIDs, packageID)
+	}
+	return resp, nil
+}
+
+func (s *fakePackageService) GetPackage(_ context.Context, req *model.GetPackageRequest) (*model.GetPackageResponse, error) {
+	s.mu.Lock()
+	s.gets++
+	s.mu.Unlock()
+
+	payload, ok := s.payloads[req.PackageID]
+	if !ok {
+		return nil, errors.New("package not found")
+	}
+	return &model.GetPackageResponse{ArchivePayload: payload, Hash: req.PackageID}, nil
+}
+
+// fakePreferredPackages returns packageID as the preferred package
+type fakePreferredPackages struct {
+	ledger.InteractiveSubmissionService
+	packageID string
+	requests  []*model.GetPreferredPackageVersionRequest
+}
+
+func (s *fakePreferredPackages) GetPreferredPackageVersion(_ context.Context, req *model.GetPreferredPackageVersionRequest) (*model.GetPreferredPackageVersionResponse, error) {
+	s.requests = append(s.requests, req)
+	if s.packageID == "" {
+		return &model.GetPreferredPackageVersionResponse{}, nil
+	}
+	return &model.GetPreferredPackageVersionResponse{
+		PackageReference: &model.PackageReference{PackageID: s.packageID, PackageName: req.PackageName},
+	}, nil
+}
+
+func validArguments() map[string]any {
+	pair := map[string]any{"left": 1, "right": int64(2)}
+	return map[string]any{
+		"operator":        types.PARTY("alice::1220"),
+		"someBoolean":     true,
+		"someInteger":     types.INT64(42),
+		"someDecimal":     "1.5",
+		"someMaybe":       7,
+		"someText":        types.TEXT("text"),
+		"someDate":        types.DATE(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		"someDatetime":    "2024-03-01T12:30:00.000001Z",
+		"someSimpleList":  []types.INT64{1, 2, 3},
+		"someSimplePair":  pair,
+		"someNestedPair":  map[string]any{"left": pair, "right": pair},
+		"someUglyNesting": map[string]any{"Both": map[string]any{"tag": "Left", "value": map[string]any{"left": pair, "right": pair}}},
+		"someMeasurement": decimal.RequireFromString("-2.25"),
+		"someEnum":        "Green",
+		"theUnit":         types.UNIT{},
+	}
+}
+
+func TestConverterCreateArguments(t *testing.T) {
+	service := newFakePackageService(t, "../../test-data/all-kinds-of-1.0.0_lf.dar")
+	converter := NewConverter(service)
+
+	args, err := converter.CreateArguments(context.Background(), oneOfEverything, validArguments())
+	require.NoError(t, err)
+
+	pair := value.NewRecord(value.Field{Label: "left", Value: value.Int64(1)}, value.Field{Label: "right", Value: value.Int64(2)})
+	require.Equal(t, map[string]any{
+		"operator":        value.Party("alice::1220"),
+		"someBoolean":     value.Bool(true),
+		"someInteger":     value.Int64(42),
+		"someDecimal":     value.NewNumeric(big.NewInt(15000000000), 10),
+		"someMaybe":       value.Some(value.Int64(7)),
+		"someMaybeNot":    value.None(),
+		"someText":        value.Text("text"),
+		"someDate":        value.DateFromTime(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		"someDatetime":    value.TimestampFromTime(time.Date(2024, 3, 1, 12, 30, 0, 1000, time.UTC)),
+		"someSimpleList":  value.List{value.Int64(1), value.Int64(2), value.Int64(3)},
+		"someSimplePair":  pair,
+		"someNestedPair":  value.NewRecord(value.Field{Label: "left", Value: pair}, value.Field{Label: "right", Value: pair}),
+		"someUglyNesting": value.Variant{Constructor: "Both", Value: value.Variant{Constructor: "Left", Value: value.NewRecord(value.Field{Label: "left", Value: pair}, value.Field{Label: "right", Value: pair})}},
+		"someMeasurement": value.NewNumeric(big.NewInt(-22500000000), 10),
+		"someEnum":        value.Enum{Constructor: "Green"},
+		"theUnit":         value.Unit{},
+	}, args)
+	require.Equal(t, "1.5000000000", args["someDecimal"].(value.Numeric).String())
+
+	// Packages are loaded once
+	gets := service.gets
+	_, err = converter.CreateArguments(context.Background(), allKindsOfPackageID+":AllKindsOf:OneOfEverything", args)
+	require.NoError(t, err)
+	require.Equal(t, gets, service.gets)
+
+	_, err = converter.CreateArguments(context.Background(), "#all-kinds-of:AllKindsOf:Missing", args)
+	require.EqualError(t, err, "template #all-kinds-of:AllKindsOf:Missing is unknown")
+	_, err = converter.CreateArguments(context.Background(), "#missing:AllKindsOf:OneOfEverything", args)
+	require.EqualError(t, err, "package missing is unknown")
+	_, err = converter.CreateArguments(context.Background(), "AllKindsOf:OneOfEverything", args)
+	require.EqualError(t, err, `invalid identifier "AllKindsOf:OneOfEverything", expected package:module:entity`)
+}
+
+func TestConverterFieldErrors(t *testing.T) {
+	converter := NewConverter(newFakePackageService(t, "../../test-data/all-kinds-of-1.0.0_lf.dar"))
+
+	args := validArguments()
+	delete(args, "operator")
+	args["someInteger"] = "forty-two"
+	args["someDecimal"] = "0.12345678901"
+	args["someMeasurement"] = "12345678901234567890123456789.5"
+	args["someSimpleList"] = []any{1, "two", 2.5}
+	args["someSimplePair"] = map[string]any{"left": 1}
+	args["someUglyNesting"] = map[string]any{"Middle": 1}
+	args["someEnum"] = "Purple"
+	args["someDate"] = "01/03/2024"
+	args["someMaybe"] = "seven"
+	args["extra"] = true
+
+	_, err := converter.CreateArguments(context.Background(), oneOfEverything, args)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	messages := make(map[string]string, len(validationErr.Errors))
+	for _, fieldErr := range validationErr.Errors {
+		messages[fieldErr.Path] = fieldErr.Err.Error()
+	}
+	require.Equal(t, map[string]string{
+		"operator":             "missing field of type Party",
+		"someInteger":          `wrong type: "forty-two" is not an Int`,
+		"someDecimal":          "numeric out of range: 0.12345678901 has more than 10 digits after the decimal point",
+		"someMeasurement":      "numeric out of range: 12345678901234567890123456789.5 has more than 28 digits before the decimal point",
+		"someSimpleList[1]":    `wrong type: "two" is not an Int`,
+		"someSimpleList[2]":    "wrong type: 2.5 is not an Int",
+		"someSimplePair.right": "missing field of type Int",
+		"someUglyNesting":      `wrong type: unknown constructor "Middle" of variant AllKindsOf.VPair (AllKindsOf.MyPair (AllKindsOf.MyPair Int))`,
+		"someEnum":             `wrong type: unknown constructor "Purple" of enum AllKindsOf.Color`,
+		"someDate":             `wrong type: invalid date "01/03/2024"`,
+		"someMaybe":            `wrong type: "seven" is not an Int`,
+		"extra":                "unknown field of AllKindsOf.OneOfEverything",
+	}, messages)
+
+	require.ErrorIs(t, err, ErrMissingField)
+	require.ErrorIs(t, err, ErrUnknownField)
+	require.ErrorIs(t, err, ErrNumericScale)
+	require.ErrorIs(t, err, ErrWrongType)
+	require.Contains(t, err.Error(), "invalid value: operator: missing field of type Party; ")
+
+	_, err = converter.CreateArguments(context.Background(), oneOfEverything, []int{1})
+	require.EqualError(t, err, "invalid value: wrong type: expected AllKindsOf.OneOfEverything, got []int")
+}
+
+func TestConverterChoiceArguments(t *testing.T) {
+	converter := NewConverter(newFakePackageService(t, "../../test-data/all-kinds-of-1.0.0_lf.dar"))
+
+	args, err := converter.ChoiceArguments(context.Background(), oneOfEverything, "Accept", map[string]any{})
+	require.NoError(t, err)
+	require.Empty(t, args)
+
+	args, err = converter.ChoiceArguments(context.Background(), oneOfEverything, "Archive", nil)
+	require.EqualError(t, err, "invalid value: wrong type: expected DA.Internal.Template.Archive, got nil")
+	require.Nil(t, args)
+
+	_, err = converter.ChoiceArguments(context.Background(), oneOfEverything, "Accept", map[string]any{"extra": 1})
+	require.EqualError(t, err, "invalid value: extra: unknown field of AllKindsOf.Accept")
+
+	_, err = converter.ChoiceArguments(context.Background(), oneOfEverything, "Reject", map[string]any{})
+	require.EqualError(t, err, "choice Reject of template "+oneOfEverything+" is unknown")
+}
+
+func TestConverterConvertCommands(t *testing.T) {
+	converter := NewConverter(newFakePackageService(t, "../../test-data/all-kinds-of-1.0.0_lf.dar"))
+
+	create := &model.CreateCommand{TemplateID: oneOfEverything, Arguments: validArguments()}
+	exercise := &model.ExerciseCommand{ContractID: "00abc", TemplateID: oneOfEverything, Choice: "Accept", Arguments: map[string]any{"extra": 1}}
+	commands := []*model.Command{{Command: create}, {Command: exercise}}
+
+	// Invalid commands leave all commands unchanged
+	err := converter.ConvertCommands(context.Background(), commands)
+	require.EqualError(t, err, "invalid command 1: invalid value: extra: unknown field of AllKindsOf.Accept")
+	require.Equal(t, types.PARTY("alice::1220"), create.Arguments["operator"])
+
+	exercise.Arguments = map[string]any{}
+	require.NoError(t, converter.ConvertCommands(context.Background(), commands))
+	require.Equal(t, value.Party("alice::1220"), create.Arguments["operator"])
+	require.Equal(t, value.None(), create.Arguments["someMaybeNot"])
+	require.Empty(t, exercise.Arguments)
+
+	// Converted arguments are converted again unchanged
+	converted := create.Arguments
+	require.NoError(t, converter.ConvertCommands(context.Background(), commands))
+	require.Equal(t, converted, create.Arguments)
+}
+
+func TestConverterResolvePackageName(t *testing.T) {
+	service := newFakePackageService(t, "../../test-data/all-kinds-of-1.0.0_lf.dar")
+	// Packages of other Daml-LF versions are skipped
+	service.payloads["0123"] = []byte("not a Daml-LF 2 package")
+
+	converter := NewConverter(service)
+	tmpl, err := converter.Template(context.Background(), oneOfEverything)
+	require.NoError(t, err)
+	require.Equal(t, allKindsOfPackageID, tmpl.ID.PackageID)
+
+	// The package ID of the name is resolved once
+	_, err = converter.Choice(context.Background(), oneOfEverything, "Archive")
+	require.NoError(t, err)
+	require.Equal(t, 1, service.lists)
+
+	// The ledger's preferred package is used instead of listing the packages
+	preferred := &fakePreferredPackages{packageID: allKindsOfPackageID}
+	converter = NewConverter(service, WithPreferredPackages(preferred, "alice::1220"))
+	tmpl, err = converter.Template(context.Background(), oneOfEverything)
+	require.NoError(t, err)
+	require.Equal(t, allKindsOfPackageID, tmpl.ID.PackageID)
+	require.Equal(t, []*model.GetPreferredPackageVersionRequest{{Parties: []string{"alice::1220"}, PackageName: "all-kinds-of"}}, preferred.requests)
+	require.Equal(t, 1, service.lists)
+
+	converter = NewConverter(service, WithPreferredPackages(&fakePreferredPackages{}))
+	_, err = converter.Template(context.Background(), oneOfEverything)
+	require.EqualError(t, err, "package all-kinds-of is unknown")
+
+	// Package IDs set by the caller take precedence
+	converter.UsePackage("all-kinds-of", allKindsOfPackageID)
+	tmpl, err = converter.Template(context.Background(), oneOfEverything)
+	require.NoError(t, err)
+	require.Equal(t, allKindsOfPackageID, tmpl.ID.PackageID)
+}
+
+func TestConverterConvert(t *testing.T) {
+	converter := NewConverter(nil)
+	numeric := func(scale int64) *Type {
+		return &Type{Kind: TypeNumeric, Args: []*Type{{Kind: TypeNat, Nat: scale}}}
+	}
+
+	tests := []struct {
+		name     string
+		typ      *Type
+		input    any
+		expected value.Value
+		err      string
+	}{
+		{"numeric from float", numeric(2), 1.25, value.NewNumeric(big.NewInt(125), 2), ""},
+		{"numeric from decimal", numeric(0), types.DECIMAL(big.NewInt(70000000000)), value.NewNumeric(big.NewInt(7), 0), ""},
+		{"numeric from value", numeric(3), value.NewNumeric(big.NewInt(5), 1), value.NewNumeric(big.NewInt(500), 3), ""},
+		{"numeric exponent", numeric(2), "1e3", nil, `invalid value: wrong type: "1e3" is not a numeric`},
+		{"numeric scale", numeric(0), "0.5", nil, "invalid value: numeric out of range: 0.5 has more than 0 digits after the decimal point"},
+		{"int from json", &Type{Kind: TypeInt64}, 3.0, value.Int64(3), ""},
+		{"int from value", &Type{Kind: TypeInt64}, value.Text("3"), nil, "invalid value: wrong type: expected Int, got value.Text"},
+		{"empty party", &Type{Kind: TypeParty}, "", nil, "invalid value: wrong type: party is empty"},
+		{"tagged party", &Type{Kind: TypeParty}, types.PARTY("bob").ToMap(), value.Party("bob"), ""},
+		{"nil optional", &Type{Kind: TypeOptional, Args: []*Type{{Kind: TypeText}}}, (*string)(nil), value.None(), ""},
+		{
+			"text map",
+			&Type{Kind: TypeTextMap, Args: []*Type{{Kind: TypeInt64}}},
+			map[string]any{"_type": "textmap", "value": map[string]int{"b": 2, "a": 1}},
+			value.TextMap{{Key: "a", Value: value.Int64(1)}, {Key: "b", Value: value.Int64(2)}},
+			"",
+		},
+		{
+			"text map entry",
+			&Type{Kind: TypeTextMap, Args: []*Type{{Kind: TypeInt64}}},
+			map[string]any{"a": "one"},
+			nil,
+			`invalid value: ["a"]: wrong type: "one" is not an Int`,
+		},
+		{
+			"gen map",
+			&Type{Kind: TypeGenMap, Args: []*Type{{Kind: TypeInt64}, {Kind: TypeText}}},
+			map[int]string{2: "b", 1: "a"},
+			value.GenMap{{Key: value.Int64(1), Value: value.Text("a")}, {Key: value.Int64(2), Value: value.Text("b")}},
+			"",
+		},
+		{"unbound variable", &Type{Kind: TypeVar, Var: "a"}, 1, nil, "invalid value: unbound type variable a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.Convert(context.Background(), tt.typ, tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
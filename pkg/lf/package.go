@@ -0,0 +1,439 @@
+// Package lf reads the type signatures of Daml-LF packages and converts Go values to the Daml
+// values of their templates and choices, reporting the path of each invalid field before the
+// commands are submitted rather than as a ledger-side preprocessing failure.
+package lf
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	damlcommon "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive"
+	daml "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/daml/lf/archive/daml_lf_2"
+	"github.com/smartcontractkit/go-daml/pkg/value"
+)
+
+// TypeKind is the kind of a serializable Daml-LF type.
+type TypeKind int
+
+const (
+	TypeUnit TypeKind = iota + 1
+	TypeBool
+	TypeInt64
+	TypeText
+	TypeNumeric
+	TypeParty
+	TypeContractID
+	TypeDate
+	TypeTimestamp
+	TypeList
+	TypeOptional
+	TypeTextMap
+	TypeGenMap
+	// TypeCon is a record, variant or enum, applied to Args
+	TypeCon
+	// TypeVar is a type parameter of a data type
+	TypeVar
+	// TypeNat is a type-level natural number, the scale argument of Numeric
+	TypeNat
+)
+
+// Type is a Daml-LF type. Args are the element type of lists and optionals, the value type of
+// text maps, the key and value types of maps, the scale of numerics and the arguments of type
+// constructors and variables.
+type Type struct {
+	Kind TypeKind
+	Con  value.Identifier
+	Var  string
+	Nat  int64
+	Args []*Type
+}
+
+// String formats the type in Daml syntax, e.g. Optional (Numeric 10).
+func (t *Type) String() string {
+	var name string
+	switch t.Kind {
+	case TypeUnit:
+		return "()"
+	case TypeBool:
+		name = "Bool"
+	case TypeInt64:
+		name = "Int"
+	case TypeText:
+		name = "Text"
+	case TypeNumeric:
+		name = "Numeric"
+	case TypeParty:
+		name = "Party"
+	case TypeContractID:
+		name = "ContractId"
+	case TypeDate:
+		name = "Date"
+	case TypeTimestamp:
+		name = "Time"
+	case TypeList:
+		if len(t.Args) == 1 {
+			return "[" + t.Args[0].String() + "]"
+		}
+		name = "List"
+	case TypeOptional:
+		name = "Optional"
+	case TypeTextMap:
+		name = "TextMap"
+	case TypeGenMap:
+		name = "Map"
+	case TypeCon:
+		name = t.Con.ModuleName + "." + t.Con.EntityName
+	case TypeVar:
+		name = t.Var
+	case TypeNat:
+		return fmt.Sprint(t.Nat)
+	default:
+		return "unknown"
+	}
+
+	for _, arg := range t.Args {
+		if len(arg.Args) > 0 && arg.Kind != TypeList {
+			name += " (" + arg.String() + ")"
+		} else {
+			name += " " + arg.String()
+		}
+	}
+	return name
+}
+
+// DataTypeKind is the kind of a data type definition.
+type DataTypeKind int
+
+const (
+	DataTypeRecord DataTypeKind = iota + 1
+	DataTypeVariant
+	DataTypeEnum
+	DataTypeInterface
+)
+
+// Field is a field of a record or a constructor of a variant.
+type Field struct {
+	Name string
+	Type *Type
+}
+
+// DataType is a record, variant or enum definition with type parameters Params.
+type DataType struct {
+	ID     value.Identifier
+	Kind   DataTypeKind
+	Params []string
+	// Fields are the fields of records and the constructors of variants
+	Fields []Field
+	// Constructors are the constructors of enums
+	Constructors []string
+	Serializable bool
+}
+
+// Choice is a choice of a template or interface.
+type Choice struct {
+	Name       string
+	Consuming  bool
+	ArgType    *Type
+	ReturnType *Type
+}
+
+// Template is a template definition, whose create arguments are the record of the same ID.
+type Template struct {
+	ID      value.Identifier
+	Choices map[string]*Choice
+	// Key is the type of the contract key, nil for templates without key
+	Key        *Type
+	Implements []value.Identifier
+}
+
+// Interface is an interface definition.
+type Interface struct {
+	ID      value.Identifier
+	Choices map[string]*Choice
+	View    *Type
+}
+
+// Package is the type signature of a Daml-LF package. Definitions are keyed by Module:Entity.
+type Package struct {
+	ID         string
+	Name       string
+	Version    string
+	DataTypes  map[string]*DataType
+	Templates  map[string]*Template
+	Interfaces map[string]*Interface
+}
+
+func qualifiedName(moduleName, entityName string) string {
+	return moduleName + ":" + entityName
+}
+
+// DataType returns the data type moduleName:entityName.
+func (p *Package) DataType(moduleName, entityName string) (*DataType, bool) {
+	dt, ok := p.DataTypes[qualifiedName(moduleName, entityName)]
+	return dt, ok
+}
+
+// Template returns the template moduleName:entityName.
+func (p *Package) Template(moduleName, entityName string) (*Template, bool) {
+	tmpl, ok := p.Templates[qualifiedName(moduleName, entityName)]
+	return tmpl, ok
+}
+
+// Interface returns the interface moduleName:entityName.
+func (p *Package) Interface(moduleName, entityName string) (*Interface, bool) {
+	ifc, ok := p.Interfaces[qualifiedName(moduleName, entityName)]
+	return ifc, ok
+}
+
+// DecodeArchive decodes the type signature of a .dalf file, a Daml-LF archive.
+func DecodeArchive(dalf []byte) (*Package, error) {
+	var archive damlcommon.Archive
+	if err := proto.Unmarshal(dalf, &archive); err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+
+	return DecodePackage(archive.Hash, archive.Payload)
+}
+
+// DecodePackage decodes the type signature of package packageID from its archive payload, as
+// returned by PackageService.GetPackage. Only Daml-LF 2 packages are supported.
+func DecodePackage(packageID string, archivePayload []byte) (*Package, error) {
+	var payload damlcommon.ArchivePayload
+	if err := proto.Unmarshal(archivePayload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode archive payload of package %s: %w", packageID, err)
+	}
+
+	lfBytes := payload.GetDamlLf_2()
+	if lfBytes == nil {
+		return nil, fmt.Errorf("package %s is not a Daml-LF 2 package", packageID)
+	}
+
+	var pkg daml.Package
+	if err := proto.Unmarshal(lfBytes, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to decode Daml-LF package %s: %w", packageID, err)
+	}
+
+	d := &decoder{pkg: &pkg, packageID: packageID}
+	return d.decode()
+}
+
+type decoder struct {
+	pkg       *daml.Package
+	packageID string
+}
+
+func (d *decoder) decode() (result *Package, err error) {
+	// Malformed packages can refer to interned strings, names and types out of range
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("invalid Daml-LF package %s: %v", d.packageID, r)
+		}
+	}()
+
+	result = &Package{
+		ID:         d.packageID,
+		DataTypes:  make(map[string]*DataType),
+		Templates:  make(map[string]*Template),
+		Interfaces: make(map[string]*Interface),
+	}
+	if metadata := d.pkg.Metadata; metadata != nil {
+		result.Name = d.str(metadata.NameInternedStr)
+		result.Version = d.str(metadata.VersionInternedStr)
+	}
+
+	for _, module := range d.pkg.Modules {
+		moduleName := d.dottedName(module.NameInternedDname)
+
+		for _, dt := range module.DataTypes {
+			dataType := d.dataType(moduleName, dt)
+			result.DataTypes[qualifiedName(moduleName, dataType.ID.EntityName)] = dataType
+		}
+
+		for _, tmpl := range module.Templates {
+			template := &Template{
+				ID:      d.identifier(moduleName, tmpl.TyconInternedDname),
+				Choices: d.choices(tmpl.Choices),
+			}
+			if tmpl.Key != nil {
+				template.Key = d.typ(tmpl.Key.Type)
+			}
+			for _, impl := range tmpl.Implements {
+				template.Implements = append(template.Implements, d.typeConID(impl.Interface))
+			}
+			result.Templates[qualifiedName(moduleName, template.ID.EntityName)] = template
+		}
+
+		for _, ifc := range module.Interfaces {
+			iface := &Interface{
+				ID:      d.identifier(moduleName, ifc.TyconInternedDname),
+				Choices: d.choices(ifc.Choices),
+				View:    d.typ(ifc.View),
+			}
+			result.Interfaces[qualifiedName(moduleName, iface.ID.EntityName)] = iface
+		}
+	}
+
+	return result, nil
+}
+
+func (d *decoder) dataType(moduleName string, dt *daml.DefDataType) *DataType {
+	dataType := &DataType{
+		ID:           d.identifier(moduleName, dt.NameInternedDname),
+		Serializable: dt.Serializable,
+	}
+	for _, param := range dt.Params {
+		dataType.Params = append(dataType.Params, d.str(param.VarInternedStr))
+	}
+
+	switch cons := dt.DataCons.(type) {
+	case *daml.DefDataType_Record:
+		dataType.Kind = DataTypeRecord
+		dataType.Fields = d.fields(cons.Record.GetFields())
+	case *daml.DefDataType_Variant:
+		dataType.Kind = DataTypeVariant
+		dataType.Fields = d.fields(cons.Variant.GetFields())
+	case *daml.DefDataType_Enum:
+		dataType.Kind = DataTypeEnum
+		for _, constructor := range cons.Enum.GetConstructorsInternedStr() {
+			dataType.Constructors = append(dataType.Constructors, d.str(constructor))
+		}
+	case *daml.DefDataType_Interface:
+		dataType.Kind = DataTypeInterface
+	}
+
+	return dataType
+}
+
+func (d *decoder) fields(fields []*daml.FieldWithType) []Field {
+	result := make([]Field, len(fields))
+	for i, field := range fields {
+		result[i] = Field{Name: d.str(field.FieldInternedStr), Type: d.typ(field.Type)}
+	}
+	return result
+}
+
+func (d *decoder) choices(choices []*daml.TemplateChoice) map[string]*Choice {
+	result := make(map[string]*Choice, len(choices))
+	for _, ch := range choices {
+		choice := &Choice{
+			Name:       d.str(ch.NameInternedStr),
+			Consuming:  ch.Consuming,
+			ReturnType: d.typ(ch.RetType),
+		}
+		if ch.ArgBinder != nil {
+			choice.ArgType = d.typ(ch.ArgBinder.Type)
+		}
+		result[choice.Name] = choice
+	}
+	return result
+}
+
+func (d *decoder) typ(t *daml.Type) *Type {
+	if t == nil {
+		return nil
+	}
+
+	switch v := t.Sum.(type) {
+	case *daml.Type_InternedType:
+		return d.typ(d.pkg.InternedTypes[v.InternedType])
+	case *daml.Type_Builtin_:
+		return &Type{Kind: builtinKind(v.Builtin.Builtin), Args: d.types(v.Builtin.Args)}
+	case *daml.Type_Con_:
+		return &Type{Kind: TypeCon, Con: d.typeConID(v.Con.Tycon), Args: d.types(v.Con.Args)}
+	case *daml.Type_Var_:
+		return &Type{Kind: TypeVar, Var: d.str(v.Var.VarInternedStr), Args: d.types(v.Var.Args)}
+	case *daml.Type_Nat:
+		return &Type{Kind: TypeNat, Nat: v.Nat}
+	case *daml.Type_Tapp:
+		// Type applications are flattened into the arguments of the applied type
+		lhs := *d.typ(v.Tapp.Lhs)
+		lhs.Args = append(append([]*Type(nil), lhs.Args...), d.typ(v.Tapp.Rhs))
+		return &lhs
+	default:
+		// Functions, structs, synonyms and foralls are not serializable
+		return &Type{}
+	}
+}
+
+func (d *decoder) types(types []*daml.Type) []*Type {
+	if len(types) == 0 {
+		return nil
+	}
+	result := make([]*Type, len(types))
+	for i, t := range types {
+		result[i] = d.typ(t)
+	}
+	return result
+}
+
+func builtinKind(builtin daml.BuiltinType) TypeKind {
+	switch builtin {
+	case daml.BuiltinType_UNIT:
+		return TypeUnit
+	case daml.BuiltinType_BOOL:
+		return TypeBool
+	case daml.BuiltinType_INT64:
+		return TypeInt64
+	case daml.BuiltinType_TEXT:
+		return TypeText
+	case daml.BuiltinType_NUMERIC:
+		return TypeNumeric
+	case daml.BuiltinType_PARTY:
+		return TypeParty
+	case daml.BuiltinType_CONTRACT_ID:
+		return TypeContractID
+	case daml.BuiltinType_DATE:
+		return TypeDate
+	case daml.BuiltinType_TIMESTAMP:
+		return TypeTimestamp
+	case daml.BuiltinType_LIST:
+		return TypeList
+	case daml.BuiltinType_OPTIONAL:
+		return TypeOptional
+	case daml.BuiltinType_TEXTMAP:
+		return TypeTextMap
+	case daml.BuiltinType_GENMAP:
+		return TypeGenMap
+	default:
+		return 0
+	}
+}
+
+func (d *decoder) typeConID(id *daml.TypeConId) value.Identifier {
+	return value.Identifier{
+		PackageID:  d.modulePackageID(id.Module),
+		ModuleName: d.dottedName(id.Module.ModuleNameInternedDname),
+		EntityName: d.dottedName(id.NameInternedDname),
+	}
+}
+
+func (d *decoder) modulePackageID(module *daml.ModuleId) string {
+	switch id := module.PackageId.Sum.(type) {
+	case *daml.SelfOrImportedPackageId_ImportedPackageIdInternedStr:
+		return d.str(id.ImportedPackageIdInternedStr)
+	case *daml.SelfOrImportedPackageId_PackageImportId:
+		return d.pkg.GetPackageImports().ImportedPackages[id.PackageImportId]
+	default:
+		return d.packageID
+	}
+}
+
+func (d *decoder) identifier(moduleName string, nameInternedDname int32) value.Identifier {
+	return value.Identifier{PackageID: d.packageID, ModuleName: moduleName, EntityName: d.dottedName(nameInternedDname)}
+}
+
+func (d *decoder) str(id int32) string {
+	return d.pkg.InternedStrings[id]
+}
+
+func (d *decoder) dottedName(id int32) string {
+	var name string
+	for i, segment := range d.pkg.InternedDottedNames[id].SegmentsInternedStr {
+		if i > 0 {
+			name += "."
+		}
+		name += d.str(segment)
+	}
+	return name
+}
@@ -0,0 +1,104 @@
+package lf
+
+import (
+	"archive/zip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/go-daml/pkg/value"
+	"github.com/stretchr/testify/require"
+)
+
+const allKindsOfPackageID = "6d7e83e81a0a7960eec37340f5b11e7a61606bd9161f413684bc345c3f387948"
+
+// readDalfs returns the dalf files of the DAR at darPath
+func readDalfs(t *testing.T, darPath string) [][]byte {
+	reader, err := zip.OpenReader(darPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	var dalfs [][]byte
+	for _, file := range reader.File {
+		if !strings.HasSuffix(file.Name, ".dalf") {
+			continue
+		}
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		dalfs = append(dalfs, content)
+	}
+	return dalfs
+}
+
+func TestDecodeArchive(t *testing.T) {
+	var pkg *Package
+	for _, dalf := range readDalfs(t, "../../test-data/all-kinds-of-1.0.0_lf.dar") {
+		decoded, err := DecodeArchive(dalf)
+		require.NoError(t, err)
+		if decoded.ID == allKindsOfPackageID {
+			pkg = decoded
+		}
+	}
+	require.NotNil(t, pkg)
+	require.Equal(t, "all-kinds-of", pkg.Name)
+	require.Equal(t, "1.0.0", pkg.Version)
+
+	record, ok := pkg.DataType("AllKindsOf", "OneOfEverything")
+	require.True(t, ok)
+	require.Equal(t, DataTypeRecord, record.Kind)
+	require.Equal(t, value.Identifier{PackageID: allKindsOfPackageID, ModuleName: "AllKindsOf", EntityName: "OneOfEverything"}, record.ID)
+	types := make(map[string]string, len(record.Fields))
+	for _, field := range record.Fields {
+		types[field.Name] = field.Type.String()
+	}
+	require.Equal(t, map[string]string{
+		"operator":        "Party",
+		"someBoolean":     "Bool",
+		"someInteger":     "Int",
+		"someDecimal":     "Numeric 10",
+		"someMaybe":       "Optional Int",
+		"someMaybeNot":    "Optional Int",
+		"someText":        "Text",
+		"someDate":        "Date",
+		"someDatetime":    "Time",
+		"someSimpleList":  "[Int]",
+		"someSimplePair":  "AllKindsOf.MyPair Int",
+		"someNestedPair":  "AllKindsOf.MyPair (AllKindsOf.MyPair Int)",
+		"someUglyNesting": "AllKindsOf.VPair (AllKindsOf.MyPair (AllKindsOf.MyPair Int))",
+		"someMeasurement": "Numeric 10",
+		"someEnum":        "AllKindsOf.Color",
+		"theUnit":         "()",
+	}, types)
+	require.Equal(t, allKindsOfPackageID, record.Fields[11].Type.Con.PackageID)
+
+	pair, ok := pkg.DataType("AllKindsOf", "MyPair")
+	require.True(t, ok)
+	require.Equal(t, []string{"a"}, pair.Params)
+
+	variant, ok := pkg.DataType("AllKindsOf", "VPair")
+	require.True(t, ok)
+	require.Equal(t, DataTypeVariant, variant.Kind)
+	require.Equal(t, "AllKindsOf.VPair a", variant.Fields[2].Type.String())
+
+	enum, ok := pkg.DataType("AllKindsOf", "Color")
+	require.True(t, ok)
+	require.Equal(t, DataTypeEnum, enum.Kind)
+	require.Equal(t, []string{"Red", "Green", "Blue"}, enum.Constructors)
+
+	tmpl, ok := pkg.Template("AllKindsOf", "OneOfEverything")
+	require.True(t, ok)
+	require.Nil(t, tmpl.Key)
+	require.Len(t, tmpl.Choices, 2)
+	require.True(t, tmpl.Choices["Accept"].Consuming)
+	require.Equal(t, "AllKindsOf.Accept", tmpl.Choices["Accept"].ArgType.String())
+	require.Equal(t, "()", tmpl.Choices["Accept"].ReturnType.String())
+	require.Equal(t, "DA.Internal.Template.Archive", tmpl.Choices["Archive"].ArgType.String())
+
+	_, err := DecodeArchive([]byte("not an archive"))
+	require.Error(t, err)
+	_, err = DecodePackage(allKindsOfPackageID, nil)
+	require.EqualError(t, err, "package "+allKindsOfPackageID+" is not a Daml-LF 2 package")
+}
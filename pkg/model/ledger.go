@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"time"
 
+	"google.golang.org/protobuf/types/known/anypb"
+
 	"github.com/smartcontractkit/go-daml/pkg/types"
 )
 
@@ -173,6 +175,8 @@ func (StatusOK) isStatus() {}
 type StatusError struct {
 	Code    int32
 	Message string
+	// Details are the error details of the status, such as its ErrorInfo and RetryInfo
+	Details []*anypb.Any
 }
 
 func (StatusError) isStatus() {}
@@ -185,6 +189,21 @@ type SubmitResponse struct{}
 
 type SubmitAndWaitRequest struct {
 	Commands *Commands
+	// TransactionFormat selects the events returned by SubmitAndWaitForTransaction.
+	// When nil, the ledger returns the ACS delta visible to the actAs parties.
+	TransactionFormat *TransactionFormat
+}
+
+type TransactionShape int32
+
+const (
+	TransactionShapeAcsDelta      TransactionShape = 1
+	TransactionShapeLedgerEffects TransactionShape = 2
+)
+
+type TransactionFormat struct {
+	EventFormat      *EventFormat
+	TransactionShape TransactionShape
 }
 
 type SubmitAndWaitResponse struct {
@@ -167,6 +167,17 @@ const (
 	SigningKeyUsageProofOfOwnership        SigningKeyUsage = 5
 )
 
+type CryptoKeyFormat int32
+
+const (
+	CryptoKeyFormatUnspecified                 CryptoKeyFormat = 0
+	CryptoKeyFormatDER                         CryptoKeyFormat = 2
+	CryptoKeyFormatRaw                         CryptoKeyFormat = 3
+	CryptoKeyFormatDERX509SubjectPublicKeyInfo CryptoKeyFormat = 4
+	CryptoKeyFormatDERPKCS8PrivateKeyInfo      CryptoKeyFormat = 5
+	CryptoKeyFormatSymbolic                    CryptoKeyFormat = 10000
+)
+
 type PublicKey struct {
 	Format  int32
 	Key     []byte
@@ -286,3 +297,174 @@ type ImportTopologySnapshotRequest struct {
 }
 
 type ImportTopologySnapshotResponse struct{}
+
+type ListVettedPackagesRequest struct {
+	BaseQuery         *BaseQuery
+	FilterParticipant string
+}
+
+type ListVettedPackagesResponse struct {
+	Results []*VettedPackagesResult
+}
+
+type VettedPackagesResult struct {
+	Context *BaseResult
+	Item    *VettedPackages
+}
+
+type VettedPackages struct {
+	ParticipantUID string
+	Packages       []VettedPackage
+}
+
+type VettedPackage struct {
+	PackageID  string
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+}
+
+type ListOwnerToKeyMappingRequest struct {
+	BaseQuery          *BaseQuery
+	FilterKeyOwnerType string
+	FilterKeyOwnerUID  string
+}
+
+type ListOwnerToKeyMappingResponse struct {
+	Results []*OwnerToKeyMappingResult
+}
+
+type OwnerToKeyMappingResult struct {
+	Context *BaseResult
+	Item    *OwnerToKeyMapping
+}
+
+type OwnerToKeyMapping struct {
+	Member         string
+	SigningKeys    []PublicKey
+	EncryptionKeys []PublicKey
+}
+
+type ListSynchronizerParametersStateRequest struct {
+	BaseQuery            *BaseQuery
+	FilterSynchronizerID string
+}
+
+type ListSynchronizerParametersStateResponse struct {
+	Results []*SynchronizerParametersResult
+}
+
+type SynchronizerParametersResult struct {
+	Context *BaseResult
+	Item    *DynamicSynchronizerParameters
+}
+
+type DynamicSynchronizerParameters struct {
+	ConfirmationResponseTimeout         time.Duration
+	MediatorReactionTimeout             time.Duration
+	AssignmentExclusivityTimeout        time.Duration
+	LedgerTimeRecordTimeTolerance       time.Duration
+	ReconciliationInterval              time.Duration
+	MediatorDeduplicationTimeout        time.Duration
+	MaxRequestSize                      uint32
+	OnboardingRestriction               OnboardingRestriction
+	ConfirmationRequestsMaxRate         uint32
+	SequencerAggregateSubmissionTimeout time.Duration
+	PreparationTimeRecordTimeTolerance  time.Duration
+}
+
+type OnboardingRestriction int32
+
+const (
+	OnboardingRestrictionUnspecified        OnboardingRestriction = 0
+	OnboardingRestrictionUnrestrictedOpen   OnboardingRestriction = 1
+	OnboardingRestrictionUnrestrictedLocked OnboardingRestriction = 2
+	OnboardingRestrictionRestrictedOpen     OnboardingRestriction = 3
+	OnboardingRestrictionRestrictedLocked   OnboardingRestriction = 4
+)
+
+type ListDecentralizedNamespaceDefinitionRequest struct {
+	BaseQuery       *BaseQuery
+	FilterNamespace string
+}
+
+type ListDecentralizedNamespaceDefinitionResponse struct {
+	Results []*DecentralizedNamespaceDefinitionResult
+}
+
+type DecentralizedNamespaceDefinitionResult struct {
+	Context *BaseResult
+	Item    *DecentralizedNamespaceDefinition
+}
+
+type DecentralizedNamespaceDefinition struct {
+	DecentralizedNamespace string
+	Threshold              int32
+	Owners                 []string
+}
+
+type ListMediatorSynchronizerStateRequest struct {
+	BaseQuery            *BaseQuery
+	FilterSynchronizerID string
+}
+
+type ListMediatorSynchronizerStateResponse struct {
+	Results []*MediatorSynchronizerStateResult
+}
+
+type MediatorSynchronizerStateResult struct {
+	Context *BaseResult
+	Item    *MediatorSynchronizerState
+}
+
+type MediatorSynchronizerState struct {
+	SynchronizerID string
+	Group          uint32
+	Threshold      uint32
+	Active         []string
+	Observers      []string
+}
+
+type ListSequencerSynchronizerStateRequest struct {
+	BaseQuery            *BaseQuery
+	FilterSynchronizerID string
+}
+
+type ListSequencerSynchronizerStateResponse struct {
+	Results []*SequencerSynchronizerStateResult
+}
+
+type SequencerSynchronizerStateResult struct {
+	Context *BaseResult
+	Item    *SequencerSynchronizerState
+}
+
+type SequencerSynchronizerState struct {
+	SynchronizerID string
+	Threshold      uint32
+	Active         []string
+	Observers      []string
+}
+
+type ListAllRequest struct {
+	BaseQuery       *BaseQuery
+	ExcludeMappings []string
+	FilterNamespace string
+}
+
+type ListAllResponse struct {
+	Transactions []*StoredTopologyTransaction
+}
+
+type StoredTopologyTransaction struct {
+	Sequenced       *time.Time
+	ValidFrom       *time.Time
+	ValidUntil      *time.Time
+	Transaction     []byte
+	RejectionReason *string
+}
+
+type ExportTopologySnapshotRequest struct {
+	BaseQuery       *BaseQuery
+	ExcludeMappings []string
+	FilterNamespace string
+}
@@ -0,0 +1,289 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// ErrCommandIDSet is the error of the results of submitted commands that already have a command ID,
+// which the batch submitter would otherwise replace by its own.
+var ErrCommandIDSet = errors.New("command ID is assigned by the batch submitter")
+
+// BatchResult is the outcome of one transaction submitted by a BatchSubmitter.
+type BatchResult struct {
+	// Index is the position of the transaction in the batch, which its command ID is derived from
+	Index     int
+	CommandID string
+	// Items are the positions of the submitted commands packed into the transaction
+	Items    []int
+	Response *model.SubmitAndWaitResponse
+	Err      error
+}
+
+// BatchProgress counts the submitted commands of a batch. Total is 0 for batches read from a channel.
+type BatchProgress struct {
+	Total     int
+	Submitted int
+	Succeeded int
+	Failed    int
+}
+
+// BatchSubmitter submits many commands concurrently through CommandService.SubmitAndWait, with a
+// bounded number of transactions in flight.
+type BatchSubmitter struct {
+	commandService   CommandService
+	maxInFlight      int
+	commandsPerTx    int
+	commandIDPrefix  string
+	progressCallback func(BatchProgress)
+}
+
+type BatchSubmitterOption func(*BatchSubmitter)
+
+// WithMaxInFlight limits the number of transactions awaiting their completion, 10 by default.
+func WithMaxInFlight(maxInFlight int) BatchSubmitterOption {
+	return func(b *BatchSubmitter) {
+		b.maxInFlight = maxInFlight
+	}
+}
+
+// WithCommandsPerTransaction packs up to n consecutive submitted commands with the same user,
+// parties, workflow and submission ID into a single transaction. By default every submission is its own transaction.
+func WithCommandsPerTransaction(n int) BatchSubmitterOption {
+	return func(b *BatchSubmitter) {
+		b.commandsPerTx = n
+	}
+}
+
+// WithProgress registers a callback invoked after every completed transaction.
+func WithProgress(callback func(BatchProgress)) BatchSubmitterOption {
+	return func(b *BatchSubmitter) {
+		b.progressCallback = callback
+	}
+}
+
+// NewBatchSubmitter returns a submitter whose command IDs are <commandIDPrefix>-<transaction index>.
+// The prefix identifies the batch: resubmitting a batch with the same prefix after a crash is
+// deduplicated by the ledger, and transactions that were already committed fail with DUPLICATE_COMMAND.
+func NewBatchSubmitter(commandService CommandService, commandIDPrefix string, opts ...BatchSubmitterOption) *BatchSubmitter {
+	b := &BatchSubmitter{
+		commandService:  commandService,
+		maxInFlight:     10,
+		commandsPerTx:   1,
+		commandIDPrefix: commandIDPrefix,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.maxInFlight = max(b.maxInFlight, 1)
+	b.commandsPerTx = max(b.commandsPerTx, 1)
+	return b
+}
+
+// Submit submits all commands and returns the results ordered by transaction index. The error
+// reports how many transactions failed; the individual errors are in the results.
+// The command IDs are assigned by the submitter (see NewBatchSubmitter): commands that already
+// have a CommandID are not submitted, and their result fails with ErrCommandIDSet.
+func (b *BatchSubmitter) Submit(ctx context.Context, commands []*model.Commands) ([]BatchResult, error) {
+	items := make(chan *model.Commands)
+	go func() {
+		defer close(items)
+		for _, cmds := range commands {
+			select {
+			case items <- cmds:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var results []BatchResult
+	failed := 0
+	for result := range b.submit(ctx, items, len(commands)) {
+		if result.Err != nil {
+			failed++
+		}
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d transactions failed", failed, len(results))
+	}
+	return results, nil
+}
+
+// SubmitStream submits the commands read from items until it is closed, and returns a channel
+// of the results in completion order, which is closed once all transactions have completed.
+// The results have to be drained, as submission blocks until they are received.
+// As with Submit, commands that already have a CommandID fail with ErrCommandIDSet.
+func (b *BatchSubmitter) SubmitStream(ctx context.Context, items <-chan *model.Commands) <-chan BatchResult {
+	return b.submit(ctx, items, 0)
+}
+
+type batchTx struct {
+	index    int
+	items    []int
+	commands *model.Commands
+	// err rejects the transaction without submitting it
+	err error
+}
+
+func (b *BatchSubmitter) submit(ctx context.Context, items <-chan *model.Commands, total int) <-chan BatchResult {
+	txs := make(chan batchTx)
+	go func() {
+		defer close(txs)
+		b.pack(ctx, items, txs)
+	}()
+
+	submitted := make(chan BatchResult)
+	var wg sync.WaitGroup
+	for i := 0; i < b.maxInFlight; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tx := range txs {
+				if tx.err != nil {
+					submitted <- BatchResult{Index: tx.index, Items: tx.items, Err: tx.err}
+					continue
+				}
+				resp, err := b.commandService.SubmitAndWait(ctx, &model.SubmitAndWaitRequest{Commands: tx.commands})
+				if err != nil {
+					err = fmt.Errorf("failed to submit transaction %s: %w", tx.commands.CommandID, err)
+				}
+				submitted <- BatchResult{
+					Index:     tx.index,
+					CommandID: tx.commands.CommandID,
+					Items:     tx.items,
+					Response:  resp,
+					Err:       err,
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(submitted)
+	}()
+
+	results := make(chan BatchResult)
+	go func() {
+		defer close(results)
+
+		progress := BatchProgress{Total: total}
+		for result := range submitted {
+			progress.Submitted += len(result.Items)
+			if result.Err != nil {
+				progress.Failed += len(result.Items)
+			} else {
+				progress.Succeeded += len(result.Items)
+			}
+			if b.progressCallback != nil {
+				b.progressCallback(progress)
+			}
+			results <- result
+		}
+	}()
+
+	return results
+}
+
+// pack groups consecutive compatible items into transactions and assigns their command IDs.
+func (b *BatchSubmitter) pack(ctx context.Context, items <-chan *model.Commands, txs chan<- batchTx) {
+	var current *batchTx
+	index, item := 0, 0
+
+	flush := func() bool {
+		if current == nil {
+			return true
+		}
+		current.commands.CommandID = fmt.Sprintf("%s-%d", b.commandIDPrefix, current.index)
+		select {
+		case txs <- *current:
+			current = nil
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		var cmds *model.Commands
+		var ok bool
+		select {
+		case cmds, ok = <-items:
+		case <-ctx.Done():
+			return
+		}
+		if !ok {
+			flush()
+			return
+		}
+
+		if cmds.CommandID != "" {
+			if !flush() {
+				return
+			}
+			rejected := batchTx{
+				index: index,
+				items: []int{item},
+				err:   fmt.Errorf("%w: command %d has command ID %s", ErrCommandIDSet, item, cmds.CommandID),
+			}
+			index++
+			item++
+			select {
+			case txs <- rejected:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if current != nil && (len(current.items) == b.commandsPerTx || !packable(current.commands, cmds)) {
+			if !flush() {
+				return
+			}
+		}
+
+		if current == nil {
+			packed := *cmds
+			packed.Commands = slices.Clone(cmds.Commands)
+			packed.DisclosedContracts = nil
+			current = &batchTx{index: index, commands: &packed}
+			index++
+		} else {
+			current.commands.Commands = append(current.commands.Commands, cmds.Commands...)
+		}
+		// Contracts disclosed by several commands are disclosed once
+		for _, disclosed := range cmds.DisclosedContracts {
+			if !slices.ContainsFunc(current.commands.DisclosedContracts, func(d *model.DisclosedContract) bool {
+				return d.ContractID == disclosed.ContractID
+			}) {
+				current.commands.DisclosedContracts = append(current.commands.DisclosedContracts, disclosed)
+			}
+		}
+		current.items = append(current.items, item)
+		item++
+	}
+}
+
+// packable reports whether the commands of b can be submitted in the same transaction as a.
+func packable(a, b *model.Commands) bool {
+	return a.UserID == b.UserID &&
+		a.WorkflowID == b.WorkflowID &&
+		a.SubmissionID == b.SubmissionID &&
+		slices.Equal(a.ActAs, b.ActAs) &&
+		slices.Equal(a.ReadAs, b.ReadAs) &&
+		a.DeduplicationPeriod == b.DeduplicationPeriod &&
+		a.MinLedgerTimeAbs == nil && b.MinLedgerTimeAbs == nil &&
+		a.MinLedgerTimeRel == nil && b.MinLedgerTimeRel == nil
+}
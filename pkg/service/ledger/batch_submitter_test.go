@@ -0,0 +1,184 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+type fakeCommandService struct {
+	CommandService
+
+	mu          sync.Mutex
+	inFlight    atomic.Int32
+	maxInFlight int32
+	submitted   map[string]*model.Commands
+	fail        func(*model.Commands) error
+}
+
+func (f *fakeCommandService) SubmitAndWait(_ context.Context, req *model.SubmitAndWaitRequest) (*model.SubmitAndWaitResponse, error) {
+	inFlight := f.inFlight.Add(1)
+	defer f.inFlight.Add(-1)
+
+	f.mu.Lock()
+	f.maxInFlight = max(f.maxInFlight, inFlight)
+	if f.submitted == nil {
+		f.submitted = make(map[string]*model.Commands)
+	}
+	f.submitted[req.Commands.CommandID] = req.Commands
+	f.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+	if f.fail != nil {
+		if err := f.fail(req.Commands); err != nil {
+			return nil, err
+		}
+	}
+	return &model.SubmitAndWaitResponse{UpdateID: "update-" + req.Commands.CommandID}, nil
+}
+
+func createCommands(party string, n int) []*model.Commands {
+	commands := make([]*model.Commands, n)
+	for i := range commands {
+		commands[i] = &model.Commands{
+			UserID: "app-provider",
+			ActAs:  []string{party},
+			Commands: []*model.Command{{Command: &model.CreateCommand{
+				TemplateID: "#airdrop:Main:Token",
+				Arguments:  map[string]interface{}{"amount": fmt.Sprint(i)},
+			}}},
+		}
+	}
+	return commands
+}
+
+func TestBatchSubmitter(t *testing.T) {
+	commandService := &fakeCommandService{fail: func(commands *model.Commands) error {
+		if commands.CommandID == "airdrop-7" {
+			return status.Error(codes.InvalidArgument, "COMMAND_PREPROCESSING_FAILED(8,abc): invalid amount")
+		}
+		return nil
+	}}
+
+	var progress []BatchProgress
+	submitter := NewBatchSubmitter(commandService, "airdrop",
+		WithMaxInFlight(4),
+		WithProgress(func(p BatchProgress) { progress = append(progress, p) }),
+	)
+
+	results, err := submitter.Submit(context.Background(), createCommands("alice", 20))
+	require.ErrorContains(t, err, "1 of 20 transactions failed")
+	require.Len(t, results, 20)
+	require.LessOrEqual(t, commandService.maxInFlight, int32(4))
+	require.Greater(t, commandService.maxInFlight, int32(1))
+
+	for i, result := range results {
+		require.Equal(t, i, result.Index)
+		require.Equal(t, fmt.Sprintf("airdrop-%d", i), result.CommandID)
+		require.Equal(t, []int{i}, result.Items)
+		if i == 7 {
+			require.Equal(t, codes.InvalidArgument, status.Code(result.Err))
+			continue
+		}
+		require.NoError(t, result.Err)
+		require.Equal(t, "update-"+result.CommandID, result.Response.UpdateID)
+	}
+
+	require.Len(t, progress, 20)
+	require.Equal(t, BatchProgress{Total: 20, Submitted: 20, Succeeded: 19, Failed: 1}, progress[19])
+}
+
+func TestBatchSubmitterPacksCommands(t *testing.T) {
+	commandService := &fakeCommandService{}
+	submitter := NewBatchSubmitter(commandService, "migration", WithCommandsPerTransaction(3))
+
+	// Commands of different parties are never packed together
+	commands := append(createCommands("alice", 4), createCommands("bob", 2)...)
+	results, err := submitter.Submit(context.Background(), commands)
+	require.NoError(t, err)
+
+	items := make([][]int, 0, len(results))
+	for _, result := range results {
+		items = append(items, result.Items)
+	}
+	require.Equal(t, [][]int{{0, 1, 2}, {3}, {4, 5}}, items)
+	require.Len(t, commandService.submitted["migration-0"].Commands, 3)
+	require.Equal(t, []string{"bob"}, commandService.submitted["migration-2"].ActAs)
+
+	// The submitted commands are not modified
+	require.Len(t, commands[0].Commands, 1)
+	require.Empty(t, commands[0].CommandID)
+}
+
+func TestBatchSubmitterPacksDisclosedContracts(t *testing.T) {
+	commandService := &fakeCommandService{}
+	submitter := NewBatchSubmitter(commandService, "disclosed", WithCommandsPerTransaction(3))
+
+	commands := createCommands("alice", 4)
+	commands[0].DisclosedContracts = []*model.DisclosedContract{{ContractID: "00a"}}
+	commands[1].DisclosedContracts = []*model.DisclosedContract{{ContractID: "00a"}, {ContractID: "00b"}}
+	// Commands of other submissions are not packed together
+	commands[3].SubmissionID = "submission-2"
+	results, err := submitter.Submit(context.Background(), commands)
+	require.NoError(t, err)
+
+	items := make([][]int, 0, len(results))
+	for _, result := range results {
+		items = append(items, result.Items)
+	}
+	require.Equal(t, [][]int{{0, 1, 2}, {3}}, items)
+	require.Equal(t, []*model.DisclosedContract{{ContractID: "00a"}, {ContractID: "00b"}}, commandService.submitted["disclosed-0"].DisclosedContracts)
+	require.Equal(t, "submission-2", commandService.submitted["disclosed-1"].SubmissionID)
+}
+
+func TestBatchSubmitterRejectsCommandID(t *testing.T) {
+	commandService := &fakeCommandService{}
+	submitter := NewBatchSubmitter(commandService, "migration", WithCommandsPerTransaction(3))
+
+	commands := createCommands("alice", 4)
+	commands[1].CommandID = "caller-id"
+	results, err := submitter.Submit(context.Background(), commands)
+	require.ErrorContains(t, err, "1 of 3 transactions failed")
+
+	require.Len(t, results, 3)
+	require.Equal(t, []int{0}, results[0].Items)
+	require.Equal(t, []int{1}, results[1].Items)
+	require.ErrorIs(t, results[1].Err, ErrCommandIDSet)
+	require.ErrorContains(t, results[1].Err, "caller-id")
+	require.Empty(t, results[1].CommandID)
+	require.Equal(t, []int{2, 3}, results[2].Items)
+
+	// The rejected command is never submitted under the caller's or a generated command ID
+	require.Len(t, commandService.submitted, 2)
+	require.NotContains(t, commandService.submitted, "caller-id")
+	require.NotContains(t, commandService.submitted, "migration-1")
+}
+
+func TestBatchSubmitterStream(t *testing.T) {
+	submitter := NewBatchSubmitter(&fakeCommandService{}, "stream", WithMaxInFlight(2))
+
+	items := make(chan *model.Commands)
+	go func() {
+		defer close(items)
+		for _, cmds := range createCommands("alice", 5) {
+			items <- cmds
+		}
+	}()
+
+	indexes := map[int]bool{}
+	for result := range submitter.SubmitStream(context.Background(), items) {
+		require.NoError(t, result.Err)
+		require.Equal(t, fmt.Sprintf("stream-%d", result.Index), result.CommandID)
+		indexes[result.Index] = true
+	}
+	require.Len(t, indexes, 5)
+}
@@ -125,5 +125,6 @@ func statusFromProto(pb *rpcstatus.Status) model.Status {
 	return model.StatusError{
 		Code:    pb.Code,
 		Message: pb.Message,
+		Details: pb.Details,
 	}
 }
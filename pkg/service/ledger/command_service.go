@@ -46,7 +46,8 @@ func (c *commandService) SubmitAndWait(ctx context.Context, req *model.SubmitAnd
 func (c *commandService) SubmitAndWaitForTransaction(ctx context.Context, req *model.SubmitAndWaitRequest) (*model.SubmitAndWaitForTransactionResponse, error) {
 	// The request structure for both Wait and WaitForTransaction is identical in terms of commands
 	protoReq := &v2.SubmitAndWaitForTransactionRequest{
-		Commands: commandsToProto(req.Commands),
+		Commands:          commandsToProto(req.Commands),
+		TransactionFormat: transactionFormatToProto(req.TransactionFormat),
 	}
 
 	resp, err := c.client.SubmitAndWaitForTransaction(ctx, protoReq)
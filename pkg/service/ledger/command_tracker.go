@@ -0,0 +1,327 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+var (
+	// ErrCommandTimeout is returned for commands without a completion within their deduplication period.
+	// Such commands may still be executed, unless they are resubmitted with the same command ID.
+	ErrCommandTimeout = errors.New("no completion received for command")
+	// ErrTrackerStopped is returned for commands that were pending when the tracker stopped.
+	ErrTrackerStopped = errors.New("command tracker stopped")
+)
+
+// CommandFuture is the pending completion of a tracked command.
+type CommandFuture struct {
+	commandID    string
+	submissionID string
+	done         chan struct{}
+	timeout      *time.Timer
+	completion   *model.Completion
+	err          error
+}
+
+func (f *CommandFuture) CommandID() string {
+	return f.commandID
+}
+
+// Done is closed once the command has completed, failed or timed out.
+func (f *CommandFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the command completes and returns its completion. Commands rejected by the
+// ledger return the completion together with an error wrapping the gRPC status of the rejection,
+// which can be inspected with errors.AsDamlError.
+func (f *CommandFuture) Wait(ctx context.Context) (*model.Completion, error) {
+	select {
+	case <-f.done:
+		return f.completion, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (f *CommandFuture) resolve(completion *model.Completion, err error) {
+	f.timeout.Stop()
+	f.completion = completion
+	f.err = err
+	close(f.done)
+}
+
+// CommandTracker submits commands and correlates them with their completions, using a single
+// completion stream for a user and set of parties.
+type CommandTracker struct {
+	submission CommandSubmission
+	completion CommandCompletion
+	state      StateService
+	userID     string
+	parties    []string
+
+	completionTimeout time.Duration
+	reconnectBackoff  time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*CommandFuture
+	stopped bool
+
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+type CommandTrackerOption func(*CommandTracker)
+
+// WithCompletionTimeout sets how long to wait for the completion of commands that do not have
+// a deduplication duration, two minutes by default. This includes commands deduplicated by an
+// offset, as the time covered by an offset is not known to the tracker.
+func WithCompletionTimeout(timeout time.Duration) CommandTrackerOption {
+	return func(t *CommandTracker) {
+		t.completionTimeout = timeout
+	}
+}
+
+// WithCompletionReconnectBackoff sets the delay before the completion stream is reopened after it failed.
+func WithCompletionReconnectBackoff(backoff time.Duration) CommandTrackerOption {
+	return func(t *CommandTracker) {
+		t.reconnectBackoff = backoff
+	}
+}
+
+func NewCommandTracker(submission CommandSubmission, completion CommandCompletion, state StateService, userID string, parties []string, opts ...CommandTrackerOption) *CommandTracker {
+	t := &CommandTracker{
+		submission:        submission,
+		completion:        completion,
+		state:             state,
+		userID:            userID,
+		parties:           parties,
+		completionTimeout: 2 * time.Minute,
+		reconnectBackoff:  time.Second,
+		pending:           make(map[string]*CommandFuture),
+		ready:             make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Run streams completions from the ledger end until ctx is cancelled, reopening the stream after
+// transient failures. Pending commands fail with ErrTrackerStopped, or the stream error, when it returns.
+func (t *CommandTracker) Run(ctx context.Context) error {
+	err := t.run(ctx)
+
+	stopErr := ErrTrackerStopped
+	if err != nil {
+		stopErr = fmt.Errorf("%w: %w", ErrTrackerStopped, err)
+	}
+	t.stop(stopErr)
+
+	return err
+}
+
+func (t *CommandTracker) run(ctx context.Context) error {
+	ledgerEnd, err := t.state.GetLedgerEnd(ctx, &model.GetLedgerEndRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get ledger end: %w", err)
+	}
+	offset := ledgerEnd.Offset
+	t.readyOnce.Do(func() { close(t.ready) })
+
+	for {
+		var streamErr error
+		offset, streamErr = t.stream(ctx, offset)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if streamErr != nil && !IsRetryableStreamError(streamErr) {
+			return fmt.Errorf("completion stream from offset %d failed: %w", offset, streamErr)
+		}
+
+		log.Warn().Err(streamErr).Msgf("completion stream interrupted at offset %d, reconnecting in %s", offset, t.reconnectBackoff)
+		select {
+		case <-time.After(t.reconnectBackoff):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// stream resolves the commands completed after offset until the completion stream ends. It returns
+// the offset the stream ended at and the error it failed with.
+func (t *CommandTracker) stream(ctx context.Context, offset int64) (int64, error) {
+	responses, errs := t.completion.CompletionStream(ctx, &model.CompletionStreamRequest{
+		UserID:         t.userID,
+		Parties:        t.parties,
+		BeginExclusive: offset,
+	})
+	if responses == nil {
+		return offset, <-errs
+	}
+
+	for resp := range responses {
+		switch r := resp.Response.(type) {
+		case model.Completion:
+			offset = max(offset, r.Offset)
+			t.complete(r)
+		case model.OffsetCheckpoint:
+			offset = max(offset, r.Offset)
+		}
+	}
+	return offset, <-errs
+}
+
+// Submit submits commands and returns the future of their completion. A command ID is generated
+// if commands has none, and the user ID and acting parties default to those of the tracker.
+// The completion times out after the deduplication duration of the commands, if they have one,
+// and after the completion timeout of the tracker otherwise. Use SubmitWithTimeout to choose
+// the timeout of commands deduplicated by an offset.
+func (t *CommandTracker) Submit(ctx context.Context, commands *model.Commands) (*CommandFuture, error) {
+	timeout := t.completionTimeout
+	if dedup, ok := commands.DeduplicationPeriod.(model.DeduplicationDuration); ok && dedup.Duration > 0 {
+		timeout = dedup.Duration
+	}
+	return t.SubmitWithTimeout(ctx, commands, timeout)
+}
+
+// SubmitWithTimeout is like Submit, but the completion times out after timeout regardless of
+// the deduplication period of the commands.
+func (t *CommandTracker) SubmitWithTimeout(ctx context.Context, commands *model.Commands, timeout time.Duration) (*CommandFuture, error) {
+	if err := t.WaitReady(ctx); err != nil {
+		return nil, err
+	}
+
+	if commands.CommandID == "" {
+		commands.CommandID = uuid.NewString()
+	}
+	if commands.UserID == "" {
+		commands.UserID = t.userID
+	}
+	if len(commands.ActAs) == 0 {
+		commands.ActAs = t.parties
+	}
+
+	future, err := t.track(commands.CommandID, commands.SubmissionID, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := t.submission.Submit(ctx, &model.SubmitRequest{Commands: commands}); err != nil {
+		t.remove(future)
+		return nil, fmt.Errorf("failed to submit command %s: %w", commands.CommandID, err)
+	}
+
+	return future, nil
+}
+
+// Track returns the future of the completion of a command submitted by other means, e.g. by
+// an earlier process. Only completions after the tracker started are observed.
+func (t *CommandTracker) Track(commandID string, timeout time.Duration) (*CommandFuture, error) {
+	return t.track(commandID, "", timeout)
+}
+
+// WaitReady blocks until the tracker observes the completion stream, so that the completions
+// of commands submitted from then on are not missed.
+func (t *CommandTracker) WaitReady(ctx context.Context) error {
+	select {
+	case <-t.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Pending returns the number of commands awaiting their completion.
+func (t *CommandTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+func (t *CommandTracker) track(commandID, submissionID string, timeout time.Duration) (*CommandFuture, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stopped {
+		return nil, ErrTrackerStopped
+	}
+	if _, ok := t.pending[commandID]; ok {
+		return nil, fmt.Errorf("command %s is already being tracked", commandID)
+	}
+
+	future := &CommandFuture{
+		commandID:    commandID,
+		submissionID: submissionID,
+		done:         make(chan struct{}),
+	}
+	t.pending[commandID] = future
+
+	future.timeout = time.AfterFunc(timeout, func() {
+		if t.remove(future) {
+			future.resolve(nil, fmt.Errorf("%w %s within %s", ErrCommandTimeout, commandID, timeout))
+		}
+	})
+
+	return future, nil
+}
+
+// remove stops tracking future and reports whether it was still pending.
+func (t *CommandTracker) remove(future *CommandFuture) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending[future.commandID] != future {
+		return false
+	}
+	delete(t.pending, future.commandID)
+	future.timeout.Stop()
+	return true
+}
+
+func (t *CommandTracker) complete(completion model.Completion) {
+	t.mu.Lock()
+	future, ok := t.pending[completion.CommandID]
+	if !ok || (future.submissionID != "" && completion.SubmissionID != "" && future.submissionID != completion.SubmissionID) {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.pending, completion.CommandID)
+	t.mu.Unlock()
+
+	var err error
+	if st, ok := completion.Status.(model.StatusError); ok {
+		// Keep the details of the status, so that the error can be inspected with errors.AsDamlError
+		err = fmt.Errorf("command %s failed: %w", completion.CommandID, status.FromProto(&rpcstatus.Status{
+			Code:    st.Code,
+			Message: st.Message,
+			Details: st.Details,
+		}).Err())
+	}
+	future.resolve(&completion, err)
+}
+
+func (t *CommandTracker) stop(err error) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[string]*CommandFuture)
+	t.stopped = true
+	t.mu.Unlock()
+
+	// Unblock submissions waiting for a tracker that failed to start
+	t.readyOnce.Do(func() { close(t.ready) })
+
+	for _, future := range pending {
+		future.resolve(nil, err)
+	}
+}
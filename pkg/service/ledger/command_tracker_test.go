@@ -0,0 +1,231 @@
+package ledger
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	damlerrors "github.com/smartcontractkit/go-daml/pkg/errors"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// fakeLedger accepts submissions and completes them on the completion stream with the status
+// returned by complete, or not at all if complete returns nil.
+type fakeLedger struct {
+	StateService
+	complete func(commands *model.Commands) model.Status
+
+	mu          sync.Mutex
+	offset      int64
+	submitted   []*model.Commands
+	begins      []int64
+	completions chan *model.CompletionStreamResponse
+	failStream  chan error
+}
+
+func newFakeLedger(complete func(*model.Commands) model.Status) *fakeLedger {
+	return &fakeLedger{
+		complete:    complete,
+		offset:      100,
+		completions: make(chan *model.CompletionStreamResponse, 16),
+		failStream:  make(chan error, 1),
+	}
+}
+
+func (f *fakeLedger) GetLedgerEnd(context.Context, *model.GetLedgerEndRequest) (*model.GetLedgerEndResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &model.GetLedgerEndResponse{Offset: f.offset}, nil
+}
+
+func (f *fakeLedger) Submit(_ context.Context, req *model.SubmitRequest) (*model.SubmitResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.submitted = append(f.submitted, req.Commands)
+	if st := f.complete(req.Commands); st != nil {
+		f.offset++
+		f.completions <- &model.CompletionStreamResponse{Response: model.Completion{
+			CommandID:    req.Commands.CommandID,
+			SubmissionID: req.Commands.SubmissionID,
+			UpdateID:     "update-" + req.Commands.CommandID,
+			Offset:       f.offset,
+			Status:       st,
+		}}
+	}
+	return &model.SubmitResponse{}, nil
+}
+
+func (f *fakeLedger) CompletionStream(ctx context.Context, req *model.CompletionStreamRequest) (<-chan *model.CompletionStreamResponse, <-chan error) {
+	f.mu.Lock()
+	f.begins = append(f.begins, req.BeginExclusive)
+	f.mu.Unlock()
+
+	responseCh := make(chan *model.CompletionStreamResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(responseCh)
+		defer close(errCh)
+		for {
+			select {
+			case resp := <-f.completions:
+				select {
+				case responseCh <- resp:
+				case <-ctx.Done():
+					return
+				}
+			case err := <-f.failStream:
+				errCh <- err
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return responseCh, errCh
+}
+
+func startTracker(t *testing.T, ledger *fakeLedger, opts ...CommandTrackerOption) (*CommandTracker, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	tracker := NewCommandTracker(ledger, ledger, ledger, "app-provider", []string{"alice"}, opts...)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- tracker.Run(ctx) }()
+	t.Cleanup(cancel)
+
+	return tracker, cancel, done
+}
+
+func TestCommandTrackerCompletes(t *testing.T) {
+	inFlight, err := status.New(codes.Aborted, "an opaque message").WithDetails(
+		&errdetails.ErrorInfo{Reason: "SUBMISSION_ALREADY_IN_FLIGHT", Metadata: map[string]string{"category": "2"}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)},
+	)
+	require.NoError(t, err)
+
+	ledger := newFakeLedger(func(commands *model.Commands) model.Status {
+		switch commands.CommandID {
+		case "rejected":
+			return model.StatusError{Code: int32(codes.NotFound), Message: "CONTRACT_NOT_FOUND(11,abc123): Contract could not be found with id 00abc"}
+		case "in-flight":
+			pb := inFlight.Proto()
+			return model.StatusError{Code: pb.Code, Message: pb.Message, Details: pb.Details}
+		}
+		return model.StatusOK{}
+	})
+	tracker, _, _ := startTracker(t, ledger)
+	ctx := context.Background()
+
+	future, err := tracker.Submit(ctx, &model.Commands{})
+	require.NoError(t, err)
+	completion, err := future.Wait(ctx)
+	require.NoError(t, err)
+	require.Equal(t, future.CommandID(), completion.CommandID)
+	require.Equal(t, "update-"+future.CommandID(), completion.UpdateID)
+	require.Equal(t, int64(101), completion.Offset)
+	require.Equal(t, "app-provider", ledger.submitted[0].UserID)
+	require.Equal(t, []string{"alice"}, ledger.submitted[0].ActAs)
+	// The completion timeout is stopped with the completion
+	require.False(t, future.timeout.Stop())
+
+	future, err = tracker.Submit(ctx, &model.Commands{CommandID: "rejected"})
+	require.NoError(t, err)
+	completion, err = future.Wait(ctx)
+	require.NotNil(t, completion)
+	require.ErrorIs(t, damlerrors.AsDamlError(err), damlerrors.ErrContractNotFound)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Zero(t, tracker.Pending())
+
+	// The error details of rejections are kept
+	future, err = tracker.Submit(ctx, &model.Commands{CommandID: "in-flight"})
+	require.NoError(t, err)
+	_, err = future.Wait(ctx)
+	damlErr := damlerrors.AsDamlError(err)
+	require.Equal(t, "SUBMISSION_ALREADY_IN_FLIGHT", damlErr.ErrorCode)
+	require.Equal(t, damlerrors.CategoryContentionOnSharedResources, damlErr.Category())
+	require.True(t, damlErr.Retryable)
+	require.Equal(t, 2*time.Second, damlErr.RetryDelay)
+
+	_, err = tracker.Track("rejected", time.Minute)
+	require.NoError(t, err)
+	_, err = tracker.Track("rejected", time.Minute)
+	require.ErrorContains(t, err, "already being tracked")
+}
+
+func TestCommandTrackerTimesOutAfterDeduplicationPeriod(t *testing.T) {
+	ledger := newFakeLedger(func(*model.Commands) model.Status { return nil })
+	tracker, _, _ := startTracker(t, ledger)
+
+	future, err := tracker.Submit(context.Background(), &model.Commands{
+		CommandID:           "lost",
+		DeduplicationPeriod: model.DeduplicationDuration{Duration: 20 * time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	_, err = future.Wait(context.Background())
+	require.ErrorIs(t, err, ErrCommandTimeout)
+	require.Zero(t, tracker.Pending())
+}
+
+func TestCommandTrackerSubmitWithTimeout(t *testing.T) {
+	ledger := newFakeLedger(func(*model.Commands) model.Status { return nil })
+	tracker, _, _ := startTracker(t, ledger, WithCompletionTimeout(time.Hour))
+
+	// Offsets do not tell how long deduplication lasts, so the timeout is given explicitly
+	future, err := tracker.SubmitWithTimeout(context.Background(), &model.Commands{
+		CommandID:           "lost",
+		DeduplicationPeriod: model.DeduplicationOffset{Offset: 100},
+	}, 20*time.Millisecond)
+	require.NoError(t, err)
+
+	_, err = future.Wait(context.Background())
+	require.ErrorIs(t, err, ErrCommandTimeout)
+	require.Zero(t, tracker.Pending())
+}
+
+func TestCommandTrackerReconnects(t *testing.T) {
+	var completeNext bool
+	ledger := newFakeLedger(func(*model.Commands) model.Status {
+		if completeNext {
+			return model.StatusOK{}
+		}
+		return nil
+	})
+	tracker, cancel, done := startTracker(t, ledger, WithCompletionReconnectBackoff(time.Millisecond))
+	ctx := context.Background()
+
+	first, err := tracker.Submit(ctx, &model.Commands{CommandID: "first"})
+	require.NoError(t, err)
+
+	ledger.failStream <- status.Error(codes.Unavailable, "participant restarting")
+	require.Eventually(t, func() bool {
+		ledger.mu.Lock()
+		defer ledger.mu.Unlock()
+		return len(ledger.begins) == 2
+	}, 5*time.Second, time.Millisecond)
+
+	ledger.mu.Lock()
+	completeNext = true
+	ledger.mu.Unlock()
+	second, err := tracker.Submit(ctx, &model.Commands{CommandID: "second"})
+	require.NoError(t, err)
+	_, err = second.Wait(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{100, 100}, ledger.begins)
+
+	cancel()
+	require.NoError(t, <-done)
+	_, err = first.Wait(ctx)
+	require.ErrorIs(t, err, ErrTrackerStopped)
+
+	_, err = tracker.Submit(ctx, &model.Commands{CommandID: "third"})
+	require.ErrorIs(t, err, ErrTrackerStopped)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/smartcontractkit/go-daml/pkg/codec"
 	"github.com/smartcontractkit/go-daml/pkg/model"
 	"github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/smartcontractkit/go-daml/pkg/value"
 )
 
 var defaultJsonCodec = codec.NewJsonCodec()
@@ -247,6 +248,16 @@ func eventFormatToProto(format *model.EventFormat) *v2.EventFormat {
 	}
 }
 
+func transactionFormatToProto(format *model.TransactionFormat) *v2.TransactionFormat {
+	if format == nil {
+		return nil
+	}
+	return &v2.TransactionFormat{
+		EventFormat:      eventFormatToProto(format.EventFormat),
+		TransactionShape: v2.TransactionShape(format.TransactionShape),
+	}
+}
+
 func updateFormatToProto(format *model.EventFormat) *v2.UpdateFormat {
 	if format == nil {
 		return nil
@@ -552,6 +563,8 @@ func mapToValue(data interface{}) *v2.Value {
 
 	// handle custom pointer types first before dereferencing
 	switch v := data.(type) {
+	case value.Value:
+		return value.ToProto(v)
 	case decimal.Decimal:
 		scaled := types.NewNumericFromDecimal(v)
 		s, ok := normalizeLedgerNumericLiteral(string(scaled))
@@ -1095,6 +1108,10 @@ func convertToRecord(data any) *v2.Record {
 		rv = reflect.ValueOf(data)
 	}
 
+	if r, ok := data.(value.Record); ok {
+		return value.RecordToProto(r)
+	}
+
 	// If it's a struct, convert it to map using json tags (existing helper)
 	if rv.Kind() == reflect.Struct {
 		return MapToRecord(structToMap(data))
@@ -1213,6 +1230,42 @@ func MapToStruct(data map[string]interface{}, target interface{}) error {
 	return nil
 }
 
+// ValueToStruct decodes a single ledger value into target. data may be a raw *v2.Value
+// or a value already converted by the event converters, such as ExercisedEvent.ExerciseResult.
+func ValueToStruct(data interface{}, target interface{}) error {
+	if data == nil {
+		return nil
+	}
+
+	if target == nil {
+		return fmt.Errorf("target cannot be nil")
+	}
+
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr {
+		return fmt.Errorf("target must be a pointer, got %T", target)
+	}
+
+	if rv.IsNil() {
+		return fmt.Errorf("target pointer cannot be nil")
+	}
+
+	if pb, ok := data.(*v2.Value); ok {
+		data = valueFromProtoForStruct(pb)
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value to JSON: %w", err)
+	}
+
+	if err := defaultJsonCodec.Unmarshal(jsonData, target); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON to value (target type: %T): %w", target, err)
+	}
+
+	return nil
+}
+
 func prepareSubmissionRequestToProto(req *model.PrepareSubmissionRequest) *interactive.PrepareSubmissionRequest {
 	if req == nil {
 		return nil
@@ -8,6 +8,7 @@ import (
 	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
 	"github.com/smartcontractkit/go-daml/pkg/model"
 	"github.com/smartcontractkit/go-daml/pkg/types"
+	"github.com/smartcontractkit/go-daml/pkg/value"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -151,6 +152,25 @@ func TestMapToValueVariantWithUnitPayload(t *testing.T) {
 	require.IsType(t, &v2.Value_Unit{}, value.GetVariant().GetValue().GetSum())
 }
 
+func TestConvertToRecordDamlValue(t *testing.T) {
+	record := value.NewRecord(
+		value.Field{Label: "owner", Value: value.Party("alice::1220aa")},
+		value.Field{Label: "amount", Value: value.Numeric{Unscaled: big.NewInt(150), Scale: 2}},
+		value.Field{Label: "memo", Value: value.None()},
+	)
+
+	pb := convertToRecord(record)
+	require.Equal(t, value.RecordToProto(record), pb)
+	require.Equal(t, "1.50", pb.Fields[1].Value.GetNumeric())
+
+	require.Equal(t, value.ToProto(value.Text("hello")), mapToValue(value.Text("hello")))
+
+	// Pointers to values convert like the values they point to
+	require.Equal(t, value.RecordToProto(record), convertToRecord(&record))
+	require.Equal(t, value.ToProto(record), mapToValue(&record))
+	require.Nil(t, mapToValue((*value.Record)(nil)))
+}
+
 // Verify interface implementation
 var (
 	_ types.VARIANT = (*VPairTest)(nil)
@@ -2,11 +2,14 @@ package ledger
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2/interactive"
+	"github.com/smartcontractkit/go-daml/pkg/crypto"
 	"github.com/smartcontractkit/go-daml/pkg/model"
 )
 
@@ -75,3 +78,74 @@ func (c *interactiveSubmissionService) GetPreferredPackageVersion(ctx context.Co
 
 	return resp, nil
 }
+
+// PartySigner is a signer of an external party. Parties with a signing threshold above one
+// are listed once for every key.
+type PartySigner struct {
+	Party  string
+	Signer crypto.Signer
+}
+
+// PrepareSignExecute prepares the commands of req, verifies the prepared transaction hash,
+// signs it with signers and executes the signed transaction. It returns the prepared transaction,
+// whose completion is reported on the completion stream under the command ID of req.
+func PrepareSignExecute(ctx context.Context, service InteractiveSubmissionService, req *model.PrepareSubmissionRequest, signers ...PartySigner) (*model.PrepareSubmissionResponse, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("at least one party signer is required")
+	}
+
+	prepared, err := service.PrepareSubmission(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare submission: %w", err)
+	}
+
+	if _, err := VerifyPreparedTransaction(prepared); err != nil {
+		return nil, err
+	}
+
+	partySignatures, err := SignPreparedTransaction(ctx, prepared, signers...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = service.ExecuteSubmission(ctx, &model.ExecuteSubmissionRequest{
+		PreparedTransaction:  prepared.PreparedTransaction,
+		PartySignatures:      partySignatures,
+		SubmissionID:         uuid.NewString(),
+		UserID:               req.UserID,
+		HashingSchemeVersion: prepared.HashingSchemeVersion,
+		MinLedgerTime:        req.MinLedgerTime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute submission: %w", err)
+	}
+
+	return prepared, nil
+}
+
+// SignPreparedTransaction signs the hash of a prepared transaction with signers, grouping the
+// signatures by party in the order the parties are first listed.
+func SignPreparedTransaction(ctx context.Context, prepared *model.PrepareSubmissionResponse, signers ...PartySigner) ([]*model.SinglePartySignatures, error) {
+	if len(prepared.PreparedTransactionHash) == 0 {
+		return nil, fmt.Errorf("prepared transaction has no hash")
+	}
+
+	var partySignatures []*model.SinglePartySignatures
+	byParty := make(map[string]*model.SinglePartySignatures)
+	for _, s := range signers {
+		signature, err := s.Signer.Sign(ctx, prepared.PreparedTransactionHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign prepared transaction for %s: %w", s.Party, err)
+		}
+
+		signatures, ok := byParty[s.Party]
+		if !ok {
+			signatures = &model.SinglePartySignatures{Party: s.Party}
+			byParty[s.Party] = signatures
+			partySignatures = append(partySignatures, signatures)
+		}
+		signatures.Signatures = append(signatures.Signatures, signature)
+	}
+
+	return partySignatures, nil
+}
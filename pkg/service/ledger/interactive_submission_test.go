@@ -0,0 +1,79 @@
+package ledger
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/go-daml/pkg/crypto"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+type fakeInteractiveSubmissionService struct {
+	InteractiveSubmissionService
+	prepared *model.PrepareSubmissionResponse
+	executed *model.ExecuteSubmissionRequest
+}
+
+func (f *fakeInteractiveSubmissionService) PrepareSubmission(context.Context, *model.PrepareSubmissionRequest) (*model.PrepareSubmissionResponse, error) {
+	return f.prepared, nil
+}
+
+func (f *fakeInteractiveSubmissionService) ExecuteSubmission(_ context.Context, req *model.ExecuteSubmissionRequest) (*model.ExecuteSubmissionResponse, error) {
+	f.executed = req
+	return &model.ExecuteSubmissionResponse{}, nil
+}
+
+func TestPrepareSignExecute(t *testing.T) {
+	_, aliceKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	bobKey1, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	bobKey2, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	alice := crypto.NewEd25519Signer(aliceKey)
+	bob1, err := crypto.NewECDSASigner(bobKey1)
+	require.NoError(t, err)
+	bob2, err := crypto.NewECDSASigner(bobKey2)
+	require.NoError(t, err)
+
+	service := &fakeInteractiveSubmissionService{prepared: prepareResponse(t, preparedTransfer())}
+	prepared, err := PrepareSignExecute(context.Background(), service,
+		&model.PrepareSubmissionRequest{UserID: "wallet", CommandID: "cmd-1", ActAs: []string{"alice", "bob"}},
+		PartySigner{Party: "bob", Signer: bob1},
+		PartySigner{Party: "alice", Signer: alice},
+		PartySigner{Party: "bob", Signer: bob2},
+	)
+	require.NoError(t, err)
+	require.Equal(t, service.prepared, prepared)
+
+	executed := service.executed
+	require.Equal(t, prepared.PreparedTransaction, executed.PreparedTransaction)
+	require.Equal(t, "wallet", executed.UserID)
+	require.Equal(t, model.HashingSchemeVersionV2, executed.HashingSchemeVersion)
+	require.NotEmpty(t, executed.SubmissionID)
+
+	require.Len(t, executed.PartySignatures, 2)
+	require.Equal(t, "bob", executed.PartySignatures[0].Party)
+	require.Len(t, executed.PartySignatures[0].Signatures, 2)
+	require.Equal(t, bob1.Fingerprint(), executed.PartySignatures[0].Signatures[0].SignedBy)
+	require.Equal(t, model.SigningAlgorithmSpecECDSASHA384, executed.PartySignatures[0].Signatures[1].SigningAlgorithmSpec)
+	require.Equal(t, "alice", executed.PartySignatures[1].Party)
+	require.True(t, ed25519.Verify(alice.PublicKey().(ed25519.PublicKey), prepared.PreparedTransactionHash, executed.PartySignatures[1].Signatures[0].Signature))
+
+	_, err = PrepareSignExecute(context.Background(), service, &model.PrepareSubmissionRequest{})
+	require.ErrorContains(t, err, "at least one party signer is required")
+
+	// Transactions whose hash does not match are not signed
+	service.executed = nil
+	service.prepared.PreparedTransactionHash = make([]byte, 32)
+	_, err = PrepareSignExecute(context.Background(), service, &model.PrepareSubmissionRequest{}, PartySigner{Party: "alice", Signer: alice})
+	require.ErrorIs(t, err, ErrPreparedTransactionHashMismatch)
+	require.Nil(t, service.executed)
+}
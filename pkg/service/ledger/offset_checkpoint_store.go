@@ -0,0 +1,102 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OffsetCheckpointStore persists the offset an UpdateSubscriber has delivered updates up to,
+// so that a restarted subscriber resumes where the previous one stopped.
+type OffsetCheckpointStore interface {
+	// Load returns the last saved offset, or 0 if no offset has been saved yet.
+	Load(ctx context.Context) (int64, error)
+	Save(ctx context.Context, offset int64) error
+}
+
+type memoryCheckpointStore struct {
+	mu     sync.Mutex
+	offset int64
+}
+
+// NewMemoryCheckpointStore returns a store that keeps the offset in memory, starting at offset.
+func NewMemoryCheckpointStore(offset int64) *memoryCheckpointStore {
+	return &memoryCheckpointStore{
+		offset: offset,
+	}
+}
+
+func (s *memoryCheckpointStore) Load(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset, nil
+}
+
+func (s *memoryCheckpointStore) Save(_ context.Context, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset = offset
+	return nil
+}
+
+type fileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpointStore returns a store that keeps the offset in the file at path.
+// The file is replaced atomically on every save.
+func NewFileCheckpointStore(path string) *fileCheckpointStore {
+	return &fileCheckpointStore{
+		path: path,
+	}
+}
+
+func (s *fileCheckpointStore) Load(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read offset checkpoint %s: %w", s.path, err)
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset checkpoint in %s: %w", s.path, err)
+	}
+
+	return offset, nil
+}
+
+func (s *fileCheckpointStore) Save(_ context.Context, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to save offset checkpoint %s: %w", s.path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.FormatInt(offset, 10) + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save offset checkpoint %s: %w", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save offset checkpoint %s: %w", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save offset checkpoint %s: %w", s.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+	"github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2/interactive"
+	v1 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2/interactive/transaction/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// ErrPreparedTransactionHashMismatch is returned when the hash of a prepared transaction
+// recomputed locally differs from the hash returned by the participant.
+var ErrPreparedTransactionHashMismatch = errors.New("prepared transaction hash mismatch")
+
+type PreparedNodeKind string
+
+const (
+	PreparedNodeCreate   PreparedNodeKind = "create"
+	PreparedNodeExercise PreparedNodeKind = "exercise"
+	PreparedNodeFetch    PreparedNodeKind = "fetch"
+	PreparedNodeRollback PreparedNodeKind = "rollback"
+)
+
+// PreparedNode is a node of a prepared transaction. Depth is 0 for root nodes and increases
+// for the children of exercise and rollback nodes.
+type PreparedNode struct {
+	NodeID        string
+	Kind          PreparedNodeKind
+	Depth         int
+	ContractID    string
+	TemplateID    string
+	InterfaceID   string
+	Choice        string
+	Consuming     bool
+	Signatories   []string
+	Stakeholders  []string
+	ActingParties []string
+}
+
+// PreparedTransactionSummary describes what a prepared transaction does, for review before
+// it is signed.
+type PreparedTransactionSummary struct {
+	CommandID              string
+	ActAs                  []string
+	SynchronizerID         string
+	PreparationTime        time.Time
+	MinLedgerEffectiveTime *time.Time
+	MaxLedgerEffectiveTime *time.Time
+	InputContracts         []string
+	// Nodes are listed depth-first, in execution order
+	Nodes []PreparedNode
+}
+
+// String renders the summary with one line per node, indented by depth.
+func (s *PreparedTransactionSummary) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "command %s acting as %s on synchronizer %s, prepared at %s\n",
+		s.CommandID, strings.Join(s.ActAs, ", "), s.SynchronizerID, s.PreparationTime.UTC().Format(time.RFC3339))
+
+	for _, node := range s.Nodes {
+		sb.WriteString(strings.Repeat("  ", node.Depth+1))
+		switch node.Kind {
+		case PreparedNodeCreate:
+			fmt.Fprintf(&sb, "create %s %s signed by %s", node.TemplateID, node.ContractID, strings.Join(node.Signatories, ", "))
+		case PreparedNodeExercise:
+			consuming := "non-consuming"
+			if node.Consuming {
+				consuming = "consuming"
+			}
+			fmt.Fprintf(&sb, "exercise %s %s on %s %s by %s", consuming, node.Choice, node.TemplateID, node.ContractID, strings.Join(node.ActingParties, ", "))
+		case PreparedNodeFetch:
+			fmt.Fprintf(&sb, "fetch %s %s by %s", node.TemplateID, node.ContractID, strings.Join(node.ActingParties, ", "))
+		case PreparedNodeRollback:
+			sb.WriteString("rollback")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// HashPreparedTransaction recomputes the hash of a serialized prepared transaction. Only
+// HashingSchemeVersionV2 is supported.
+func HashPreparedTransaction(preparedTransaction []byte, version model.HashingSchemeVersion) ([]byte, error) {
+	if version != model.HashingSchemeVersionV2 {
+		return nil, fmt.Errorf("unsupported hashing scheme version %d", version)
+	}
+
+	prepared, err := unmarshalPreparedTransaction(preparedTransaction)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hashPreparedTransactionV2(prepared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash prepared transaction: %w", err)
+	}
+	return hash, nil
+}
+
+// VerifyPreparedTransaction recomputes the hash of the prepared transaction and compares it to
+// the hash returned by the participant, which is what external parties sign. It returns the
+// summary of the transaction, or ErrPreparedTransactionHashMismatch if the hashes differ.
+func VerifyPreparedTransaction(prepared *model.PrepareSubmissionResponse) (*PreparedTransactionSummary, error) {
+	hash, err := HashPreparedTransaction(prepared.PreparedTransaction, prepared.HashingSchemeVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hash, prepared.PreparedTransactionHash) {
+		return nil, fmt.Errorf("%w: computed %x, participant returned %x", ErrPreparedTransactionHashMismatch, hash, prepared.PreparedTransactionHash)
+	}
+	return SummarizePreparedTransaction(prepared.PreparedTransaction)
+}
+
+// SummarizePreparedTransaction decodes a serialized prepared transaction into a summary of its nodes.
+func SummarizePreparedTransaction(preparedTransaction []byte) (*PreparedTransactionSummary, error) {
+	prepared, err := unmarshalPreparedTransaction(preparedTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PreparedTransactionSummary{}
+	if metadata := prepared.Metadata; metadata != nil {
+		summary.CommandID = metadata.GetSubmitterInfo().GetCommandId()
+		summary.ActAs = metadata.GetSubmitterInfo().GetActAs()
+		summary.SynchronizerID = metadata.SynchronizerId
+		summary.PreparationTime = microsToTime(metadata.PreparationTime)
+		if metadata.MinLedgerEffectiveTime != nil {
+			t := microsToTime(*metadata.MinLedgerEffectiveTime)
+			summary.MinLedgerEffectiveTime = &t
+		}
+		if metadata.MaxLedgerEffectiveTime != nil {
+			t := microsToTime(*metadata.MaxLedgerEffectiveTime)
+			summary.MaxLedgerEffectiveTime = &t
+		}
+		for _, contract := range metadata.InputContracts {
+			summary.InputContracts = append(summary.InputContracts, contract.GetV1().GetContractId())
+		}
+	}
+
+	if prepared.Transaction != nil {
+		e, err := newHashEncoder(prepared.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.summarize(summary, prepared.Transaction.Roots, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+func (e *hashEncoder) summarize(summary *PreparedTransactionSummary, nodeIDs []string, depth int) error {
+	for _, nodeID := range nodeIDs {
+		node, ok := e.nodes[nodeID]
+		if !ok {
+			return fmt.Errorf("node %s not found", nodeID)
+		}
+
+		var children []string
+		summarized := PreparedNode{NodeID: nodeID, Depth: depth}
+		switch n := node.NodeType.(type) {
+		case *v1.Node_Create:
+			summarized.Kind = PreparedNodeCreate
+			summarized.ContractID = n.Create.ContractId
+			summarized.TemplateID = identifierString(n.Create.TemplateId)
+			summarized.Signatories = n.Create.Signatories
+			summarized.Stakeholders = n.Create.Stakeholders
+		case *v1.Node_Exercise:
+			summarized.Kind = PreparedNodeExercise
+			summarized.ContractID = n.Exercise.ContractId
+			summarized.TemplateID = identifierString(n.Exercise.TemplateId)
+			summarized.InterfaceID = identifierString(n.Exercise.InterfaceId)
+			summarized.Choice = n.Exercise.ChoiceId
+			summarized.Consuming = n.Exercise.Consuming
+			summarized.Signatories = n.Exercise.Signatories
+			summarized.Stakeholders = n.Exercise.Stakeholders
+			summarized.ActingParties = n.Exercise.ActingParties
+			children = n.Exercise.Children
+		case *v1.Node_Fetch:
+			summarized.Kind = PreparedNodeFetch
+			summarized.ContractID = n.Fetch.ContractId
+			summarized.TemplateID = identifierString(n.Fetch.TemplateId)
+			summarized.InterfaceID = identifierString(n.Fetch.InterfaceId)
+			summarized.Signatories = n.Fetch.Signatories
+			summarized.Stakeholders = n.Fetch.Stakeholders
+			summarized.ActingParties = n.Fetch.ActingParties
+		case *v1.Node_Rollback:
+			summarized.Kind = PreparedNodeRollback
+			children = n.Rollback.Children
+		default:
+			return fmt.Errorf("node %s has an unknown type %T", nodeID, node.NodeType)
+		}
+
+		summary.Nodes = append(summary.Nodes, summarized)
+		if err := e.summarize(summary, children, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unmarshalPreparedTransaction(preparedTransaction []byte) (*interactive.PreparedTransaction, error) {
+	prepared := &interactive.PreparedTransaction{}
+	if err := proto.Unmarshal(preparedTransaction, prepared); err != nil {
+		return nil, fmt.Errorf("failed to decode prepared transaction: %w", err)
+	}
+	return prepared, nil
+}
+
+func identifierString(id *v2.Identifier) string {
+	if id == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%s", id.PackageId, id.ModuleName, id.EntityName)
+}
+
+func microsToTime(micros uint64) time.Time {
+	return time.UnixMicro(int64(micros)).UTC()
+}
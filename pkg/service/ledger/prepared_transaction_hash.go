@@ -0,0 +1,392 @@
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+	"github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2/interactive"
+	v1 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2/interactive/transaction/v1"
+)
+
+var (
+	// hashPurposePreparedSubmission prefixes the hashes of prepared transactions and their parts
+	hashPurposePreparedSubmission = []byte{0x00, 0x00, 0x00, 0x30}
+	hashingSchemeVersionV2        = byte(0x02)
+	nodeEncodingVersion           = byte(0x01)
+	metadataEncodingVersion       = byte(0x01)
+)
+
+const (
+	createNodeTag   = byte(0x00)
+	exerciseNodeTag = byte(0x01)
+	fetchNodeTag    = byte(0x02)
+	rollbackNodeTag = byte(0x03)
+)
+
+// hashPreparedTransactionV2 computes the hash of a prepared transaction according to hashing
+// scheme version 2, which is the hash external parties sign.
+func hashPreparedTransactionV2(prepared *interactive.PreparedTransaction) ([]byte, error) {
+	if prepared.Transaction == nil || prepared.Metadata == nil {
+		return nil, fmt.Errorf("prepared transaction has no transaction or metadata")
+	}
+
+	e, err := newHashEncoder(prepared.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionHash, err := e.hashTransaction(prepared.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	metadataHash, err := hashMetadata(prepared.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	h.Write(hashPurposePreparedSubmission)
+	h.Write([]byte{hashingSchemeVersionV2})
+	h.Write(transactionHash)
+	h.Write(metadataHash)
+	return h.Sum(nil), nil
+}
+
+// hashEncoder encodes the parts of a prepared transaction into the byte strings that are hashed.
+type hashEncoder struct {
+	nodes map[string]*v1.Node
+	seeds map[string][]byte
+}
+
+func newHashEncoder(tx *interactive.DamlTransaction) (*hashEncoder, error) {
+	e := &hashEncoder{
+		nodes: make(map[string]*v1.Node, len(tx.Nodes)),
+		seeds: make(map[string][]byte, len(tx.NodeSeeds)),
+	}
+	for _, node := range tx.Nodes {
+		v1Node, ok := node.VersionedNode.(*interactive.DamlTransaction_Node_V1)
+		if !ok || v1Node.V1 == nil {
+			return nil, fmt.Errorf("node %s has an unsupported version", node.NodeId)
+		}
+		e.nodes[node.NodeId] = v1Node.V1
+	}
+	for _, seed := range tx.NodeSeeds {
+		e.seeds[strconv.Itoa(int(seed.NodeId))] = seed.Seed
+	}
+	return e, nil
+}
+
+func (e *hashEncoder) hashTransaction(tx *interactive.DamlTransaction) ([]byte, error) {
+	b := append([]byte{}, hashPurposePreparedSubmission...)
+	b = encodeString(b, tx.Version)
+	b, err := e.encodeNodeIDs(b, tx.Roots)
+	if err != nil {
+		return nil, err
+	}
+	return sha256Sum(b), nil
+}
+
+func hashMetadata(metadata *interactive.Metadata) ([]byte, error) {
+	b := append([]byte{}, hashPurposePreparedSubmission...)
+	b = append(b, metadataEncodingVersion)
+
+	var actAs []string
+	var commandID string
+	if metadata.SubmitterInfo != nil {
+		actAs, commandID = metadata.SubmitterInfo.ActAs, metadata.SubmitterInfo.CommandId
+	}
+	b = encodeStrings(b, actAs)
+	b = encodeString(b, commandID)
+	b = encodeString(b, metadata.TransactionUuid)
+	b = binary.BigEndian.AppendUint32(b, metadata.MediatorGroup)
+	b = encodeString(b, metadata.SynchronizerId)
+	b = encodeOptionalTime(b, metadata.MinLedgerEffectiveTime)
+	b = encodeOptionalTime(b, metadata.MaxLedgerEffectiveTime)
+	b = binary.BigEndian.AppendUint64(b, metadata.PreparationTime)
+
+	b = binary.BigEndian.AppendUint32(b, uint32(len(metadata.InputContracts)))
+	for _, contract := range metadata.InputContracts {
+		v1Contract, ok := contract.Contract.(*interactive.Metadata_InputContract_V1)
+		if !ok || v1Contract.V1 == nil {
+			return nil, fmt.Errorf("input contract has an unsupported version")
+		}
+		b = binary.BigEndian.AppendUint64(b, contract.CreatedAt)
+		// Input contracts are hashed as create nodes without a seed
+		create, err := encodeCreate(v1Contract.V1, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode input contract %s: %w", v1Contract.V1.ContractId, err)
+		}
+		b = append(b, sha256Sum(create)...)
+	}
+
+	return sha256Sum(b), nil
+}
+
+// encodeNodeIDs encodes nodes by the hashes of their encoding, making the transaction a Merkle tree.
+func (e *hashEncoder) encodeNodeIDs(b []byte, nodeIDs []string) ([]byte, error) {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(nodeIDs)))
+	for _, nodeID := range nodeIDs {
+		encoded, err := e.encodeNode(nodeID)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, sha256Sum(encoded)...)
+	}
+	return b, nil
+}
+
+func (e *hashEncoder) encodeNode(nodeID string) ([]byte, error) {
+	node, ok := e.nodes[nodeID]
+	if !ok {
+		return nil, fmt.Errorf("node %s not found", nodeID)
+	}
+
+	switch n := node.NodeType.(type) {
+	case *v1.Node_Create:
+		return encodeCreate(n.Create, e.seeds[nodeID])
+	case *v1.Node_Exercise:
+		seed, ok := e.seeds[nodeID]
+		if !ok {
+			return nil, fmt.Errorf("exercise node %s has no seed", nodeID)
+		}
+		return e.encodeExercise(n.Exercise, seed)
+	case *v1.Node_Fetch:
+		return encodeFetch(n.Fetch)
+	case *v1.Node_Rollback:
+		b := []byte{nodeEncodingVersion, rollbackNodeTag}
+		return e.encodeNodeIDs(b, n.Rollback.Children)
+	default:
+		return nil, fmt.Errorf("node %s has an unknown type %T", nodeID, node.NodeType)
+	}
+}
+
+func encodeCreate(create *v1.Create, seed []byte) ([]byte, error) {
+	b := []byte{nodeEncodingVersion}
+	b = encodeString(b, create.LfVersion)
+	b = append(b, createNodeTag)
+	if seed == nil {
+		b = append(b, 0x00)
+	} else {
+		b = append(b, 0x01)
+		b = append(b, seed...)
+	}
+
+	b, err := encodeContractID(b, create.ContractId)
+	if err != nil {
+		return nil, err
+	}
+	b = encodeString(b, create.PackageName)
+	b = encodeIdentifier(b, create.TemplateId)
+	if b, err = encodeValue(b, create.Argument); err != nil {
+		return nil, err
+	}
+	b = encodeStrings(b, create.Signatories)
+	b = encodeStrings(b, create.Stakeholders)
+	return b, nil
+}
+
+func (e *hashEncoder) encodeExercise(exercise *v1.Exercise, seed []byte) ([]byte, error) {
+	b := []byte{nodeEncodingVersion}
+	b = encodeString(b, exercise.LfVersion)
+	b = append(b, exerciseNodeTag)
+	b = append(b, seed...)
+
+	b, err := encodeContractID(b, exercise.ContractId)
+	if err != nil {
+		return nil, err
+	}
+	b = encodeString(b, exercise.PackageName)
+	b = encodeIdentifier(b, exercise.TemplateId)
+	b = encodeStrings(b, exercise.Signatories)
+	b = encodeStrings(b, exercise.Stakeholders)
+	b = encodeStrings(b, exercise.ActingParties)
+	b = encodeOptionalIdentifier(b, exercise.InterfaceId)
+	b = encodeString(b, exercise.ChoiceId)
+	if b, err = encodeValue(b, exercise.ChosenValue); err != nil {
+		return nil, err
+	}
+	b = encodeBool(b, exercise.Consuming)
+	if exercise.ExerciseResult == nil {
+		b = append(b, 0x00)
+	} else {
+		b = append(b, 0x01)
+		if b, err = encodeValue(b, exercise.ExerciseResult); err != nil {
+			return nil, err
+		}
+	}
+	b = encodeStrings(b, exercise.ChoiceObservers)
+	return e.encodeNodeIDs(b, exercise.Children)
+}
+
+func encodeFetch(fetch *v1.Fetch) ([]byte, error) {
+	b := []byte{nodeEncodingVersion}
+	b = encodeString(b, fetch.LfVersion)
+	b = append(b, fetchNodeTag)
+
+	b, err := encodeContractID(b, fetch.ContractId)
+	if err != nil {
+		return nil, err
+	}
+	b = encodeString(b, fetch.PackageName)
+	b = encodeIdentifier(b, fetch.TemplateId)
+	b = encodeStrings(b, fetch.Signatories)
+	b = encodeStrings(b, fetch.Stakeholders)
+	b = encodeOptionalIdentifier(b, fetch.InterfaceId)
+	b = encodeStrings(b, fetch.ActingParties)
+	return b, nil
+}
+
+func encodeValue(b []byte, value *v2.Value) ([]byte, error) {
+	if value == nil {
+		return nil, fmt.Errorf("value is missing")
+	}
+
+	var err error
+	switch v := value.Sum.(type) {
+	case *v2.Value_Unit:
+		b = append(b, 0x00)
+	case *v2.Value_Bool:
+		b = encodeBool(append(b, 0x01), v.Bool)
+	case *v2.Value_Int64:
+		b = binary.BigEndian.AppendUint64(append(b, 0x02), uint64(v.Int64))
+	case *v2.Value_Numeric:
+		b = encodeString(append(b, 0x03), v.Numeric)
+	case *v2.Value_Timestamp:
+		b = binary.BigEndian.AppendUint64(append(b, 0x04), uint64(v.Timestamp))
+	case *v2.Value_Date:
+		b = binary.BigEndian.AppendUint32(append(b, 0x05), uint32(v.Date))
+	case *v2.Value_Party:
+		b = encodeString(append(b, 0x06), v.Party)
+	case *v2.Value_Text:
+		b = encodeString(append(b, 0x07), v.Text)
+	case *v2.Value_ContractId:
+		b, err = encodeContractID(append(b, 0x08), v.ContractId)
+	case *v2.Value_Optional:
+		b = append(b, 0x09)
+		if v.Optional == nil || v.Optional.Value == nil {
+			b = append(b, 0x00)
+		} else {
+			b, err = encodeValue(append(b, 0x01), v.Optional.Value)
+		}
+	case *v2.Value_List:
+		b = append(b, 0x0a)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(v.List.GetElements())))
+		for _, element := range v.List.GetElements() {
+			if b, err = encodeValue(b, element); err != nil {
+				return nil, err
+			}
+		}
+	case *v2.Value_TextMap:
+		b = append(b, 0x0b)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(v.TextMap.GetEntries())))
+		for _, entry := range v.TextMap.GetEntries() {
+			b = encodeString(b, entry.Key)
+			if b, err = encodeValue(b, entry.Value); err != nil {
+				return nil, err
+			}
+		}
+	case *v2.Value_Record:
+		b = append(b, 0x0c)
+		b = encodeOptionalIdentifier(b, v.Record.GetRecordId())
+		b = binary.BigEndian.AppendUint32(b, uint32(len(v.Record.GetFields())))
+		for _, field := range v.Record.GetFields() {
+			if field.Label == "" {
+				b = append(b, 0x00)
+			} else {
+				b = encodeString(append(b, 0x01), field.Label)
+			}
+			if b, err = encodeValue(b, field.Value); err != nil {
+				return nil, err
+			}
+		}
+	case *v2.Value_Variant:
+		b = append(b, 0x0d)
+		b = encodeOptionalIdentifier(b, v.Variant.GetVariantId())
+		b = encodeString(b, v.Variant.GetConstructor())
+		b, err = encodeValue(b, v.Variant.GetValue())
+	case *v2.Value_Enum:
+		b = append(b, 0x0e)
+		b = encodeOptionalIdentifier(b, v.Enum.GetEnumId())
+		b = encodeString(b, v.Enum.GetConstructor())
+	case *v2.Value_GenMap:
+		b = append(b, 0x0f)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(v.GenMap.GetEntries())))
+		for _, entry := range v.GenMap.GetEntries() {
+			if b, err = encodeValue(b, entry.Key); err != nil {
+				return nil, err
+			}
+			if b, err = encodeValue(b, entry.Value); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value.Sum)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func encodeBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0x01)
+	}
+	return append(b, 0x00)
+}
+
+func encodeBytes(b, v []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(v)))
+	return append(b, v...)
+}
+
+func encodeString(b []byte, v string) []byte {
+	return encodeBytes(b, []byte(v))
+}
+
+func encodeStrings(b []byte, values []string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(values)))
+	for _, v := range values {
+		b = encodeString(b, v)
+	}
+	return b
+}
+
+// encodeContractID encodes the bytes of a hex encoded contract ID.
+func encodeContractID(b []byte, contractID string) ([]byte, error) {
+	decoded, err := hex.DecodeString(contractID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract ID %q: %w", contractID, err)
+	}
+	return encodeBytes(b, decoded), nil
+}
+
+func encodeIdentifier(b []byte, id *v2.Identifier) []byte {
+	b = encodeString(b, id.GetPackageId())
+	b = encodeStrings(b, strings.Split(id.GetModuleName(), "."))
+	return encodeStrings(b, strings.Split(id.GetEntityName(), "."))
+}
+
+func encodeOptionalIdentifier(b []byte, id *v2.Identifier) []byte {
+	if id == nil {
+		return append(b, 0x00)
+	}
+	return encodeIdentifier(append(b, 0x01), id)
+}
+
+func encodeOptionalTime(b []byte, micros *uint64) []byte {
+	if micros == nil {
+		return append(b, 0x00)
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0x01), *micros)
+}
+
+func sha256Sum(b []byte) []byte {
+	sum := sha256.Sum256(b)
+	return sum[:]
+}
@@ -0,0 +1,307 @@
+package ledger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+	"github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2/interactive"
+	v1 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2/interactive/transaction/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+const (
+	tokenContractID    = "00aa"
+	transferContractID = "00bb"
+)
+
+func tokenTemplateID() *v2.Identifier {
+	return &v2.Identifier{PackageId: "6d7e83e8", ModuleName: "Main.Token", EntityName: "Token"}
+}
+
+func tokenArgument(owner string) *v2.Value {
+	return &v2.Value{Sum: &v2.Value_Record{Record: &v2.Record{Fields: []*v2.RecordField{
+		{Label: "owner", Value: &v2.Value{Sum: &v2.Value_Party{Party: owner}}},
+		{Label: "amount", Value: &v2.Value{Sum: &v2.Value_Numeric{Numeric: "10.0000000000"}}},
+	}}}}
+}
+
+func preparedNode(nodeID string, node *v1.Node) *interactive.DamlTransaction_Node {
+	return &interactive.DamlTransaction_Node{NodeId: nodeID, VersionedNode: &interactive.DamlTransaction_Node_V1{V1: node}}
+}
+
+// preparedTransfer is a prepared transaction in which alice transfers a token to bob.
+func preparedTransfer() *interactive.PreparedTransaction {
+	minLedgerTime := uint64(1_700_000_000_000_000)
+	return &interactive.PreparedTransaction{
+		Transaction: &interactive.DamlTransaction{
+			Version: "2.1",
+			Roots:   []string{"0"},
+			Nodes: []*interactive.DamlTransaction_Node{
+				preparedNode("0", &v1.Node{NodeType: &v1.Node_Exercise{Exercise: &v1.Exercise{
+					LfVersion:     "2.1",
+					ContractId:    tokenContractID,
+					PackageName:   "airdrop",
+					TemplateId:    tokenTemplateID(),
+					Signatories:   []string{"alice"},
+					Stakeholders:  []string{"alice"},
+					ActingParties: []string{"alice"},
+					ChoiceId:      "Transfer",
+					ChosenValue: &v2.Value{Sum: &v2.Value_Record{Record: &v2.Record{Fields: []*v2.RecordField{
+						{Label: "newOwner", Value: &v2.Value{Sum: &v2.Value_Party{Party: "bob"}}},
+					}}}},
+					Consuming:      true,
+					Children:       []string{"1", "2"},
+					ExerciseResult: &v2.Value{Sum: &v2.Value_ContractId{ContractId: transferContractID}},
+				}}}),
+				preparedNode("1", &v1.Node{NodeType: &v1.Node_Fetch{Fetch: &v1.Fetch{
+					LfVersion:     "2.1",
+					ContractId:    tokenContractID,
+					PackageName:   "airdrop",
+					TemplateId:    tokenTemplateID(),
+					Signatories:   []string{"alice"},
+					Stakeholders:  []string{"alice"},
+					ActingParties: []string{"alice"},
+				}}}),
+				preparedNode("2", &v1.Node{NodeType: &v1.Node_Create{Create: &v1.Create{
+					LfVersion:    "2.1",
+					ContractId:   transferContractID,
+					PackageName:  "airdrop",
+					TemplateId:   tokenTemplateID(),
+					Argument:     tokenArgument("bob"),
+					Signatories:  []string{"bob"},
+					Stakeholders: []string{"bob"},
+				}}}),
+			},
+			NodeSeeds: []*interactive.DamlTransaction_NodeSeed{
+				{NodeId: 0, Seed: make([]byte, 32)},
+				{NodeId: 2, Seed: append(make([]byte, 31), 1)},
+			},
+		},
+		Metadata: &interactive.Metadata{
+			SubmitterInfo:          &interactive.Metadata_SubmitterInfo{ActAs: []string{"alice"}, CommandId: "cmd-1"},
+			SynchronizerId:         "global::1220",
+			TransactionUuid:        "5f0b6f6e-3c1a-4d52-9a55-0d0a1c0f5a11",
+			PreparationTime:        1_700_000_000_000_000,
+			MinLedgerEffectiveTime: &minLedgerTime,
+			InputContracts: []*interactive.Metadata_InputContract{{
+				CreatedAt: 1_600_000_000_000_000,
+				Contract: &interactive.Metadata_InputContract_V1{V1: &v1.Create{
+					LfVersion:    "2.1",
+					ContractId:   tokenContractID,
+					PackageName:  "airdrop",
+					TemplateId:   tokenTemplateID(),
+					Argument:     tokenArgument("alice"),
+					Signatories:  []string{"alice"},
+					Stakeholders: []string{"alice"},
+				}},
+			}},
+		},
+	}
+}
+
+func prepareResponse(t *testing.T, prepared *interactive.PreparedTransaction) *model.PrepareSubmissionResponse {
+	t.Helper()
+
+	data, err := proto.Marshal(prepared)
+	require.NoError(t, err)
+	hash, err := HashPreparedTransaction(data, model.HashingSchemeVersionV2)
+	require.NoError(t, err)
+
+	return &model.PrepareSubmissionResponse{
+		PreparedTransaction:     data,
+		PreparedTransactionHash: hash,
+		HashingSchemeVersion:    model.HashingSchemeVersionV2,
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	encoded, err := encodeValue(nil, &v2.Value{Sum: &v2.Value_Record{Record: &v2.Record{Fields: []*v2.RecordField{
+		{Label: "owner", Value: &v2.Value{Sum: &v2.Value_Party{Party: "alice"}}},
+		{Value: &v2.Value{Sum: &v2.Value_Optional{Optional: &v2.Optional{Value: &v2.Value{Sum: &v2.Value_Int64{Int64: 1}}}}}},
+	}}}})
+	require.NoError(t, err)
+	require.Equal(t, "0c"+ // record
+		"00"+ // no record ID
+		"00000002"+ // fields
+		"01"+"00000005"+hex.EncodeToString([]byte("owner"))+ // label
+		"06"+"00000005"+hex.EncodeToString([]byte("alice"))+ // party
+		"00"+ // no label
+		"09"+"01"+"02"+"0000000000000001", // optional int64
+		hex.EncodeToString(encoded))
+}
+
+func TestVerifyPreparedTransaction(t *testing.T) {
+	prepared := prepareResponse(t, preparedTransfer())
+	require.Len(t, prepared.PreparedTransactionHash, 32)
+
+	summary, err := VerifyPreparedTransaction(prepared)
+	require.NoError(t, err)
+	require.Equal(t, "cmd-1", summary.CommandID)
+	require.Equal(t, []string{"alice"}, summary.ActAs)
+	require.Equal(t, []string{tokenContractID}, summary.InputContracts)
+	require.Equal(t, int64(1_700_000_000), summary.PreparationTime.Unix())
+
+	require.Len(t, summary.Nodes, 3)
+	require.Equal(t, PreparedNode{
+		NodeID:        "0",
+		Kind:          PreparedNodeExercise,
+		ContractID:    tokenContractID,
+		TemplateID:    "6d7e83e8:Main.Token:Token",
+		Choice:        "Transfer",
+		Consuming:     true,
+		Signatories:   []string{"alice"},
+		Stakeholders:  []string{"alice"},
+		ActingParties: []string{"alice"},
+	}, summary.Nodes[0])
+	require.Equal(t, PreparedNodeFetch, summary.Nodes[1].Kind)
+	require.Equal(t, 1, summary.Nodes[2].Depth)
+	require.Equal(t, []string{"bob"}, summary.Nodes[2].Signatories)
+
+	require.Equal(t, `command cmd-1 acting as alice on synchronizer global::1220, prepared at 2023-11-14T22:13:20Z
+  exercise consuming Transfer on 6d7e83e8:Main.Token:Token 00aa by alice
+    fetch 6d7e83e8:Main.Token:Token 00aa by alice
+    create 6d7e83e8:Main.Token:Token 00bb signed by bob
+`, summary.String())
+}
+
+func TestVerifyPreparedTransactionDetectsTampering(t *testing.T) {
+	prepared := prepareResponse(t, preparedTransfer())
+
+	tests := []struct {
+		name   string
+		tamper func(*interactive.PreparedTransaction)
+	}{
+		{"create argument", func(p *interactive.PreparedTransaction) {
+			p.Transaction.Nodes[2].GetV1().GetCreate().Argument = tokenArgument("mallory")
+		}},
+		{"choice argument", func(p *interactive.PreparedTransaction) {
+			p.Transaction.Nodes[0].GetV1().GetExercise().ChosenValue = &v2.Value{Sum: &v2.Value_Unit{}}
+		}},
+		{"node seed", func(p *interactive.PreparedTransaction) {
+			p.Transaction.NodeSeeds[1].Seed = make([]byte, 32)
+		}},
+		{"submitter", func(p *interactive.PreparedTransaction) {
+			p.Metadata.SubmitterInfo.ActAs = []string{"mallory"}
+		}},
+		{"ledger time bound", func(p *interactive.PreparedTransaction) {
+			p.Metadata.MinLedgerEffectiveTime = nil
+		}},
+		{"input contract", func(p *interactive.PreparedTransaction) {
+			p.Metadata.InputContracts[0].CreatedAt++
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := preparedTransfer()
+			tt.tamper(tampered)
+			data, err := proto.Marshal(tampered)
+			require.NoError(t, err)
+
+			_, err = VerifyPreparedTransaction(&model.PrepareSubmissionResponse{
+				PreparedTransaction:     data,
+				PreparedTransactionHash: prepared.PreparedTransactionHash,
+				HashingSchemeVersion:    model.HashingSchemeVersionV2,
+			})
+			require.ErrorIs(t, err, ErrPreparedTransactionHashMismatch)
+		})
+	}
+
+	_, err := HashPreparedTransaction(prepared.PreparedTransaction, model.HashingSchemeVersionUnspecified)
+	require.ErrorContains(t, err, "unsupported hashing scheme version")
+}
+
+// preparedCreate is a prepared transaction with a single create node, small enough for its
+// hashing scheme version 2 encoding to be written out by hand.
+func preparedCreate() *interactive.PreparedTransaction {
+	return &interactive.PreparedTransaction{
+		Transaction: &interactive.DamlTransaction{
+			Version: "2.1",
+			Roots:   []string{"0"},
+			Nodes: []*interactive.DamlTransaction_Node{
+				preparedNode("0", &v1.Node{NodeType: &v1.Node_Create{Create: &v1.Create{
+					LfVersion:    "2.1",
+					ContractId:   tokenContractID,
+					PackageName:  "airdrop",
+					TemplateId:   tokenTemplateID(),
+					Argument:     tokenArgument("alice"),
+					Signatories:  []string{"alice"},
+					Stakeholders: []string{"alice"},
+				}}}),
+			},
+			NodeSeeds: []*interactive.DamlTransaction_NodeSeed{
+				{NodeId: 0, Seed: bytes.Repeat([]byte{0x01}, 32)},
+			},
+		},
+		Metadata: &interactive.Metadata{
+			SubmitterInfo:   &interactive.Metadata_SubmitterInfo{ActAs: []string{"alice"}, CommandId: "cmd-1"},
+			SynchronizerId:  "global::1220",
+			TransactionUuid: "5f0b6f6e-3c1a-4d52-9a55-0d0a1c0f5a11",
+			PreparationTime: 1_700_000_000_000_000,
+		},
+	}
+}
+
+// encodedString is the hex of the length-prefixed encoding of s.
+func encodedString(s string) string {
+	return fmt.Sprintf("%08x", len(s)) + hex.EncodeToString([]byte(s))
+}
+
+func sha256Hex(t *testing.T, encoded string) string {
+	t.Helper()
+
+	b, err := hex.DecodeString(encoded)
+	require.NoError(t, err)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHashPreparedTransactionFixture(t *testing.T) {
+	// The expected hash is pinned, and rebuilt below from the byte layout of hashing scheme
+	// version 2 written out by hand rather than by the encoder under test.
+	const expectedHash = "cd387cc2aa398273c56847ea9c4607e782a4ca46a6654ebeb902c5d18399c8f7"
+
+	identifier := encodedString("6d7e83e8") +
+		"00000002" + encodedString("Main") + encodedString("Token") + // module name
+		"00000001" + encodedString("Token") // entity name
+	createNode := "01" + // node encoding version
+		encodedString("2.1") +
+		"00" + // create
+		"01" + strings.Repeat("01", 32) + // seed
+		"00000002" + "00aa" + // contract ID
+		encodedString("airdrop") +
+		identifier +
+		"0c" + "00" + "00000002" + // argument record
+		"01" + encodedString("owner") + "06" + encodedString("alice") +
+		"01" + encodedString("amount") + "03" + encodedString("10.0000000000") +
+		"00000001" + encodedString("alice") + // signatories
+		"00000001" + encodedString("alice") // stakeholders
+	transaction := "00000030" + // hash purpose
+		encodedString("2.1") +
+		"00000001" + sha256Hex(t, createNode) // roots
+	metadata := "00000030" + // hash purpose
+		"01" + // metadata encoding version
+		"00000001" + encodedString("alice") + // act as
+		encodedString("cmd-1") +
+		encodedString("5f0b6f6e-3c1a-4d52-9a55-0d0a1c0f5a11") +
+		"00000000" + // mediator group
+		encodedString("global::1220") +
+		"00" + "00" + // no ledger time bounds
+		"00060a24181e4000" + // preparation time
+		"00000000" // input contracts
+	require.Equal(t, expectedHash, sha256Hex(t, "00000030"+"02"+sha256Hex(t, transaction)+sha256Hex(t, metadata)))
+
+	data, err := proto.Marshal(preparedCreate())
+	require.NoError(t, err)
+	hash, err := HashPreparedTransaction(data, model.HashingSchemeVersionV2)
+	require.NoError(t, err)
+	require.Equal(t, expectedHash, hex.EncodeToString(hash))
+}
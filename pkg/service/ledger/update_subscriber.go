@@ -0,0 +1,251 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	damlerrors "github.com/smartcontractkit/go-daml/pkg/errors"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// ErrOffsetPruned is returned by an UpdateSubscriber when the offset it has to resume from
+// has been pruned by the participant. Updates up to the pruned offset can no longer be streamed,
+// so the subscriber has to be restarted from a snapshot, e.g. of the active contracts.
+var ErrOffsetPruned = errors.New("offset has been pruned by the participant")
+
+// UpdateSubscriber streams updates like UpdateService.GetUpdates, but reconnects with backoff when
+// the stream fails, resuming after the last delivered offset. Offsets, including those of offset
+// checkpoints, are saved to an OffsetCheckpointStore once an update has been handed to the consumer.
+type UpdateSubscriber struct {
+	updateService   UpdateService
+	stateService    StateService
+	updateFormat    *model.EventFormat
+	store           OffsetCheckpointStore
+	minBackoff      time.Duration
+	maxBackoff      time.Duration
+	maxReconnects   int
+	isRetryableFunc func(error) bool
+}
+
+type UpdateSubscriberOption func(*UpdateSubscriber)
+
+// WithCheckpointStore sets the store the subscriber loads its start offset from and saves offsets to.
+// By default offsets are only kept in memory and streaming starts at the beginning of the ledger.
+func WithCheckpointStore(store OffsetCheckpointStore) UpdateSubscriberOption {
+	return func(s *UpdateSubscriber) {
+		s.store = store
+	}
+}
+
+// WithReconnectBackoff sets the delay before the first reconnect, which doubles up to max
+// for every subsequent attempt that does not deliver any update.
+func WithReconnectBackoff(min, max time.Duration) UpdateSubscriberOption {
+	return func(s *UpdateSubscriber) {
+		s.minBackoff = min
+		s.maxBackoff = max
+	}
+}
+
+// WithMaxReconnects limits the number of consecutive reconnects without any delivered update.
+// The default of 0 reconnects indefinitely.
+func WithMaxReconnects(maxReconnects int) UpdateSubscriberOption {
+	return func(s *UpdateSubscriber) {
+		s.maxReconnects = maxReconnects
+	}
+}
+
+// WithRetryableError overrides which stream errors cause a reconnect, see IsRetryableStreamError.
+func WithRetryableError(isRetryable func(error) bool) UpdateSubscriberOption {
+	return func(s *UpdateSubscriber) {
+		s.isRetryableFunc = isRetryable
+	}
+}
+
+func NewUpdateSubscriber(updateService UpdateService, stateService StateService, updateFormat *model.EventFormat, opts ...UpdateSubscriberOption) *UpdateSubscriber {
+	s := &UpdateSubscriber{
+		updateService:   updateService,
+		stateService:    stateService,
+		updateFormat:    updateFormat,
+		store:           NewMemoryCheckpointStore(0),
+		minBackoff:      500 * time.Millisecond,
+		maxBackoff:      30 * time.Second,
+		isRetryableFunc: IsRetryableStreamError,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// IsRetryableStreamError reports whether a stream failed for a transient reason, such as the
+// participant being unavailable or restarting. Errors without a gRPC status are treated as transient.
+func IsRetryableStreamError(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted,
+		codes.Internal, codes.Unknown, codes.Canceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Subscribe streams updates until ctx is cancelled or the stream fails permanently, in which case
+// the error is sent on the error channel. Both channels are closed when the subscription ends.
+func (s *UpdateSubscriber) Subscribe(ctx context.Context) (<-chan *model.GetUpdatesResponse, <-chan error) {
+	responseCh := make(chan *model.GetUpdatesResponse)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(responseCh)
+		defer close(errCh)
+
+		if err := s.run(ctx, responseCh); err != nil && ctx.Err() == nil {
+			errCh <- err
+		}
+	}()
+
+	return responseCh, errCh
+}
+
+func (s *UpdateSubscriber) run(ctx context.Context, responseCh chan<- *model.GetUpdatesResponse) error {
+	offset, err := s.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load offset checkpoint: %w", err)
+	}
+
+	backoff := s.minBackoff
+	reconnects := 0
+	for {
+		progressed, streamErr, err := s.stream(ctx, &offset, responseCh)
+		if err != nil || ctx.Err() != nil {
+			return err
+		}
+
+		if progressed {
+			backoff = s.minBackoff
+			reconnects = 0
+		}
+
+		if streamErr != nil {
+			if err := s.checkPruned(ctx, offset, streamErr); err != nil {
+				return err
+			}
+			if !s.isRetryableFunc(streamErr) {
+				return fmt.Errorf("update stream from offset %d failed: %w", offset, streamErr)
+			}
+		}
+
+		reconnects++
+		if s.maxReconnects > 0 && reconnects > s.maxReconnects {
+			return fmt.Errorf("update stream from offset %d failed after %d reconnects: %w", offset, s.maxReconnects, streamErr)
+		}
+
+		log.Warn().Err(streamErr).Msgf("update stream interrupted at offset %d, reconnecting in %s", offset, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		backoff = min(2*backoff, s.maxBackoff)
+	}
+}
+
+// stream delivers the updates after offset until the stream ends. It returns whether any update
+// was delivered, the error the stream ended with, and any error that has to end the subscription.
+func (s *UpdateSubscriber) stream(ctx context.Context, offset *int64, responseCh chan<- *model.GetUpdatesResponse) (bool, error, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses, errs := s.updateService.GetUpdates(streamCtx, &model.GetUpdatesRequest{
+		BeginExclusive: *offset,
+		UpdateFormat:   s.updateFormat,
+	})
+
+	progressed := false
+	for responses != nil || errs != nil {
+		select {
+		case resp, ok := <-responses:
+			if !ok {
+				responses = nil
+				continue
+			}
+
+			updateOffset := updateOffset(resp.Update)
+			if updateOffset != 0 && updateOffset <= *offset {
+				// Already delivered before the stream was resumed
+				continue
+			}
+
+			select {
+			case responseCh <- resp:
+			case <-ctx.Done():
+				return progressed, nil, nil
+			}
+			progressed = true
+
+			if updateOffset != 0 {
+				*offset = updateOffset
+				if err := s.store.Save(ctx, updateOffset); err != nil {
+					return progressed, nil, fmt.Errorf("failed to save offset checkpoint %d: %w", updateOffset, err)
+				}
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			return progressed, err, nil
+		case <-ctx.Done():
+			return progressed, nil, nil
+		}
+	}
+
+	return progressed, nil, nil
+}
+
+// checkPruned returns ErrOffsetPruned if the stream cannot be resumed from offset because
+// the participant has pruned it.
+func (s *UpdateSubscriber) checkPruned(ctx context.Context, offset int64, streamErr error) error {
+	if s.stateService != nil {
+		pruned, err := s.stateService.GetLatestPrunedOffsets(ctx, &model.GetLatestPrunedOffsetsRequest{})
+		if err == nil {
+			if offset < pruned.ParticipantPrunedUpToInclusive {
+				return fmt.Errorf("%w: resuming from offset %d, pruned up to %d", ErrOffsetPruned, offset, pruned.ParticipantPrunedUpToInclusive)
+			}
+			return nil
+		}
+		log.Debug().Err(err).Msg("failed to get latest pruned offsets")
+	}
+
+	if errors.Is(damlerrors.AsDamlError(streamErr), damlerrors.ErrParticipantPrunedDataAccessed) {
+		return fmt.Errorf("%w: resuming from offset %d: %w", ErrOffsetPruned, offset, streamErr)
+	}
+
+	return nil
+}
+
+func updateOffset(update *model.Update) int64 {
+	switch {
+	case update == nil:
+		return 0
+	case update.Transaction != nil:
+		return update.Transaction.Offset
+	case update.Reassignment != nil:
+		return update.Reassignment.Offset
+	case update.OffsetCheckpoint != nil:
+		return update.OffsetCheckpoint.Offset
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,150 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// fakeStream is one scripted GetUpdates call: the updates it delivers and the error it ends with.
+type fakeStream struct {
+	updates []*model.Update
+	err     error
+}
+
+type fakeUpdateService struct {
+	UpdateService
+	streams []fakeStream
+	begins  []int64
+}
+
+func (f *fakeUpdateService) GetUpdates(ctx context.Context, req *model.GetUpdatesRequest) (<-chan *model.GetUpdatesResponse, <-chan error) {
+	f.begins = append(f.begins, req.BeginExclusive)
+
+	stream := fakeStream{err: status.Error(codes.Unavailable, "no more streams")}
+	if len(f.streams) > 0 {
+		stream, f.streams = f.streams[0], f.streams[1:]
+	}
+
+	responseCh := make(chan *model.GetUpdatesResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(responseCh)
+		defer close(errCh)
+		for _, update := range stream.updates {
+			select {
+			case responseCh <- &model.GetUpdatesResponse{Update: update}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if stream.err != nil {
+			errCh <- stream.err
+		}
+	}()
+	return responseCh, errCh
+}
+
+type fakeStateService struct {
+	StateService
+	prunedUpTo int64
+}
+
+func (f *fakeStateService) GetLatestPrunedOffsets(context.Context, *model.GetLatestPrunedOffsetsRequest) (*model.GetLatestPrunedOffsetsResponse, error) {
+	return &model.GetLatestPrunedOffsetsResponse{ParticipantPrunedUpToInclusive: f.prunedUpTo}, nil
+}
+
+func transactionAt(offset int64) *model.Update {
+	return &model.Update{Transaction: &model.Transaction{Offset: offset}}
+}
+
+func TestUpdateSubscriberReconnects(t *testing.T) {
+	updates := &fakeUpdateService{streams: []fakeStream{
+		{updates: []*model.Update{transactionAt(11), transactionAt(12)}, err: status.Error(codes.Unavailable, "participant restarting")},
+		{err: status.Error(codes.Unavailable, "participant restarting")},
+		{updates: []*model.Update{transactionAt(12), {OffsetCheckpoint: &model.OffsetCheckpoint{Offset: 15}}, transactionAt(16)}},
+	}}
+	store := NewMemoryCheckpointStore(10)
+	subscriber := NewUpdateSubscriber(updates, &fakeStateService{}, nil,
+		WithCheckpointStore(store), WithReconnectBackoff(time.Millisecond, 2*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	responses, errs := subscriber.Subscribe(ctx)
+
+	var offsets []int64
+	for len(offsets) < 4 {
+		resp := <-responses
+		offsets = append(offsets, updateOffset(resp.Update))
+	}
+	require.Equal(t, []int64{11, 12, 15, 16}, offsets)
+
+	cancel()
+	for range responses {
+	}
+	require.NoError(t, <-errs)
+	require.Equal(t, []int64{10, 12, 12}, updates.begins[:3])
+
+	offset, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(16), offset)
+}
+
+func TestUpdateSubscriberStopsOnPrunedOffset(t *testing.T) {
+	updates := &fakeUpdateService{streams: []fakeStream{
+		{err: status.Error(codes.FailedPrecondition, "PARTICIPANT_PRUNED_DATA_ACCESSED(9,abc): Transactions request from 5 to 20 precedes pruned offset 8")},
+	}}
+	subscriber := NewUpdateSubscriber(updates, &fakeStateService{prunedUpTo: 8}, nil,
+		WithCheckpointStore(NewMemoryCheckpointStore(5)), WithReconnectBackoff(time.Millisecond, time.Millisecond))
+
+	responses, errs := subscriber.Subscribe(context.Background())
+	for range responses {
+	}
+	require.ErrorIs(t, <-errs, ErrOffsetPruned)
+}
+
+func TestUpdateSubscriberMaxReconnects(t *testing.T) {
+	updates := &fakeUpdateService{}
+	subscriber := NewUpdateSubscriber(updates, &fakeStateService{}, nil,
+		WithReconnectBackoff(time.Millisecond, time.Millisecond), WithMaxReconnects(2))
+
+	responses, errs := subscriber.Subscribe(context.Background())
+	for range responses {
+	}
+	err := <-errs
+	require.ErrorContains(t, err, "after 2 reconnects")
+	require.Equal(t, codes.Unavailable, status.Code(errors.Unwrap(err)))
+	require.Len(t, updates.begins, 3)
+
+	updates = &fakeUpdateService{streams: []fakeStream{{err: status.Error(codes.PermissionDenied, "no access")}}}
+	subscriber = NewUpdateSubscriber(updates, &fakeStateService{}, nil, WithReconnectBackoff(time.Millisecond, time.Millisecond))
+	responses, errs = subscriber.Subscribe(context.Background())
+	for range responses {
+	}
+	require.Equal(t, codes.PermissionDenied, status.Code(errors.Unwrap(<-errs)))
+	require.Len(t, updates.begins, 1)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "offset"))
+
+	offset, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Zero(t, offset)
+
+	require.NoError(t, store.Save(ctx, 42))
+	require.NoError(t, store.Save(ctx, 43))
+
+	offset, err = NewFileCheckpointStore(store.path).Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(43), offset)
+}
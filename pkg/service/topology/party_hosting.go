@@ -0,0 +1,200 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+// PartyHostingChange changes the hosting of a party, see AddHostingParticipant,
+// RemoveHostingParticipant, ChangeHostingPermission and ChangeHostingThreshold.
+type PartyHostingChange func(mapping *model.PartyToParticipantMapping) error
+
+// AddHostingParticipant hosts the party on participantUID with permission.
+func AddHostingParticipant(participantUID string, permission model.ParticipantPermission) PartyHostingChange {
+	return func(mapping *model.PartyToParticipantMapping) error {
+		if hostingParticipantIndex(mapping, participantUID) >= 0 {
+			return fmt.Errorf("party %s is already hosted on %s", mapping.Party, participantUID)
+		}
+		mapping.Participants = append(mapping.Participants, model.HostingParticipant{
+			ParticipantUID: participantUID,
+			Permission:     permission,
+		})
+		return nil
+	}
+}
+
+// RemoveHostingParticipant stops hosting the party on participantUID.
+func RemoveHostingParticipant(participantUID string) PartyHostingChange {
+	return func(mapping *model.PartyToParticipantMapping) error {
+		i := hostingParticipantIndex(mapping, participantUID)
+		if i < 0 {
+			return fmt.Errorf("party %s is not hosted on %s", mapping.Party, participantUID)
+		}
+		mapping.Participants = slices.Delete(mapping.Participants, i, i+1)
+		return nil
+	}
+}
+
+// ChangeHostingPermission changes the permission of participantUID, which must already host the party.
+func ChangeHostingPermission(participantUID string, permission model.ParticipantPermission) PartyHostingChange {
+	return func(mapping *model.PartyToParticipantMapping) error {
+		i := hostingParticipantIndex(mapping, participantUID)
+		if i < 0 {
+			return fmt.Errorf("party %s is not hosted on %s", mapping.Party, participantUID)
+		}
+		mapping.Participants[i].Permission = permission
+		return nil
+	}
+}
+
+// ChangeHostingThreshold changes the number of hosting participants that must confirm
+// transactions of the party.
+func ChangeHostingThreshold(threshold uint32) PartyHostingChange {
+	return func(mapping *model.PartyToParticipantMapping) error {
+		mapping.Threshold = threshold
+		return nil
+	}
+}
+
+// ApplyPartyHostingChanges applies changes to a copy of mapping and validates the result: the
+// party must be hosted on at least one participant, and the threshold must be between one and
+// the number of participants with submission or confirmation permission.
+func ApplyPartyHostingChanges(mapping *model.PartyToParticipantMapping, changes ...PartyHostingChange) (*model.PartyToParticipantMapping, error) {
+	changed := &model.PartyToParticipantMapping{
+		Party:        mapping.Party,
+		Threshold:    mapping.Threshold,
+		Participants: slices.Clone(mapping.Participants),
+	}
+	for _, change := range changes {
+		if err := change(changed); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(changed.Participants) == 0 {
+		return nil, fmt.Errorf("party %s must be hosted on at least one participant", changed.Party)
+	}
+	var confirming uint32
+	for _, p := range changed.Participants {
+		if p.Permission != model.ParticipantPermissionObservation {
+			confirming++
+		}
+	}
+	if changed.Threshold < 1 || changed.Threshold > confirming {
+		return nil, fmt.Errorf("threshold %d of party %s must be between 1 and its %d confirming participants", changed.Threshold, changed.Party, confirming)
+	}
+
+	return changed, nil
+}
+
+// PartyHostingProposal is a proposed change to the hosting of a party.
+type PartyHostingProposal struct {
+	Mapping     *model.PartyToParticipantMapping
+	Serial      uint32
+	Transaction *model.SignedTopologyTransaction
+	// MissingSignatures are the namespaces that still have to authorize the proposal: the
+	// namespace of the party and those of the participants it is newly hosted on. A namespace
+	// counts as signed once its root key, whose fingerprint is the namespace, has signed.
+	MissingSignatures []string
+}
+
+// FullyAuthorized reports whether the proposal has all the signatures it requires.
+func (p *PartyHostingProposal) FullyAuthorized() bool {
+	return len(p.MissingSignatures) == 0
+}
+
+// ProposePartyHosting proposes changes to the hosting of party in store, signing the proposal
+// with the keys of the participant that are authorized to. The current hosting of the party is
+// read from store; parties that are not hosted yet start from an empty mapping with threshold 1.
+// Other participants and external parties authorize the proposal by its transaction hash.
+func ProposePartyHosting(ctx context.Context, read TopologyManagerRead, write TopologyManagerWrite, store *model.StoreID, party string, changes ...PartyHostingChange) (*PartyHostingProposal, error) {
+	resp, err := read.ListPartyToParticipant(ctx, &model.ListPartyToParticipantRequest{
+		BaseQuery:   &model.BaseQuery{Store: store},
+		FilterParty: party,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list party to participant mappings: %w", err)
+	}
+
+	current := &model.PartyToParticipantMapping{Party: party, Threshold: 1}
+	var serial uint32
+	for _, result := range resp.Results {
+		if result.Item == nil || result.Item.Party != party || result.Context == nil || result.Context.ValidUntil != nil {
+			continue
+		}
+		current = result.Item
+		serial = uint32(result.Context.Serial)
+	}
+
+	mapping, err := ApplyPartyHostingChanges(current, changes...)
+	if err != nil {
+		return nil, err
+	}
+
+	authorized, err := write.Authorize(ctx, &model.AuthorizeRequest{
+		Proposal: &model.TopologyTransactionProposal{
+			Operation: model.OperationAddReplace,
+			Mapping:   mapping,
+			Serial:    serial + 1,
+		},
+		Store: store,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorize party hosting proposal: %w", err)
+	}
+
+	return &PartyHostingProposal{
+		Mapping:           mapping,
+		Serial:            serial + 1,
+		Transaction:       authorized.Transaction,
+		MissingSignatures: missingHostingSignatures(current, mapping, authorized.Transaction),
+	}, nil
+}
+
+func missingHostingSignatures(current, proposed *model.PartyToParticipantMapping, transaction *model.SignedTopologyTransaction) []string {
+	required := []string{uidNamespace(proposed.Party)}
+	for _, p := range proposed.Participants {
+		if hostingParticipantIndex(current, p.ParticipantUID) < 0 {
+			required = append(required, uidNamespace(p.ParticipantUID))
+		}
+	}
+
+	signed := make(map[string]bool)
+	if transaction != nil {
+		for _, s := range transaction.Signatures {
+			signed[s.SignedBy] = true
+		}
+		for _, multi := range transaction.MultiTransactionSignatures {
+			for _, s := range multi.Signatures {
+				signed[s.SignedBy] = true
+			}
+		}
+	}
+
+	var missing []string
+	for _, namespace := range required {
+		if !signed[namespace] && !slices.Contains(missing, namespace) {
+			missing = append(missing, namespace)
+		}
+	}
+	return missing
+}
+
+func hostingParticipantIndex(mapping *model.PartyToParticipantMapping, participantUID string) int {
+	return slices.IndexFunc(mapping.Participants, func(p model.HostingParticipant) bool {
+		return p.ParticipantUID == participantUID
+	})
+}
+
+// uidNamespace returns the namespace of a unique identifier such as a party or participant ID,
+// identifier::namespace.
+func uidNamespace(uid string) string {
+	if i := strings.LastIndex(uid, "::"); i >= 0 {
+		return uid[i+2:]
+	}
+	return uid
+}
@@ -0,0 +1,139 @@
+package topology
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+const (
+	testParty        = "alice::1220aa"
+	testParticipant1 = "participant1::1220p1"
+	testParticipant2 = "participant2::1220p2"
+)
+
+type fakeTopologyManager struct {
+	TopologyManagerRead
+	TopologyManagerWrite
+	current   *model.PartyToParticipantResult
+	signedBy  []string
+	authorize *model.AuthorizeRequest
+}
+
+func (f *fakeTopologyManager) ListPartyToParticipant(context.Context, *model.ListPartyToParticipantRequest) (*model.ListPartyToParticipantResponse, error) {
+	resp := &model.ListPartyToParticipantResponse{}
+	if f.current != nil {
+		resp.Results = append(resp.Results, f.current)
+	}
+	return resp, nil
+}
+
+func (f *fakeTopologyManager) Authorize(_ context.Context, req *model.AuthorizeRequest) (*model.AuthorizeResponse, error) {
+	f.authorize = req
+	tx := &model.SignedTopologyTransaction{Transaction: []byte("proposal"), Proposal: true}
+	for _, signedBy := range f.signedBy {
+		tx.Signatures = append(tx.Signatures, model.TopologyTransactionSignature{SignedBy: signedBy})
+	}
+	return &model.AuthorizeResponse{Transaction: tx}, nil
+}
+
+func hostedOnParticipant1() *model.PartyToParticipantMapping {
+	return &model.PartyToParticipantMapping{
+		Party:     testParty,
+		Threshold: 1,
+		Participants: []model.HostingParticipant{
+			{ParticipantUID: testParticipant1, Permission: model.ParticipantPermissionConfirmation},
+		},
+	}
+}
+
+func TestApplyPartyHostingChanges(t *testing.T) {
+	current := hostedOnParticipant1()
+
+	changed, err := ApplyPartyHostingChanges(current,
+		AddHostingParticipant(testParticipant2, model.ParticipantPermissionConfirmation),
+		ChangeHostingPermission(testParticipant1, model.ParticipantPermissionSubmission),
+		ChangeHostingThreshold(2),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), changed.Threshold)
+	require.Equal(t, []model.HostingParticipant{
+		{ParticipantUID: testParticipant1, Permission: model.ParticipantPermissionSubmission},
+		{ParticipantUID: testParticipant2, Permission: model.ParticipantPermissionConfirmation},
+	}, changed.Participants)
+	// The current mapping is left untouched
+	require.Equal(t, hostedOnParticipant1(), current)
+
+	changed, err = ApplyPartyHostingChanges(changed, RemoveHostingParticipant(testParticipant2), ChangeHostingThreshold(1))
+	require.NoError(t, err)
+	require.Len(t, changed.Participants, 1)
+
+	tests := []struct {
+		name    string
+		changes []PartyHostingChange
+		err     string
+	}{
+		{"add hosting participant twice", []PartyHostingChange{AddHostingParticipant(testParticipant1, model.ParticipantPermissionObservation)}, "already hosted"},
+		{"remove unknown participant", []PartyHostingChange{RemoveHostingParticipant(testParticipant2)}, "not hosted"},
+		{"change permission of unknown participant", []PartyHostingChange{ChangeHostingPermission(testParticipant2, model.ParticipantPermissionObservation)}, "not hosted"},
+		{"remove last participant", []PartyHostingChange{RemoveHostingParticipant(testParticipant1)}, "at least one participant"},
+		{"threshold above confirming participants", []PartyHostingChange{
+			AddHostingParticipant(testParticipant2, model.ParticipantPermissionObservation),
+			ChangeHostingThreshold(2),
+		}, "between 1 and its 1 confirming participants"},
+		{"zero threshold", []PartyHostingChange{ChangeHostingThreshold(0)}, "between 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ApplyPartyHostingChanges(current, tt.changes...)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestProposePartyHosting(t *testing.T) {
+	store := &model.StoreID{Value: "synchronizer:sync::1220cd"}
+	manager := &fakeTopologyManager{
+		current: &model.PartyToParticipantResult{
+			Context: &model.BaseResult{Serial: 3},
+			Item:    hostedOnParticipant1(),
+		},
+		signedBy: []string{"1220p1"},
+	}
+
+	proposal, err := ProposePartyHosting(t.Context(), manager, manager, store, testParty,
+		AddHostingParticipant(testParticipant2, model.ParticipantPermissionConfirmation),
+		ChangeHostingThreshold(2),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint32(4), proposal.Serial)
+	require.Equal(t, uint32(4), manager.authorize.Proposal.Serial)
+	require.Equal(t, model.OperationAddReplace, manager.authorize.Proposal.Operation)
+	require.Equal(t, proposal.Mapping, manager.authorize.Proposal.Mapping)
+	require.Equal(t, store, manager.authorize.Store)
+	require.Len(t, proposal.Mapping.Participants, 2)
+
+	// The party and the newly added participant have to authorize the proposal
+	require.Equal(t, []string{"1220aa", "1220p2"}, proposal.MissingSignatures)
+	require.False(t, proposal.FullyAuthorized())
+
+	manager.signedBy = []string{"1220aa"}
+	proposal, err = ProposePartyHosting(t.Context(), manager, manager, store, testParty,
+		ChangeHostingPermission(testParticipant1, model.ParticipantPermissionSubmission),
+	)
+	require.NoError(t, err)
+	require.True(t, proposal.FullyAuthorized())
+
+	// Parties that are not hosted yet start from serial 1
+	manager.current = nil
+	proposal, err = ProposePartyHosting(t.Context(), manager, manager, store, testParty,
+		AddHostingParticipant(testParticipant1, model.ParticipantPermissionConfirmation),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), proposal.Serial)
+	require.Equal(t, []string{"1220p1"}, proposal.MissingSignatures)
+}
@@ -2,6 +2,7 @@ package topology
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"google.golang.org/grpc"
@@ -17,6 +18,16 @@ type TopologyManagerRead interface {
 	ListNamespaceDelegation(ctx context.Context, req *model.ListNamespaceDelegationRequest) (*model.ListNamespaceDelegationResponse, error)
 	ListPartyToKeyMapping(ctx context.Context, req *model.ListPartyToKeyMappingRequest) (*model.ListPartyToKeyMappingResponse, error)
 	ListPartyToParticipant(ctx context.Context, req *model.ListPartyToParticipantRequest) (*model.ListPartyToParticipantResponse, error)
+	ListVettedPackages(ctx context.Context, req *model.ListVettedPackagesRequest) (*model.ListVettedPackagesResponse, error)
+	ListOwnerToKeyMapping(ctx context.Context, req *model.ListOwnerToKeyMappingRequest) (*model.ListOwnerToKeyMappingResponse, error)
+	ListSynchronizerParametersState(ctx context.Context, req *model.ListSynchronizerParametersStateRequest) (*model.ListSynchronizerParametersStateResponse, error)
+	ListDecentralizedNamespaceDefinition(ctx context.Context, req *model.ListDecentralizedNamespaceDefinitionRequest) (*model.ListDecentralizedNamespaceDefinitionResponse, error)
+	ListMediatorSynchronizerState(ctx context.Context, req *model.ListMediatorSynchronizerStateRequest) (*model.ListMediatorSynchronizerStateResponse, error)
+	ListSequencerSynchronizerState(ctx context.Context, req *model.ListSequencerSynchronizerStateRequest) (*model.ListSequencerSynchronizerStateResponse, error)
+	ListAll(ctx context.Context, req *model.ListAllRequest) (*model.ListAllResponse, error)
+	// ExportTopologySnapshot returns the serialized topology transactions of a store, which can be
+	// imported into another store with ImportTopologySnapshot.
+	ExportTopologySnapshot(ctx context.Context, req *model.ExportTopologySnapshotRequest) ([]byte, error)
 }
 
 type topologyManagerRead struct {
@@ -63,6 +74,104 @@ func (c *topologyManagerRead) ListPartyToParticipant(ctx context.Context, req *m
 	return listPartyToParticipantResponseFromProto(resp), nil
 }
 
+func (c *topologyManagerRead) ListVettedPackages(ctx context.Context, req *model.ListVettedPackagesRequest) (*model.ListVettedPackagesResponse, error) {
+	protoReq := listVettedPackagesRequestToProto(req)
+
+	resp, err := c.client.ListVettedPackages(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return listVettedPackagesResponseFromProto(resp), nil
+}
+
+func (c *topologyManagerRead) ListOwnerToKeyMapping(ctx context.Context, req *model.ListOwnerToKeyMappingRequest) (*model.ListOwnerToKeyMappingResponse, error) {
+	protoReq := listOwnerToKeyMappingRequestToProto(req)
+
+	resp, err := c.client.ListOwnerToKeyMapping(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return listOwnerToKeyMappingResponseFromProto(resp), nil
+}
+
+func (c *topologyManagerRead) ListSynchronizerParametersState(ctx context.Context, req *model.ListSynchronizerParametersStateRequest) (*model.ListSynchronizerParametersStateResponse, error) {
+	protoReq := listSynchronizerParametersStateRequestToProto(req)
+
+	resp, err := c.client.ListSynchronizerParametersState(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return listSynchronizerParametersStateResponseFromProto(resp), nil
+}
+
+func (c *topologyManagerRead) ListDecentralizedNamespaceDefinition(ctx context.Context, req *model.ListDecentralizedNamespaceDefinitionRequest) (*model.ListDecentralizedNamespaceDefinitionResponse, error) {
+	protoReq := listDecentralizedNamespaceDefinitionRequestToProto(req)
+
+	resp, err := c.client.ListDecentralizedNamespaceDefinition(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return listDecentralizedNamespaceDefinitionResponseFromProto(resp), nil
+}
+
+func (c *topologyManagerRead) ListMediatorSynchronizerState(ctx context.Context, req *model.ListMediatorSynchronizerStateRequest) (*model.ListMediatorSynchronizerStateResponse, error) {
+	protoReq := listMediatorSynchronizerStateRequestToProto(req)
+
+	resp, err := c.client.ListMediatorSynchronizerState(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return listMediatorSynchronizerStateResponseFromProto(resp), nil
+}
+
+func (c *topologyManagerRead) ListSequencerSynchronizerState(ctx context.Context, req *model.ListSequencerSynchronizerStateRequest) (*model.ListSequencerSynchronizerStateResponse, error) {
+	protoReq := listSequencerSynchronizerStateRequestToProto(req)
+
+	resp, err := c.client.ListSequencerSynchronizerState(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return listSequencerSynchronizerStateResponseFromProto(resp), nil
+}
+
+func (c *topologyManagerRead) ListAll(ctx context.Context, req *model.ListAllRequest) (*model.ListAllResponse, error) {
+	protoReq := listAllRequestToProto(req)
+
+	resp, err := c.client.ListAll(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return listAllResponseFromProto(resp), nil
+}
+
+func (c *topologyManagerRead) ExportTopologySnapshot(ctx context.Context, req *model.ExportTopologySnapshotRequest) ([]byte, error) {
+	protoReq := exportTopologySnapshotRequestToProto(req)
+
+	stream, err := c.client.ExportTopologySnapshot(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot []byte
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return snapshot, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		snapshot = append(snapshot, resp.Chunk...)
+	}
+}
+
 func listNamespaceDelegationRequestToProto(req *model.ListNamespaceDelegationRequest) *topov30.ListNamespaceDelegationRequest {
 	if req == nil {
 		return nil
@@ -143,6 +252,221 @@ func listPartyToParticipantResponseFromProto(pb *topov30.ListPartyToParticipantR
 	}
 }
 
+func listVettedPackagesRequestToProto(req *model.ListVettedPackagesRequest) *topov30.ListVettedPackagesRequest {
+	if req == nil {
+		return nil
+	}
+
+	return &topov30.ListVettedPackagesRequest{
+		BaseQuery:         baseQueryToProto(req.BaseQuery),
+		FilterParticipant: req.FilterParticipant,
+	}
+}
+
+func listVettedPackagesResponseFromProto(pb *topov30.ListVettedPackagesResponse) *model.ListVettedPackagesResponse {
+	if pb == nil {
+		return nil
+	}
+
+	results := make([]*model.VettedPackagesResult, len(pb.Results))
+	for i, r := range pb.Results {
+		results[i] = &model.VettedPackagesResult{
+			Context: baseResultFromProto(r.Context),
+			Item:    vettedPackagesFromProto(r.Item),
+		}
+	}
+
+	return &model.ListVettedPackagesResponse{
+		Results: results,
+	}
+}
+
+func listOwnerToKeyMappingRequestToProto(req *model.ListOwnerToKeyMappingRequest) *topov30.ListOwnerToKeyMappingRequest {
+	if req == nil {
+		return nil
+	}
+
+	return &topov30.ListOwnerToKeyMappingRequest{
+		BaseQuery:          baseQueryToProto(req.BaseQuery),
+		FilterKeyOwnerType: req.FilterKeyOwnerType,
+		FilterKeyOwnerUid:  req.FilterKeyOwnerUID,
+	}
+}
+
+func listOwnerToKeyMappingResponseFromProto(pb *topov30.ListOwnerToKeyMappingResponse) *model.ListOwnerToKeyMappingResponse {
+	if pb == nil {
+		return nil
+	}
+
+	results := make([]*model.OwnerToKeyMappingResult, len(pb.Results))
+	for i, r := range pb.Results {
+		results[i] = &model.OwnerToKeyMappingResult{
+			Context: baseResultFromProto(r.Context),
+			Item:    ownerToKeyMappingFromProto(r.Item),
+		}
+	}
+
+	return &model.ListOwnerToKeyMappingResponse{
+		Results: results,
+	}
+}
+
+func listSynchronizerParametersStateRequestToProto(req *model.ListSynchronizerParametersStateRequest) *topov30.ListSynchronizerParametersStateRequest {
+	if req == nil {
+		return nil
+	}
+
+	return &topov30.ListSynchronizerParametersStateRequest{
+		BaseQuery:            baseQueryToProto(req.BaseQuery),
+		FilterSynchronizerId: req.FilterSynchronizerID,
+	}
+}
+
+func listSynchronizerParametersStateResponseFromProto(pb *topov30.ListSynchronizerParametersStateResponse) *model.ListSynchronizerParametersStateResponse {
+	if pb == nil {
+		return nil
+	}
+
+	results := make([]*model.SynchronizerParametersResult, len(pb.Results))
+	for i, r := range pb.Results {
+		results[i] = &model.SynchronizerParametersResult{
+			Context: baseResultFromProto(r.Context),
+			Item:    dynamicSynchronizerParametersFromProto(r.Item),
+		}
+	}
+
+	return &model.ListSynchronizerParametersStateResponse{
+		Results: results,
+	}
+}
+
+func listDecentralizedNamespaceDefinitionRequestToProto(req *model.ListDecentralizedNamespaceDefinitionRequest) *topov30.ListDecentralizedNamespaceDefinitionRequest {
+	if req == nil {
+		return nil
+	}
+
+	return &topov30.ListDecentralizedNamespaceDefinitionRequest{
+		BaseQuery:       baseQueryToProto(req.BaseQuery),
+		FilterNamespace: req.FilterNamespace,
+	}
+}
+
+func listDecentralizedNamespaceDefinitionResponseFromProto(pb *topov30.ListDecentralizedNamespaceDefinitionResponse) *model.ListDecentralizedNamespaceDefinitionResponse {
+	if pb == nil {
+		return nil
+	}
+
+	results := make([]*model.DecentralizedNamespaceDefinitionResult, len(pb.Results))
+	for i, r := range pb.Results {
+		results[i] = &model.DecentralizedNamespaceDefinitionResult{
+			Context: baseResultFromProto(r.Context),
+			Item:    decentralizedNamespaceDefinitionFromProto(r.Item),
+		}
+	}
+
+	return &model.ListDecentralizedNamespaceDefinitionResponse{
+		Results: results,
+	}
+}
+
+func listMediatorSynchronizerStateRequestToProto(req *model.ListMediatorSynchronizerStateRequest) *topov30.ListMediatorSynchronizerStateRequest {
+	if req == nil {
+		return nil
+	}
+
+	return &topov30.ListMediatorSynchronizerStateRequest{
+		BaseQuery:            baseQueryToProto(req.BaseQuery),
+		FilterSynchronizerId: req.FilterSynchronizerID,
+	}
+}
+
+func listMediatorSynchronizerStateResponseFromProto(pb *topov30.ListMediatorSynchronizerStateResponse) *model.ListMediatorSynchronizerStateResponse {
+	if pb == nil {
+		return nil
+	}
+
+	results := make([]*model.MediatorSynchronizerStateResult, len(pb.Results))
+	for i, r := range pb.Results {
+		results[i] = &model.MediatorSynchronizerStateResult{
+			Context: baseResultFromProto(r.Context),
+			Item:    mediatorSynchronizerStateFromProto(r.Item),
+		}
+	}
+
+	return &model.ListMediatorSynchronizerStateResponse{
+		Results: results,
+	}
+}
+
+func listSequencerSynchronizerStateRequestToProto(req *model.ListSequencerSynchronizerStateRequest) *topov30.ListSequencerSynchronizerStateRequest {
+	if req == nil {
+		return nil
+	}
+
+	return &topov30.ListSequencerSynchronizerStateRequest{
+		BaseQuery:            baseQueryToProto(req.BaseQuery),
+		FilterSynchronizerId: req.FilterSynchronizerID,
+	}
+}
+
+func listSequencerSynchronizerStateResponseFromProto(pb *topov30.ListSequencerSynchronizerStateResponse) *model.ListSequencerSynchronizerStateResponse {
+	if pb == nil {
+		return nil
+	}
+
+	results := make([]*model.SequencerSynchronizerStateResult, len(pb.Results))
+	for i, r := range pb.Results {
+		results[i] = &model.SequencerSynchronizerStateResult{
+			Context: baseResultFromProto(r.Context),
+			Item:    sequencerSynchronizerStateFromProto(r.Item),
+		}
+	}
+
+	return &model.ListSequencerSynchronizerStateResponse{
+		Results: results,
+	}
+}
+
+func listAllRequestToProto(req *model.ListAllRequest) *topov30.ListAllRequest {
+	if req == nil {
+		return nil
+	}
+
+	return &topov30.ListAllRequest{
+		BaseQuery:       baseQueryToProto(req.BaseQuery),
+		ExcludeMappings: req.ExcludeMappings,
+		FilterNamespace: req.FilterNamespace,
+	}
+}
+
+func listAllResponseFromProto(pb *topov30.ListAllResponse) *model.ListAllResponse {
+	if pb == nil {
+		return nil
+	}
+
+	items := pb.GetResult().GetItems()
+	transactions := make([]*model.StoredTopologyTransaction, len(items))
+	for i, item := range items {
+		transactions[i] = storedTopologyTransactionFromProto(item)
+	}
+
+	return &model.ListAllResponse{
+		Transactions: transactions,
+	}
+}
+
+func exportTopologySnapshotRequestToProto(req *model.ExportTopologySnapshotRequest) *topov30.ExportTopologySnapshotRequest {
+	if req == nil {
+		return nil
+	}
+
+	return &topov30.ExportTopologySnapshotRequest{
+		BaseQuery:       baseQueryToProto(req.BaseQuery),
+		ExcludeMappings: req.ExcludeMappings,
+		FilterNamespace: req.FilterNamespace,
+	}
+}
+
 func baseQueryToProto(query *model.BaseQuery) *topov30.BaseQuery {
 	if query == nil {
 		return nil
@@ -338,6 +662,131 @@ func partyToParticipantMappingFromProto(pb *protov30.PartyToParticipant) *model.
 	}
 }
 
+func vettedPackagesFromProto(pb *protov30.VettedPackages) *model.VettedPackages {
+	if pb == nil {
+		return nil
+	}
+
+	packages := make([]model.VettedPackage, 0, len(pb.Packages)+len(pb.PackageIds))
+	for _, p := range pb.Packages {
+		packages = append(packages, model.VettedPackage{
+			PackageID:  p.PackageId,
+			ValidFrom:  timestampFromProto(p.ValidFromInclusive),
+			ValidUntil: timestampFromProto(p.ValidUntilExclusive),
+		})
+	}
+	// Vetted packages without validity bounds of older protocol versions
+	for _, packageID := range pb.PackageIds {
+		packages = append(packages, model.VettedPackage{PackageID: packageID})
+	}
+
+	return &model.VettedPackages{
+		ParticipantUID: pb.ParticipantUid,
+		Packages:       packages,
+	}
+}
+
+func ownerToKeyMappingFromProto(pb *protov30.OwnerToKeyMapping) *model.OwnerToKeyMapping {
+	if pb == nil {
+		return nil
+	}
+
+	mapping := &model.OwnerToKeyMapping{
+		Member: pb.Member,
+	}
+	for _, k := range pb.PublicKeys {
+		switch key := k.Key.(type) {
+		case *cryptov30.PublicKey_SigningPublicKey:
+			mapping.SigningKeys = append(mapping.SigningKeys, signingPublicKeyFromProto(key.SigningPublicKey))
+		case *cryptov30.PublicKey_EncryptionPublicKey:
+			mapping.EncryptionKeys = append(mapping.EncryptionKeys, encryptionPublicKeyFromProto(key.EncryptionPublicKey))
+		}
+	}
+
+	return mapping
+}
+
+func dynamicSynchronizerParametersFromProto(pb *protov30.DynamicSynchronizerParameters) *model.DynamicSynchronizerParameters {
+	if pb == nil {
+		return nil
+	}
+
+	return &model.DynamicSynchronizerParameters{
+		ConfirmationResponseTimeout:         pb.ConfirmationResponseTimeout.AsDuration(),
+		MediatorReactionTimeout:             pb.MediatorReactionTimeout.AsDuration(),
+		AssignmentExclusivityTimeout:        pb.AssignmentExclusivityTimeout.AsDuration(),
+		LedgerTimeRecordTimeTolerance:       pb.LedgerTimeRecordTimeTolerance.AsDuration(),
+		ReconciliationInterval:              pb.ReconciliationInterval.AsDuration(),
+		MediatorDeduplicationTimeout:        pb.MediatorDeduplicationTimeout.AsDuration(),
+		MaxRequestSize:                      pb.MaxRequestSize,
+		OnboardingRestriction:               model.OnboardingRestriction(pb.OnboardingRestriction),
+		ConfirmationRequestsMaxRate:         pb.GetParticipantSynchronizerLimits().GetConfirmationRequestsMaxRate(),
+		SequencerAggregateSubmissionTimeout: pb.SequencerAggregateSubmissionTimeout.AsDuration(),
+		PreparationTimeRecordTimeTolerance:  pb.PreparationTimeRecordTimeTolerance.AsDuration(),
+	}
+}
+
+func decentralizedNamespaceDefinitionFromProto(pb *protov30.DecentralizedNamespaceDefinition) *model.DecentralizedNamespaceDefinition {
+	if pb == nil {
+		return nil
+	}
+
+	return &model.DecentralizedNamespaceDefinition{
+		DecentralizedNamespace: pb.DecentralizedNamespace,
+		Threshold:              pb.Threshold,
+		Owners:                 pb.Owners,
+	}
+}
+
+func mediatorSynchronizerStateFromProto(pb *protov30.MediatorSynchronizerState) *model.MediatorSynchronizerState {
+	if pb == nil {
+		return nil
+	}
+
+	return &model.MediatorSynchronizerState{
+		SynchronizerID: pb.SynchronizerId,
+		Group:          pb.Group,
+		Threshold:      pb.Threshold,
+		Active:         pb.Active,
+		Observers:      pb.Observers,
+	}
+}
+
+func sequencerSynchronizerStateFromProto(pb *protov30.SequencerSynchronizerState) *model.SequencerSynchronizerState {
+	if pb == nil {
+		return nil
+	}
+
+	return &model.SequencerSynchronizerState{
+		SynchronizerID: pb.SynchronizerId,
+		Threshold:      pb.Threshold,
+		Active:         pb.Active,
+		Observers:      pb.Observers,
+	}
+}
+
+func storedTopologyTransactionFromProto(pb *topov30.TopologyTransactions_Item) *model.StoredTopologyTransaction {
+	if pb == nil {
+		return nil
+	}
+
+	return &model.StoredTopologyTransaction{
+		Sequenced:       timestampFromProto(pb.Sequenced),
+		ValidFrom:       timestampFromProto(pb.ValidFrom),
+		ValidUntil:      timestampFromProto(pb.ValidUntil),
+		Transaction:     pb.Transaction,
+		RejectionReason: pb.RejectionReason,
+	}
+}
+
+func timestampFromProto(pb *timestamppb.Timestamp) *time.Time {
+	if pb == nil {
+		return nil
+	}
+	t := pb.AsTime()
+	return &t
+}
+
 func participantPermissionFromProto(pp protov30.Enums_ParticipantPermission) model.ParticipantPermission {
 	switch pp {
 	case protov30.Enums_PARTICIPANT_PERMISSION_CONFIRMATION:
@@ -350,12 +799,31 @@ func participantPermissionFromProto(pp protov30.Enums_ParticipantPermission) mod
 }
 
 func signingPublicKeyFromProto(pb *cryptov30.SigningPublicKey) model.PublicKey {
+	if pb == nil {
+		return model.PublicKey{}
+	}
+	usage := make([]int32, len(pb.Usage))
+	for i, u := range pb.Usage {
+		usage[i] = int32(u)
+	}
+	return model.PublicKey{
+		Format:  int32(pb.Format),
+		Key:     pb.PublicKey,
+		ID:      "",
+		Scheme:  int32(pb.Scheme),
+		KeySpec: int32(pb.KeySpec),
+		Usage:   usage,
+	}
+}
+
+func encryptionPublicKeyFromProto(pb *cryptov30.EncryptionPublicKey) model.PublicKey {
 	if pb == nil {
 		return model.PublicKey{}
 	}
 	return model.PublicKey{
-		Format: int32(pb.Format),
-		Key:    pb.PublicKey,
-		ID:     "",
+		Format:  int32(pb.Format),
+		Key:     pb.PublicKey,
+		Scheme:  int32(pb.Scheme),
+		KeySpec: int32(pb.KeySpec),
 	}
 }
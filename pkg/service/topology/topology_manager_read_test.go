@@ -0,0 +1,98 @@
+package topology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	cryptov30 "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/canton/crypto/v30"
+	protov30 "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/canton/protocol/v30"
+	topov30 "github.com/digital-asset/dazl-client/v8/go/api/com/digitalasset/canton/topology/admin/v30"
+	"github.com/smartcontractkit/go-daml/pkg/model"
+)
+
+func TestVettedPackagesFromProto(t *testing.T) {
+	validFrom := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	vetted := vettedPackagesFromProto(&protov30.VettedPackages{
+		ParticipantUid: "participant1::1220p1",
+		PackageIds:     []string{"legacy"},
+		Packages: []*protov30.VettedPackages_VettedPackage{
+			{PackageId: "bounded", ValidFromInclusive: timestamppb.New(validFrom)},
+		},
+	})
+
+	require.Equal(t, "participant1::1220p1", vetted.ParticipantUID)
+	require.Equal(t, []model.VettedPackage{
+		{PackageID: "bounded", ValidFrom: &validFrom},
+		{PackageID: "legacy"},
+	}, vetted.Packages)
+}
+
+func TestOwnerToKeyMappingFromProto(t *testing.T) {
+	mapping := ownerToKeyMappingFromProto(&protov30.OwnerToKeyMapping{
+		Member: "PAR::participant1::1220p1",
+		PublicKeys: []*cryptov30.PublicKey{
+			{Key: &cryptov30.PublicKey_SigningPublicKey{SigningPublicKey: &cryptov30.SigningPublicKey{
+				Format:    cryptov30.CryptoKeyFormat_CRYPTO_KEY_FORMAT_RAW,
+				PublicKey: []byte("signing"),
+				KeySpec:   cryptov30.SigningKeySpec_SIGNING_KEY_SPEC_EC_CURVE25519,
+				Usage:     []cryptov30.SigningKeyUsage{cryptov30.SigningKeyUsage_SIGNING_KEY_USAGE_PROTOCOL},
+			}}},
+			{Key: &cryptov30.PublicKey_EncryptionPublicKey{EncryptionPublicKey: &cryptov30.EncryptionPublicKey{
+				Format:    cryptov30.CryptoKeyFormat_CRYPTO_KEY_FORMAT_DER_X509_SUBJECT_PUBLIC_KEY_INFO,
+				PublicKey: []byte("encryption"),
+			}}},
+		},
+	})
+
+	require.Equal(t, "PAR::participant1::1220p1", mapping.Member)
+	require.Equal(t, []model.PublicKey{{
+		Format:  int32(model.CryptoKeyFormatRaw),
+		Key:     []byte("signing"),
+		KeySpec: int32(model.SigningKeySpecCurve25519),
+		Usage:   []int32{int32(model.SigningKeyUsageProtocol)},
+	}}, mapping.SigningKeys)
+	require.Equal(t, []model.PublicKey{{
+		Format: int32(model.CryptoKeyFormatDERX509SubjectPublicKeyInfo),
+		Key:    []byte("encryption"),
+	}}, mapping.EncryptionKeys)
+}
+
+func TestDynamicSynchronizerParametersFromProto(t *testing.T) {
+	parameters := dynamicSynchronizerParametersFromProto(&protov30.DynamicSynchronizerParameters{
+		ConfirmationResponseTimeout:   durationpb.New(30 * time.Second),
+		LedgerTimeRecordTimeTolerance: durationpb.New(time.Minute),
+		MaxRequestSize:                10 << 20,
+		OnboardingRestriction:         protov30.OnboardingRestriction_ONBOARDING_RESTRICTION_RESTRICTED_OPEN,
+		ParticipantSynchronizerLimits: &protov30.ParticipantSynchronizerLimits{ConfirmationRequestsMaxRate: 100},
+	})
+
+	require.Equal(t, 30*time.Second, parameters.ConfirmationResponseTimeout)
+	require.Equal(t, time.Minute, parameters.LedgerTimeRecordTimeTolerance)
+	require.Zero(t, parameters.MediatorReactionTimeout)
+	require.Equal(t, uint32(10<<20), parameters.MaxRequestSize)
+	require.Equal(t, model.OnboardingRestrictionRestrictedOpen, parameters.OnboardingRestriction)
+	require.Equal(t, uint32(100), parameters.ConfirmationRequestsMaxRate)
+}
+
+func TestListAllResponseFromProto(t *testing.T) {
+	validFrom := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	reason := "rejected"
+
+	resp := listAllResponseFromProto(&topov30.ListAllResponse{
+		Result: &topov30.TopologyTransactions{
+			Items: []*topov30.TopologyTransactions_Item{
+				{ValidFrom: timestamppb.New(validFrom), Transaction: []byte("tx"), RejectionReason: &reason},
+			},
+		},
+	})
+
+	require.Equal(t, []*model.StoredTopologyTransaction{
+		{ValidFrom: &validFrom, Transaction: []byte("tx"), RejectionReason: &reason},
+	}, resp.Transactions)
+	require.Empty(t, listAllResponseFromProto(&topov30.ListAllResponse{}).Transactions)
+}
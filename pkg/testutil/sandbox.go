@@ -12,6 +12,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 
+	"github.com/smartcontractkit/go-daml/pkg/auth"
 	"github.com/smartcontractkit/go-daml/pkg/client"
 )
 
@@ -19,16 +20,51 @@ const (
 	DamlSandboxImage   = "digitalasset/daml-sdk"
 	DamlSandboxVersion = "3.5.0-snapshot.20251106.0"
 	SandboxUserId      = "app-provider"
+	// SandboxAdminUserId is the user Canton creates with participant admin rights
+	SandboxAdminUserId = "participant_admin"
+	// SandboxAudience is the target audience of the ledger API when auth is configured
+	SandboxAudience = "https://daml.com/jwt/aud/participant/sandbox"
 )
 
 type Output struct {
 	Container     testcontainers.Container
 	BindingClient *client.DamlBindingClient
+	GRPCAddress   string
+	AdminAddress  string
+	// Signer mints tokens accepted by the sandbox, nil unless auth is configured
+	Signer *auth.JWTSigner
 }
 
-func CreateSandbox(t *testing.T) (*Output, error) {
+type sandboxConfig struct {
+	hmacSecret string
+}
+
+type SandboxOption func(*sandboxConfig)
+
+// WithHMACAuth enables the unsafe-jwt-hmac-256 auth service on the ledger API with secret.
+// The sandbox client then authenticates as SandboxAdminUserId with tokens signed by Output.Signer.
+func WithHMACAuth(secret string) SandboxOption {
+	return func(c *sandboxConfig) {
+		c.hmacSecret = secret
+	}
+}
+
+// TokenProvider returns a provider of audience-based tokens for userID, or nil if auth is not configured.
+func (o *Output) TokenProvider(userID string) auth.TokenProvider {
+	if o.Signer == nil {
+		return nil
+	}
+	return o.Signer.TokenProvider(auth.CantonToken{UserID: userID, Audience: SandboxAudience})
+}
+
+func CreateSandbox(t *testing.T, opts ...SandboxOption) (*Output, error) {
 	t.Helper()
 
+	cfg := &sandboxConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	// Allocate two ports
 	ports := freeport.GetN(t, 2)
 	exposedPorts := []string{
@@ -43,7 +79,7 @@ func CreateSandbox(t *testing.T) (*Output, error) {
 		WaitingFor:   wait.ForLog("Canton sandbox is ready.").WithStartupTimeout(time.Minute * 10),
 		Files: []testcontainers.ContainerFile{
 			{
-				Reader:            strings.NewReader(GetCantonConfig()),
+				Reader:            strings.NewReader(cantonConfig(cfg)),
 				ContainerFilePath: "/canton/canton.conf",
 				FileMode:          0755,
 			},
@@ -67,7 +103,19 @@ func CreateSandbox(t *testing.T) (*Output, error) {
 	grpcAddress := fmt.Sprintf("localhost:%d", ports[0])
 	adminAddress := fmt.Sprintf("localhost:%d", ports[1])
 
-	c, err := client.NewDamlClient("", grpcAddress).WithAdminAddress(adminAddress).Build(t.Context())
+	output := &Output{
+		Container:    container,
+		GRPCAddress:  grpcAddress,
+		AdminAddress: adminAddress,
+	}
+
+	damlClient := client.NewDamlClient("", grpcAddress).WithAdminAddress(adminAddress)
+	if cfg.hmacSecret != "" {
+		output.Signer = auth.NewHS256Signer([]byte(cfg.hmacSecret))
+		damlClient = damlClient.WithTokenProvider(output.TokenProvider(SandboxAdminUserId))
+	}
+
+	c, err := damlClient.Build(t.Context())
 	if err != nil {
 		return nil, fmt.Errorf("failed to build daml client: %w", err)
 	}
@@ -106,15 +154,26 @@ func CreateSandbox(t *testing.T) (*Output, error) {
 
 	log.Info().Msg("Sandbox ready")
 
-	return &Output{
-		Container:     container,
-		BindingClient: c,
-	}, nil
+	output.BindingClient = c
+	return output, nil
 }
 
 func GetCantonConfig() string {
+	return cantonConfig(&sandboxConfig{})
+}
+
+func cantonConfig(cfg *sandboxConfig) string {
+	authServices := ""
+	if cfg.hmacSecret != "" {
+		authServices = fmt.Sprintf(`auth-services = [{
+          type = unsafe-jwt-hmac-256
+          secret = %q
+          target-audience = %q
+        }]`, cfg.hmacSecret, SandboxAudience)
+	}
+
 	//language=HOCON
-	return `
+	return fmt.Sprintf(`
 canton {
   mediators {
     mediator1 {
@@ -143,9 +202,10 @@ canton {
         address = "0.0.0.0"
         port = 6865
         user-management-service.enabled = true
+        %s
       }
     }
   }
 }
-	`
+	`, authServices)
 }
@@ -0,0 +1,49 @@
+package testutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smartcontractkit/go-daml/pkg/client"
+	"github.com/smartcontractkit/go-daml/pkg/testutil"
+)
+
+func TestCreateSandboxWithHMACAuth(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	t.Parallel()
+	ctx := t.Context()
+
+	sandbox, err := testutil.CreateSandbox(t, testutil.WithHMACAuth("sandbox-test-secret"))
+	require.NoError(t, err)
+	require.NotNil(t, sandbox.Signer)
+
+	unauthenticated, err := client.NewDamlClient("", sandbox.GRPCAddress).
+		WithAdminAddress(sandbox.AdminAddress).
+		Build(ctx)
+	require.NoError(t, err)
+	defer unauthenticated.Close()
+
+	_, err = unauthenticated.UserMng.ListUsers(ctx)
+	require.Error(t, err)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	signed, err := client.NewDamlClient("", sandbox.GRPCAddress).
+		WithAdminAddress(sandbox.AdminAddress).
+		WithTokenProvider(sandbox.TokenProvider(testutil.SandboxAdminUserId)).
+		Build(ctx)
+	require.NoError(t, err)
+	defer signed.Close()
+
+	users, err := signed.UserMng.ListUsers(ctx)
+	require.NoError(t, err)
+
+	var userIDs []string
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	require.Contains(t, userIDs, testutil.SandboxUserId)
+}
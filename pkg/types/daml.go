@@ -29,8 +29,27 @@ type (
 		First  interface{}
 		Second interface{}
 	}
+	TUPLE3 struct {
+		First  interface{}
+		Second interface{}
+		Third  interface{}
+	}
 )
 
+// DAY_OF_WEEK is the DA.Date.Types:DayOfWeek enum of the DAML standard library, e.g. Monday
+type DAY_OF_WEEK string
+
+func (d DAY_OF_WEEK) GetEnumConstructor() string { return string(d) }
+
+func (d DAY_OF_WEEK) GetEnumTypeID() string { return "DA.Date.Types:DayOfWeek" }
+
+// MONTH is the DA.Date.Types:Month enum of the DAML standard library, e.g. Jan
+type MONTH string
+
+func (m MONTH) GetEnumConstructor() string { return string(m) }
+
+func (m MONTH) GetEnumTypeID() string { return "DA.Date.Types:Month" }
+
 func NewNumericFromDecimal(d decimal.Decimal) NUMERIC {
 	// Convert decimal to string with 10 decimal places precision
 	scaled := d.Shift(10)
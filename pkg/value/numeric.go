@@ -0,0 +1,70 @@
+package value
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxNumericScale is the largest scale of a Daml Numeric.
+const MaxNumericScale = 37
+
+// Numeric is a Daml Numeric of fixed scale, the number of digits after the decimal point. Its
+// value is Unscaled * 10^-Scale, so that 1.50 is {Unscaled: 150, Scale: 2}.
+type Numeric struct {
+	Unscaled *big.Int
+	Scale    int32
+}
+
+// NewNumeric returns the numeric unscaled * 10^-scale.
+func NewNumeric(unscaled *big.Int, scale int32) Numeric {
+	return Numeric{Unscaled: new(big.Int).Set(unscaled), Scale: scale}
+}
+
+// NumericFromDecimal returns d with scale digits after the decimal point, rounding d if it has more.
+func NumericFromDecimal(d decimal.Decimal, scale int32) Numeric {
+	return Numeric{Unscaled: d.Shift(scale).Round(0).BigInt(), Scale: scale}
+}
+
+// ParseNumeric parses a decimal numeric literal such as -12.50, whose scale is the number of
+// digits after its decimal point.
+func ParseNumeric(s string) (Numeric, error) {
+	digits := s
+	if strings.HasPrefix(digits, "-") {
+		digits = digits[1:]
+	}
+	integer, fraction, _ := strings.Cut(digits, ".")
+	if integer == "" || strings.ContainsFunc(integer+fraction, func(r rune) bool { return r < '0' || r > '9' }) {
+		return Numeric{}, fmt.Errorf("invalid numeric %q", s)
+	}
+	if len(fraction) > MaxNumericScale {
+		return Numeric{}, fmt.Errorf("numeric %q has more than %d digits after the decimal point", s, MaxNumericScale)
+	}
+
+	unscaled, ok := new(big.Int).SetString(strings.TrimSuffix(s, "."+fraction)+fraction, 10)
+	if !ok {
+		return Numeric{}, fmt.Errorf("invalid numeric %q", s)
+	}
+
+	return Numeric{Unscaled: unscaled, Scale: int32(len(fraction))}, nil
+}
+
+// String formats the numeric with Scale digits after the decimal point.
+func (n Numeric) String() string {
+	return n.Decimal().StringFixed(n.Scale)
+}
+
+// Decimal returns the numeric as a decimal.
+func (n Numeric) Decimal() decimal.Decimal {
+	if n.Unscaled == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Unscaled, -n.Scale)
+}
+
+// Rat returns the numeric as a rational number.
+func (n Numeric) Rat() *big.Rat {
+	return n.Decimal().Rat()
+}
@@ -0,0 +1,206 @@
+package value
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+)
+
+// ToProto converts a value, or a pointer to one, to its Ledger API representation. A nil value
+// or pointer converts to nil.
+func ToProto(v Value) *v2.Value {
+	// Pointers implement Value too, through the value receiver methods
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		return ToProto(rv.Elem().Interface().(Value))
+	}
+
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case Unit:
+		return &v2.Value{Sum: &v2.Value_Unit{Unit: &emptypb.Empty{}}}
+	case Bool:
+		return &v2.Value{Sum: &v2.Value_Bool{Bool: bool(x)}}
+	case Int64:
+		return &v2.Value{Sum: &v2.Value_Int64{Int64: int64(x)}}
+	case Text:
+		return &v2.Value{Sum: &v2.Value_Text{Text: string(x)}}
+	case Numeric:
+		return &v2.Value{Sum: &v2.Value_Numeric{Numeric: x.String()}}
+	case Party:
+		return &v2.Value{Sum: &v2.Value_Party{Party: string(x)}}
+	case ContractID:
+		return &v2.Value{Sum: &v2.Value_ContractId{ContractId: string(x)}}
+	case Date:
+		return &v2.Value{Sum: &v2.Value_Date{Date: int32(x)}}
+	case Timestamp:
+		return &v2.Value{Sum: &v2.Value_Timestamp{Timestamp: int64(x)}}
+	case Record:
+		return &v2.Value{Sum: &v2.Value_Record{Record: RecordToProto(x)}}
+	case Variant:
+		return &v2.Value{Sum: &v2.Value_Variant{Variant: &v2.Variant{
+			VariantId:   identifierToProto(x.ID),
+			Constructor: x.Constructor,
+			Value:       ToProto(x.Value),
+		}}}
+	case Enum:
+		return &v2.Value{Sum: &v2.Value_Enum{Enum: &v2.Enum{
+			EnumId:      identifierToProto(x.ID),
+			Constructor: x.Constructor,
+		}}}
+	case List:
+		elements := make([]*v2.Value, len(x))
+		for i, elem := range x {
+			elements[i] = ToProto(elem)
+		}
+		return &v2.Value{Sum: &v2.Value_List{List: &v2.List{Elements: elements}}}
+	case Optional:
+		return &v2.Value{Sum: &v2.Value_Optional{Optional: &v2.Optional{Value: ToProto(x.Value)}}}
+	case TextMap:
+		entries := make([]*v2.TextMap_Entry, len(x))
+		for i, entry := range x {
+			entries[i] = &v2.TextMap_Entry{Key: entry.Key, Value: ToProto(entry.Value)}
+		}
+		return &v2.Value{Sum: &v2.Value_TextMap{TextMap: &v2.TextMap{Entries: entries}}}
+	case GenMap:
+		entries := make([]*v2.GenMap_Entry, len(x))
+		for i, entry := range x {
+			entries[i] = &v2.GenMap_Entry{Key: ToProto(entry.Key), Value: ToProto(entry.Value)}
+		}
+		return &v2.Value{Sum: &v2.Value_GenMap{GenMap: &v2.GenMap{Entries: entries}}}
+	default:
+		panic(fmt.Sprintf("unknown Daml value type %T", v))
+	}
+}
+
+// RecordToProto converts a record to its Ledger API representation.
+func RecordToProto(r Record) *v2.Record {
+	fields := make([]*v2.RecordField, len(r.Fields))
+	for i, f := range r.Fields {
+		fields[i] = &v2.RecordField{Label: f.Label, Value: ToProto(f.Value)}
+	}
+	return &v2.Record{RecordId: identifierToProto(r.ID), Fields: fields}
+}
+
+// FromProto converts a Ledger API value. A nil value converts to nil.
+func FromProto(pb *v2.Value) (Value, error) {
+	if pb == nil {
+		return nil, nil
+	}
+
+	switch v := pb.Sum.(type) {
+	case *v2.Value_Unit:
+		return Unit{}, nil
+	case *v2.Value_Bool:
+		return Bool(v.Bool), nil
+	case *v2.Value_Int64:
+		return Int64(v.Int64), nil
+	case *v2.Value_Text:
+		return Text(v.Text), nil
+	case *v2.Value_Numeric:
+		return ParseNumeric(v.Numeric)
+	case *v2.Value_Party:
+		return Party(v.Party), nil
+	case *v2.Value_ContractId:
+		return ContractID(v.ContractId), nil
+	case *v2.Value_Date:
+		return Date(v.Date), nil
+	case *v2.Value_Timestamp:
+		return Timestamp(v.Timestamp), nil
+	case *v2.Value_Record:
+		return RecordFromProto(v.Record)
+	case *v2.Value_Variant:
+		if v.Variant == nil {
+			return nil, fmt.Errorf("variant is missing")
+		}
+		value, err := FromProto(v.Variant.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert variant %s: %w", v.Variant.Constructor, err)
+		}
+		return Variant{ID: identifierFromProto(v.Variant.VariantId), Constructor: v.Variant.Constructor, Value: value}, nil
+	case *v2.Value_Enum:
+		if v.Enum == nil {
+			return nil, fmt.Errorf("enum is missing")
+		}
+		return Enum{ID: identifierFromProto(v.Enum.EnumId), Constructor: v.Enum.Constructor}, nil
+	case *v2.Value_List:
+		list := make(List, len(v.List.GetElements()))
+		for i, elem := range v.List.GetElements() {
+			value, err := FromProto(elem)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert list element %d: %w", i, err)
+			}
+			list[i] = value
+		}
+		return list, nil
+	case *v2.Value_Optional:
+		value, err := FromProto(v.Optional.GetValue())
+		if err != nil {
+			return nil, err
+		}
+		return Optional{Value: value}, nil
+	case *v2.Value_TextMap:
+		textMap := make(TextMap, len(v.TextMap.GetEntries()))
+		for i, entry := range v.TextMap.GetEntries() {
+			value, err := FromProto(entry.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert text map entry %s: %w", entry.Key, err)
+			}
+			textMap[i] = TextMapEntry{Key: entry.Key, Value: value}
+		}
+		return textMap, nil
+	case *v2.Value_GenMap:
+		genMap := make(GenMap, len(v.GenMap.GetEntries()))
+		for i, entry := range v.GenMap.GetEntries() {
+			key, err := FromProto(entry.Key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert map key %d: %w", i, err)
+			}
+			value, err := FromProto(entry.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert map value %d: %w", i, err)
+			}
+			genMap[i] = GenMapEntry{Key: key, Value: value}
+		}
+		return genMap, nil
+	default:
+		return nil, fmt.Errorf("unknown value type %T", pb.Sum)
+	}
+}
+
+// RecordFromProto converts a Ledger API record, such as the create arguments of a created event.
+func RecordFromProto(pb *v2.Record) (Record, error) {
+	if pb == nil {
+		return Record{}, fmt.Errorf("record is missing")
+	}
+
+	record := Record{ID: identifierFromProto(pb.RecordId), Fields: make([]Field, len(pb.Fields))}
+	for i, f := range pb.Fields {
+		value, err := FromProto(f.Value)
+		if err != nil {
+			return Record{}, fmt.Errorf("failed to convert field %s: %w", f.Label, err)
+		}
+		record.Fields[i] = Field{Label: f.Label, Value: value}
+	}
+	return record, nil
+}
+
+func identifierToProto(id *Identifier) *v2.Identifier {
+	if id == nil {
+		return nil
+	}
+	return &v2.Identifier{PackageId: id.PackageID, ModuleName: id.ModuleName, EntityName: id.EntityName}
+}
+
+func identifierFromProto(pb *v2.Identifier) *Identifier {
+	if pb == nil {
+		return nil
+	}
+	return &Identifier{PackageID: pb.PackageId, ModuleName: pb.ModuleName, EntityName: pb.EntityName}
+}
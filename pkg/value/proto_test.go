@@ -0,0 +1,94 @@
+package value
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	v2 "github.com/digital-asset/dazl-client/v8/go/api/com/daml/ledger/api/v2"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	id := &Identifier{PackageID: "1220pkg", ModuleName: "Main", EntityName: "Everything"}
+	record := Record{ID: id, Fields: []Field{
+		{Label: "unit", Value: Unit{}},
+		{Label: "bool", Value: Bool(true)},
+		{Label: "int", Value: Int64(-42)},
+		{Label: "text", Value: Text("hello")},
+		{Label: "numeric", Value: Numeric{Unscaled: big.NewInt(-1250), Scale: 10}},
+		{Label: "party", Value: Party("alice::1220aa")},
+		{Label: "contractId", Value: ContractID("00abcd")},
+		{Label: "date", Value: Date(20000)},
+		{Label: "timestamp", Value: Timestamp(1_700_000_000_123_456)},
+		{Label: "variant", Value: Variant{Constructor: "Some", Value: Record{Fields: []Field{{Label: "x", Value: Int64(1)}}}}},
+		{Label: "enum", Value: Enum{ID: &Identifier{ModuleName: "Main", EntityName: "Color"}, Constructor: "Red"}},
+		{Label: "list", Value: List{Int64(1), Int64(2)}},
+		{Label: "none", Value: None()},
+		{Label: "someNone", Value: Some(None())},
+		{Label: "textMap", Value: TextMap{{Key: "b", Value: Int64(2)}, {Key: "a", Value: Int64(1)}}},
+		{Label: "genMap", Value: GenMap{{Key: Party("bob::1220bb"), Value: List{}}}},
+	}}
+
+	pb := ToProto(record)
+	require.Equal(t, "-0.0000001250", pb.GetRecord().Fields[4].Value.GetNumeric())
+	require.Equal(t, "1220pkg", pb.GetRecord().RecordId.PackageId)
+	require.Nil(t, pb.GetRecord().Fields[12].Value.GetOptional().Value)
+	require.NotNil(t, pb.GetRecord().Fields[13].Value.GetOptional().Value.GetOptional())
+	require.Equal(t, "b", pb.GetRecord().Fields[14].Value.GetTextMap().Entries[0].Key)
+
+	converted, err := FromProto(pb)
+	require.NoError(t, err)
+	require.Equal(t, record, converted)
+	require.True(t, proto.Equal(pb, ToProto(converted)))
+
+	require.Nil(t, ToProto(nil))
+	converted, err = FromProto(nil)
+	require.NoError(t, err)
+	require.Nil(t, converted)
+}
+
+func TestToProtoPointer(t *testing.T) {
+	text := Text("hello")
+	record := Record{Fields: []Field{
+		{Label: "text", Value: &text},
+		{Label: "optional", Value: &Optional{Value: Int64(1)}},
+	}}
+
+	pb := ToProto(&record)
+	require.True(t, proto.Equal(ToProto(Record{Fields: []Field{
+		{Label: "text", Value: Text("hello")},
+		{Label: "optional", Value: Some(Int64(1))},
+	}}), pb))
+
+	require.Nil(t, ToProto((*Record)(nil)))
+}
+
+func TestFromProto(t *testing.T) {
+	pb := &v2.Record{Fields: []*v2.RecordField{
+		{Label: "unit", Value: &v2.Value{Sum: &v2.Value_Unit{Unit: &emptypb.Empty{}}}},
+		{Label: "amount", Value: &v2.Value{Sum: &v2.Value_Numeric{Numeric: "12.5000000000"}}},
+	}}
+
+	record, err := RecordFromProto(pb)
+	require.NoError(t, err)
+	require.Nil(t, record.ID)
+	amount, ok := record.Get("amount")
+	require.True(t, ok)
+	require.Equal(t, "12.5000000000", amount.(Numeric).String())
+	require.Equal(t, int32(10), amount.(Numeric).Scale)
+	_, ok = record.Get("missing")
+	require.False(t, ok)
+
+	pb.Fields[1].Value = &v2.Value{Sum: &v2.Value_Numeric{Numeric: "12,5"}}
+	_, err = RecordFromProto(pb)
+	require.EqualError(t, err, `failed to convert field amount: invalid numeric "12,5"`)
+
+	_, err = FromProto(&v2.Value{})
+	require.ErrorContains(t, err, "unknown value type")
+
+	_, err = RecordFromProto(nil)
+	require.ErrorContains(t, err, "record is missing")
+}
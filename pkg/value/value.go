@@ -0,0 +1,180 @@
+// Package value models Daml values explicitly, independently of generated code, with lossless
+// conversion to and from the Ledger API v2.Value.
+package value
+
+import (
+	"strings"
+	"time"
+)
+
+// Value is a Daml value: one of Unit, Bool, Int64, Text, Numeric, Party, ContractID, Date,
+// Timestamp, Record, Variant, Enum, List, Optional, TextMap or GenMap.
+type Value interface {
+	isValue()
+}
+
+// Identifier identifies a Daml record, variant or enum type.
+type Identifier struct {
+	PackageID  string
+	ModuleName string
+	EntityName string
+}
+
+// String formats the identifier as package:module:entity, or module:entity without package ID.
+func (id Identifier) String() string {
+	if id.PackageID == "" {
+		return id.ModuleName + ":" + id.EntityName
+	}
+	return id.PackageID + ":" + id.ModuleName + ":" + id.EntityName
+}
+
+// ParseIdentifier parses an identifier formatted as package:module:entity or module:entity.
+func ParseIdentifier(s string) Identifier {
+	parts := strings.Split(s, ":")
+	switch len(parts) {
+	case 3:
+		return Identifier{PackageID: parts[0], ModuleName: parts[1], EntityName: parts[2]}
+	case 2:
+		return Identifier{ModuleName: parts[0], EntityName: parts[1]}
+	default:
+		return Identifier{EntityName: s}
+	}
+}
+
+type (
+	// Unit is the Daml unit value, ().
+	Unit struct{}
+	// Bool is a Daml Bool.
+	Bool bool
+	// Int64 is a Daml Int.
+	Int64 int64
+	// Text is a Daml Text.
+	Text string
+	// Party is a Daml Party.
+	Party string
+	// ContractID is a Daml ContractId.
+	ContractID string
+	// Date is a Daml Date, the number of days since the Unix epoch.
+	Date int32
+	// Timestamp is a Daml Time, the number of microseconds since the Unix epoch.
+	Timestamp int64
+	// List is a Daml list.
+	List []Value
+)
+
+// Record is a Daml record. The ID is set by the ledger and optional in commands.
+type Record struct {
+	ID     *Identifier
+	Fields []Field
+}
+
+// Field is a labeled field of a record.
+type Field struct {
+	Label string
+	Value Value
+}
+
+// Variant is a Daml variant, Constructor applied to Value.
+type Variant struct {
+	ID          *Identifier
+	Constructor string
+	Value       Value
+}
+
+// Enum is a Daml enum, one of its constructors.
+type Enum struct {
+	ID          *Identifier
+	Constructor string
+}
+
+// Optional is a Daml Optional, None when Value is nil.
+type Optional struct {
+	Value Value
+}
+
+// TextMap is a Daml TextMap, in the order of its entries.
+type TextMap []TextMapEntry
+
+// TextMapEntry is an entry of a TextMap.
+type TextMapEntry struct {
+	Key   string
+	Value Value
+}
+
+// GenMap is a Daml Map, in the order of its entries.
+type GenMap []GenMapEntry
+
+// GenMapEntry is an entry of a GenMap.
+type GenMapEntry struct {
+	Key   Value
+	Value Value
+}
+
+func (Unit) isValue()       {}
+func (Bool) isValue()       {}
+func (Int64) isValue()      {}
+func (Text) isValue()       {}
+func (Numeric) isValue()    {}
+func (Party) isValue()      {}
+func (ContractID) isValue() {}
+func (Date) isValue()       {}
+func (Timestamp) isValue()  {}
+func (Record) isValue()     {}
+func (Variant) isValue()    {}
+func (Enum) isValue()       {}
+func (List) isValue()       {}
+func (Optional) isValue()   {}
+func (TextMap) isValue()    {}
+func (GenMap) isValue()     {}
+
+// NewRecord returns a record of fields without type ID.
+func NewRecord(fields ...Field) Record {
+	return Record{Fields: fields}
+}
+
+// Get returns the value of the field labeled label.
+func (r Record) Get(label string) (Value, bool) {
+	for _, f := range r.Fields {
+		if f.Label == label {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Some returns the optional value v.
+func Some(v Value) Optional {
+	return Optional{Value: v}
+}
+
+// None returns the empty optional value.
+func None() Optional {
+	return Optional{}
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// DateFromTime returns the date of t in UTC.
+func DateFromTime(t time.Time) Date {
+	seconds := t.Unix()
+	days := seconds / secondsPerDay
+	if seconds%secondsPerDay < 0 {
+		days--
+	}
+	return Date(days)
+}
+
+// Time returns the start of the date in UTC.
+func (d Date) Time() time.Time {
+	return time.Unix(int64(d)*secondsPerDay, 0).UTC()
+}
+
+// TimestampFromTime returns the timestamp of t, truncated to microseconds.
+func TimestampFromTime(t time.Time) Timestamp {
+	return Timestamp(t.UnixMicro())
+}
+
+// Time returns the timestamp in UTC.
+func (t Timestamp) Time() time.Time {
+	return time.UnixMicro(int64(t)).UTC()
+}
@@ -0,0 +1,69 @@
+package value
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNumeric(t *testing.T) {
+	tests := []struct {
+		literal  string
+		unscaled int64
+		scale    int32
+		str      string
+	}{
+		{"1.50", 150, 2, "1.50"},
+		{"-12.0000000001", -120000000001, 10, "-12.0000000001"},
+		{"42", 42, 0, "42"},
+		{"7.", 7, 0, "7"},
+		{"-0.5", -5, 1, "-0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.literal, func(t *testing.T) {
+			n, err := ParseNumeric(tt.literal)
+			require.NoError(t, err)
+			require.Equal(t, big.NewInt(tt.unscaled), n.Unscaled)
+			require.Equal(t, tt.scale, n.Scale)
+			require.Equal(t, tt.str, n.String())
+		})
+	}
+
+	for _, literal := range []string{"", "-", ".5", "1.2.3", "1e5", "+1", "0." + strings.Repeat("1", MaxNumericScale+1)} {
+		_, err := ParseNumeric(literal)
+		require.Error(t, err, literal)
+	}
+}
+
+func TestNumericConversions(t *testing.T) {
+	n := NumericFromDecimal(decimal.RequireFromString("3.14159"), 2)
+	require.Equal(t, "3.14", n.String())
+	require.Equal(t, big.NewRat(314, 100), n.Rat())
+	require.True(t, decimal.RequireFromString("3.14").Equal(n.Decimal()))
+
+	require.Equal(t, "0.000", Numeric{Scale: 3}.String())
+	require.Equal(t, "1.0000000000", NewNumeric(big.NewInt(10_000_000_000), 10).String())
+}
+
+func TestDateAndTimestamp(t *testing.T) {
+	day := time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)
+	date := DateFromTime(day)
+	require.Equal(t, Date(19782), date)
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), date.Time())
+	require.Equal(t, Date(-1), DateFromTime(time.Date(1969, 12, 31, 12, 0, 0, 0, time.UTC)))
+
+	instant := time.Date(2024, 2, 29, 12, 30, 0, 123456789, time.UTC)
+	require.Equal(t, instant.Truncate(time.Microsecond), TimestampFromTime(instant).Time())
+}
+
+func TestIdentifier(t *testing.T) {
+	id := ParseIdentifier("1220pkg:Main.Sub:Asset")
+	require.Equal(t, Identifier{PackageID: "1220pkg", ModuleName: "Main.Sub", EntityName: "Asset"}, id)
+	require.Equal(t, "1220pkg:Main.Sub:Asset", id.String())
+	require.Equal(t, "Main:Asset", ParseIdentifier("Main:Asset").String())
+}
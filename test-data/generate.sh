@@ -2,6 +2,11 @@
 
 # This script generates all test data
 
-godaml --dar ./all-kinds-of-1.0.0_lf.dar --output . --go_package codegen_test
+godaml go --dar ./all-kinds-of-1.0.0_lf.dar --output . --go_package codegen_test
 cp ./all_kinds_of_1_0_0.go ../examples/codegen/all_kinds_of_1_0_0.go
 mv ./all_kinds_of_1_0_0.go ./all_kinds_of_1_0_0.go_gen
+
+# Daml-LF 1.x DARs: test-1.0.0_lf1.dar and quickstart-finance-0.0.1_lf1.dar are the Test.dar of SDK 2.9.1 and the
+# quickstart-finance DAR of SDK 2.5.0 from the dazl-client fixtures, as they need an SDK 2.x toolchain to build
+godaml go --dar ./test-1.0.0_lf1.dar --output . --go_package codegen_test
+mv ./test_1_0_0.go ./test_1_0_0.go_gen